@@ -1,19 +1,96 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// Supported values of DB_DRIVER
+const (
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// ConnectDB opens the database selected by DB_DRIVER (mysql by default)
 func ConnectDB() *gorm.DB {
-	// Load environment variables from .env file
-	err := godotenv.Load()
+	// Load environment variables from .env file when there is one
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = DriverMySQL
+	}
+
+	dsn, err := dsnFromEnv(driver)
 	if err != nil {
-		log.Fatal("Error loading .env file")
+		log.Fatal(err)
+	}
+
+	db, err := OpenDB(driver, dsn)
+	if err != nil {
+		log.Fatal("Error connecting to the database:", err)
+	}
+
+	log.Printf("Database connection established (%s).", driver)
+	return db
+}
+
+// OpenDB opens a connection with the given driver and data source name. For sqlite the DSN is a file path
+// or ":memory:".
+func OpenDB(driver, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverMySQL:
+		dialector = mysql.Open(dsn)
+	case DriverSQLite:
+		dialector = sqlite.Open(dsn)
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		// Timestamps are cut to milliseconds, the finest precision every backend stores, so a created or
+		// updated task reads back exactly as it was returned
+		NowFunc: func() time.Time { return time.Now().UTC().Truncate(time.Millisecond) },
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if driver == DriverSQLite {
+		// SQLite allows a single writer, and every connection to ":memory:" would open a new empty database
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// dsnFromEnv builds the data source name of the driver from environment variables
+func dsnFromEnv(driver string) (string, error) {
+	if driver == DriverSQLite {
+		path := os.Getenv("DB_PATH")
+		if path == "" {
+			path = "todo.db"
+		}
+		return path, nil
 	}
 
 	// Read database configuration from environment variables
@@ -24,15 +101,65 @@ func ConnectDB() *gorm.DB {
 	dbName := os.Getenv("DB_NAME")
 
 	if dbUser == "" || dbPassword == "" || dbHost == "" || dbPort == "" || dbName == "" {
-		log.Fatal("Database configuration is not set in environment variables")
+		return "", errors.New("database configuration is not set in environment variables")
 	}
 
-	dsn := dbUser + ":" + dbPassword + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName + "?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatal("Error connecting to the database:", err)
+	switch driver {
+	case DriverMySQL:
+		return dbUser + ":" + dbPassword + "@tcp(" + dbHost + ":" + dbPort + ")/" + dbName + "?charset=utf8mb4&parseTime=True&loc=Local", nil
+	case DriverPostgres:
+		sslMode := os.Getenv("DB_SSLMODE")
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", dbHost, dbPort, dbUser, dbPassword, dbName, sslMode), nil
+	default:
+		return "", fmt.Errorf("unsupported DB_DRIVER %q", driver)
 	}
+}
 
-	log.Println("Database connection established.")
-	return db
+// TrashRetention returns how long deleted tasks stay in the trash before they are purged (TRASH_RETENTION, default 30 days)
+func TrashRetention() time.Duration {
+	return durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+}
+
+// TrashPurgeInterval returns how often the trash is checked for expired tasks (TRASH_PURGE_INTERVAL, default 1 hour)
+func TrashPurgeInterval() time.Duration {
+	return durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+}
+
+// IdempotencyTTL returns how long an Idempotency-Key and its response are kept for retries (IDEMPOTENCY_TTL, default 24 hours)
+func IdempotencyTTL() time.Duration {
+	return durationEnv("IDEMPOTENCY_TTL", 24*time.Hour)
+}
+
+// IdempotencyLease returns how long an Idempotency-Key is held for a request still being handled (IDEMPOTENCY_LEASE, default 5 minutes)
+func IdempotencyLease() time.Duration {
+	return durationEnv("IDEMPOTENCY_LEASE", 5*time.Minute)
+}
+
+// IdempotencyPurgeInterval returns how often expired idempotency keys are deleted (IDEMPOTENCY_PURGE_INTERVAL, default 1 hour)
+func IdempotencyPurgeInterval() time.Duration {
+	return durationEnv("IDEMPOTENCY_PURGE_INTERVAL", time.Hour)
+}
+
+// durationEnv reads a duration such as "720h" from the environment, falling back to def when it is unset or invalid
+func durationEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid %s %q, using %s", key, value, def)
+		return def
+	}
+	return d
+}
+
+// AutoMigrate reports whether pending migrations are applied when the server starts (DB_AUTO_MIGRATE, default false)
+func AutoMigrate() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE"))
+	return enabled
 }
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/migrations"
+	"todo-lists/models"
+	"todo-lists/query"
+	"todo-lists/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenDBSQLite(t *testing.T) {
+	db, err := OpenDB(DriverSQLite, ":memory:")
+	assert.NoError(t, err)
+	migrator, err := migrations.New(db)
+	assert.NoError(t, err)
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+
+	repo := &repositories.TaskRepository{DB: db}
+	deadline := time.Date(2026, 11, 1, 17, 0, 0, 0, time.UTC)
+
+	// Tasks round trip through the repository
+	assert.NoError(t, repo.CreateTask(&entity.Task{Name: "Write tests", Deadline: &deadline, Priority: "high", Status: entity.StatusTodo}))
+	labels := &repositories.LabelRepository{DB: db}
+	label := entity.Label{Name: "work", Color: "#ff0000"}
+	assert.NoError(t, labels.CreateLabel(&label))
+	assert.NoError(t, labels.AttachLabel(1, label.ID))
+	filter, err := query.Parse("label:work AND priority:high")
+	assert.NoError(t, err)
+	page, err := repo.QueryTasks(filter, entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"})
+	assert.NoError(t, err)
+	if assert.Len(t, page.Tasks, 1) {
+		assert.Equal(t, "Write tests", page.Tasks[0].Name)
+		assert.Equal(t, uint(1), page.Tasks[0].Version)
+		assert.True(t, deadline.Equal(*page.Tasks[0].Deadline))
+	}
+
+	// The priority and status columns only accept known values
+	assert.Error(t, db.Create(&models.Task{Name: "Bad priority", Deadline: &deadline, Priority: "urgent", Status: entity.StatusTodo}).Error)
+	assert.Error(t, db.Create(&models.Task{Name: "Bad status", Deadline: &deadline, Priority: "less", Status: "paused"}).Error)
+}
+
+func TestOpenDBUnsupportedDriver(t *testing.T) {
+	_, err := OpenDB("oracle", "")
+	assert.Error(t, err)
+}
+
+func TestDSNFromEnv(t *testing.T) {
+	t.Setenv("DB_PATH", "")
+	dsn, err := dsnFromEnv(DriverSQLite)
+	assert.NoError(t, err)
+	assert.Equal(t, "todo.db", dsn)
+
+	t.Setenv("DB_USER", "todo")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("DB_NAME", "todo_lists")
+	t.Setenv("DB_SSLMODE", "")
+	dsn, err = dsnFromEnv(DriverPostgres)
+	assert.NoError(t, err)
+	assert.Equal(t, "host=localhost port=5432 user=todo password=secret dbname=todo_lists sslmode=disable", dsn)
+
+	dsn, err = dsnFromEnv(DriverMySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "todo:secret@tcp(localhost:5432)/todo_lists?charset=utf8mb4&parseTime=True&loc=Local", dsn)
+
+	t.Setenv("DB_NAME", "")
+	_, err = dsnFromEnv(DriverMySQL)
+	assert.Error(t, err)
+}
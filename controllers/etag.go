@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag formats a task version as a strong entity tag
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// setETag exposes the version of the returned task in the ETag header
+func setETag(ctx *gin.Context, version uint) {
+	ctx.Header("ETag", etag(version))
+}
+
+// ifMatchVersion returns the task version required by the If-Match header, or 0 when any version is
+// acceptable. A header that cannot name a task version can never match, so it responds with 412.
+func ifMatchVersion(ctx *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseUint(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+	if err != nil || version == 0 {
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "If-Match does not match the task version"})
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// notModified reports whether the If-None-Match header already names the current task version
+func notModified(ctx *gin.Context, version uint) bool {
+	header := ctx.GetHeader("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// respondVersionConflict responds with 412 when If-Match named an outdated task version
+func respondVersionConflict(ctx *gin.Context) {
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "Task was modified, fetch the latest version and retry"})
+}
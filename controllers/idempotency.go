@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// replayedHeaders are the response headers stored with an idempotency key and sent again on a replay
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// IdempotencyMiddleware makes POST endpoints safe to retry. A request with an Idempotency-Key header is
+// handled once; retries with the same key and request get the stored response again, marked with an
+// Idempotent-Replayed header. Requests without the header are passed through.
+type IdempotencyMiddleware struct {
+	Service services.IIdempotencyService
+}
+
+// Handle is the gin handler of the middleware
+func (m *IdempotencyMiddleware) Handle(ctx *gin.Context) {
+	key := ctx.GetHeader("Idempotency-Key")
+	if key == "" {
+		ctx.Next()
+		return
+	}
+	if !validIdempotencyKey(key) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 printable ASCII characters"})
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	record, replay, err := m.Service.Begin(key, requestHash(ctx.Request, body))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIdempotencyKeyReused):
+			ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+		case errors.Is(err, services.ErrIdempotencyInProgress):
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+		default:
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Error checking idempotency key"})
+		}
+		return
+	}
+	if replay {
+		for name, value := range record.Header {
+			ctx.Header(name, value)
+		}
+		ctx.Header("Idempotent-Replayed", "true")
+		ctx.Status(record.Status)
+		ctx.Writer.Write(record.Body)
+		ctx.Abort()
+		return
+	}
+
+	// Server errors and panics are not stored, the request can be retried with the same key
+	completed := false
+	defer func() {
+		if !completed {
+			if err := m.Service.Release(key); err != nil {
+				log.Println("Error releasing idempotency key:", err)
+			}
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = recorder
+	ctx.Next()
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	completed = true
+	record.Status = recorder.Status()
+	record.Header = map[string]string{}
+	for _, name := range replayedHeaders {
+		if value := recorder.Header().Get(name); value != "" {
+			record.Header[name] = value
+		}
+	}
+	record.Body = recorder.body.Bytes()
+	if err := m.Service.Complete(record); err != nil {
+		log.Println("Error storing idempotent response:", err)
+	}
+}
+
+// validIdempotencyKey reports whether a key fits the idempotency_keys table and is printable ASCII
+func validIdempotencyKey(key string) bool {
+	if len(key) > entity.MaxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestHash identifies a request by its method, URL and body
+func requestHash(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, request.Method+" "+request.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIIdempotencyService(ctrl)
+	m := IdempotencyMiddleware{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	handled := 0
+	status := http.StatusCreated
+	router := gin.New()
+	router.POST("/tasks", m.Handle, func(ctx *gin.Context) {
+		handled++
+		ctx.Header("Location", "/tasks/7")
+		ctx.JSON(status, gin.H{"id": 7})
+	})
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body))
+		if key != "" {
+			request.Header.Set("Idempotency-Key", key)
+		}
+		router.ServeHTTP(w, request)
+		return w
+	}
+
+	t.Run("First request", func(t *testing.T) {
+		handled = 0
+		var hash string
+		mockService.EXPECT().Begin("retry-1", gomock.Any()).DoAndReturn(func(key, requestHash string) (entity.IdempotencyRecord, bool, error) {
+			hash = requestHash
+			return entity.IdempotencyRecord{Key: key, RequestHash: requestHash}, false, nil
+		}).Times(1)
+		mockService.EXPECT().Complete(gomock.Any()).DoAndReturn(func(record entity.IdempotencyRecord) error {
+			assert.Equal(t, hash, record.RequestHash)
+			assert.Equal(t, http.StatusCreated, record.Status)
+			assert.Equal(t, "/tasks/7", record.Header["Location"])
+			assert.Equal(t, "application/json; charset=utf-8", record.Header["Content-Type"])
+			assert.JSONEq(t, `{"id": 7}`, string(record.Body))
+			return nil
+		}).Times(1)
+
+		w := post("retry-1", `{"name": "Write report"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, handled)
+		assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	})
+
+	t.Run("Same request", func(t *testing.T) {
+		handled = 0
+		hashes := map[string]bool{}
+		mockService.EXPECT().Begin("retry-1", gomock.Any()).DoAndReturn(func(key, requestHash string) (entity.IdempotencyRecord, bool, error) {
+			hashes[requestHash] = true
+			return entity.IdempotencyRecord{
+				Key:    key,
+				Status: http.StatusCreated,
+				Header: map[string]string{"Content-Type": "application/json; charset=utf-8", "Location": "/tasks/7"},
+				Body:   []byte(`{"id":7}`),
+			}, true, nil
+		}).Times(2)
+
+		for i := 0; i < 2; i++ {
+			w := post("retry-1", `{"name": "Write report"}`)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			assert.Equal(t, "/tasks/7", w.Header().Get("Location"))
+			assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+			assert.JSONEq(t, `{"id": 7}`, w.Body.String())
+		}
+		assert.Equal(t, 0, handled)
+		assert.Len(t, hashes, 1)
+	})
+
+	t.Run("Different request", func(t *testing.T) {
+		mockService.EXPECT().Begin("retry-1", gomock.Any()).Return(entity.IdempotencyRecord{}, false, services.ErrIdempotencyKeyReused).Times(1)
+
+		w := post("retry-1", `{"name": "Write tests"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"error": "Idempotency-Key was already used for a different request"}`, w.Body.String())
+	})
+
+	t.Run("Request in progress", func(t *testing.T) {
+		mockService.EXPECT().Begin("retry-1", gomock.Any()).Return(entity.IdempotencyRecord{}, false, services.ErrIdempotencyInProgress).Times(1)
+
+		w := post("retry-1", `{"name": "Write report"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		handled = 0
+		status = http.StatusInternalServerError
+		defer func() { status = http.StatusCreated }()
+		mockService.EXPECT().Begin("retry-2", gomock.Any()).Return(entity.IdempotencyRecord{Key: "retry-2"}, false, nil).Times(1)
+		mockService.EXPECT().Release("retry-2").Return(nil).Times(1)
+
+		w := post("retry-2", `{"name": "Write report"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, 1, handled)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		router.POST("/panics", gin.RecoveryWithWriter(io.Discard), m.Handle, func(ctx *gin.Context) {
+			panic("handler failed")
+		})
+		mockService.EXPECT().Begin("retry-4", gomock.Any()).Return(entity.IdempotencyRecord{Key: "retry-4"}, false, nil).Times(1)
+		mockService.EXPECT().Release("retry-4").Return(nil).Times(1)
+
+		w := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/panics", strings.NewReader(`{}`))
+		request.Header.Set("Idempotency-Key", "retry-4")
+		router.ServeHTTP(w, request)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Without key", func(t *testing.T) {
+		handled = 0
+
+		w := post("", `{"name": "Write report"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, 1, handled)
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		w := post(strings.Repeat("k", entity.MaxIdempotencyKeyLength+1), `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = post("retryé", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().Begin("retry-3", gomock.Any()).Return(entity.IdempotencyRecord{}, false, errors.New("db error")).Times(1)
+
+		w := post("retry-3", `{}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error checking idempotency key"}`, w.Body.String())
+	})
+}
+
+func TestRequestHash(t *testing.T) {
+	request := func(target string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, target, nil)
+	}
+	body := []byte(`{"name": "Write report"}`)
+
+	hash := requestHash(request("/tasks"), body)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, requestHash(request("/tasks"), body))
+	assert.NotEqual(t, hash, requestHash(request("/tasks"), []byte(`{"name": "Write tests"}`)))
+	assert.NotEqual(t, hash, requestHash(request("/lists/1/tasks"), body))
+	assert.NotEqual(t, requestHash(request("/tasks/bulk?tz=UTC"), body), requestHash(request("/tasks/bulk?tz=Europe/Berlin"), body))
+}
@@ -7,9 +7,72 @@ type IController interface {
 	CreateTask(ctx *gin.Context)
 	GetTasks(ctx *gin.Context)
 	GetTaskById(ctx *gin.Context)
+	GetTaskTree(ctx *gin.Context)
+	GetBlockers(ctx *gin.Context)
+	AddBlocker(ctx *gin.Context)
+	RemoveBlocker(ctx *gin.Context)
+	GetPlan(ctx *gin.Context)
 	GetTaskByTag(ctx *gin.Context)
 	UpdateTask(ctx *gin.Context)
+	PatchTask(ctx *gin.Context)
+	BulkTasks(ctx *gin.Context)
+	ExportTasksCSV(ctx *gin.Context)
+	ImportTasks(ctx *gin.Context)
+	ExportTasksICal(ctx *gin.Context)
+	ImportTasksICal(ctx *gin.Context)
+	ExportTasksTodoTxt(ctx *gin.Context)
+	ImportTasksTodoTxt(ctx *gin.Context)
+	ExportListTasksMarkdown(ctx *gin.Context)
+	ImportListTasksMarkdown(ctx *gin.Context)
+	GetFeed(ctx *gin.Context)
+	StartTask(ctx *gin.Context)
+	BlockTask(ctx *gin.Context)
+	CompleteTask(ctx *gin.Context)
+	ReopenTask(ctx *gin.Context)
+	CancelTask(ctx *gin.Context)
 	SearchTasks(ctx *gin.Context)
 	FilterTasksByDeadline(ctx *gin.Context)
 	DeleteTask(ctx *gin.Context)
+	GetOccurrences(ctx *gin.Context)
+	GetTrash(ctx *gin.Context)
+	RestoreTask(ctx *gin.Context)
+	PurgeTask(ctx *gin.Context)
+	CreateListTask(ctx *gin.Context)
+	GetListTasks(ctx *gin.Context)
+	GetListTasksByTag(ctx *gin.Context)
+	SearchListTasks(ctx *gin.Context)
+	FilterListTasksByDeadline(ctx *gin.Context)
+}
+
+// IListController defines the methods that a ListController should implement.
+type IListController interface {
+	CreateList(ctx *gin.Context)
+	GetLists(ctx *gin.Context)
+	GetListById(ctx *gin.Context)
+	UpdateList(ctx *gin.Context)
+	DeleteList(ctx *gin.Context)
+	CreateListFeed(ctx *gin.Context)
+	DeleteListFeed(ctx *gin.Context)
+}
+
+// ILabelController defines the methods that a LabelController should implement.
+type ILabelController interface {
+	CreateLabel(ctx *gin.Context)
+	GetLabels(ctx *gin.Context)
+	GetLabelById(ctx *gin.Context)
+	UpdateLabel(ctx *gin.Context)
+	DeleteLabel(ctx *gin.Context)
+	GetTaskLabels(ctx *gin.Context)
+	AttachLabel(ctx *gin.Context)
+	DetachLabel(ctx *gin.Context)
+}
+
+// IViewController defines the methods that a ViewController should implement.
+type IViewController interface {
+	CreateView(ctx *gin.Context)
+	GetViews(ctx *gin.Context)
+	GetViewById(ctx *gin.Context)
+	UpdateView(ctx *gin.Context)
+	DeleteView(ctx *gin.Context)
+	GetViewTasks(ctx *gin.Context)
 }
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type LabelController struct {
+	Service services.ILabelService
+}
+
+// CreateLabel method creates a new label
+func (c *LabelController) CreateLabel(ctx *gin.Context) {
+	var label entity.Label
+	if err := ctx.ShouldBindJSON(&label); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	label.ID = 0
+
+	if err := c.Service.CreateLabel(&label); err != nil {
+		respondLabelError(ctx, err, "Error creating label")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, label)
+}
+
+// GetLabels method retrieves all labels and responds with JSON
+func (c *LabelController) GetLabels(ctx *gin.Context) {
+	labels, err := c.Service.GetAllLabels()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching labels"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, labels)
+}
+
+// GetLabelById method retrieves a label by ID and responds with JSON
+func (c *LabelController) GetLabelById(ctx *gin.Context) {
+	id, ok := labelIdParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	label, err := c.Service.GetLabelById(id)
+	if err != nil {
+		respondLabelError(ctx, err, "Error fetching label")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, label)
+}
+
+// UpdateLabel method handles renaming or recoloring an existing label by id
+func (c *LabelController) UpdateLabel(ctx *gin.Context) {
+	id, ok := labelIdParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	var label entity.Label
+	if err := ctx.ShouldBindJSON(&label); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	label.ID = uint(id)
+
+	if err := c.Service.UpdateLabel(&label); err != nil {
+		respondLabelError(ctx, err, "Error updating label")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, label)
+}
+
+// DeleteLabel method deletes a label by ID and takes it off every task
+func (c *LabelController) DeleteLabel(ctx *gin.Context) {
+	id, ok := labelIdParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteLabel(id); err != nil {
+		respondLabelError(ctx, err, "Error deleting label")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully"})
+}
+
+// GetTaskLabels method lists the labels of the task given by the route
+func (c *LabelController) GetTaskLabels(ctx *gin.Context) {
+	taskId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	labels, err := c.Service.GetTaskLabels(taskId)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching labels"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": labels})
+}
+
+// AttachLabel method puts the label given as label_id in the body on the task given by the route
+func (c *LabelController) AttachLabel(ctx *gin.Context) {
+	taskId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var body struct {
+		LabelID uint `json:"label_id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := c.Service.AttachLabel(taskId, int(body.LabelID)); err != nil {
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		case errors.Is(err, services.ErrLabelNotFound):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Label not found"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error attaching label"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"task_id": taskId, "label_id": body.LabelID})
+}
+
+// DetachLabel method takes a label off the task given by the route
+func (c *LabelController) DetachLabel(ctx *gin.Context) {
+	taskId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+	labelId, ok := labelIdParam(ctx, "labelId")
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DetachLabel(taskId, labelId); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task does not carry this label"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error detaching label"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Label detached successfully"})
+}
+
+// labelIdParam parses a label ID route parameter, responding with 400 when it is not a number
+func labelIdParam(ctx *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid label ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondLabelError maps label service errors to responses, using 500 with the given message for unknown errors
+func respondLabelError(ctx *gin.Context, err error, message string) {
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Label not found"})
+	case errors.Is(err, services.ErrInvalidLabel):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrLabelExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Label already exists"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
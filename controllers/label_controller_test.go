@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCreateLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockILabelService(ctrl)
+	lc := LabelController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(w *httptest.ResponseRecorder, body string) *gin.Context {
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = &http.Request{
+			Method: http.MethodPost,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			Body: io.NopCloser(bytes.NewBuffer([]byte(body))),
+		}
+		return ginContext
+	}
+
+	t.Run("Successful creation", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		mockService.EXPECT().CreateLabel(gomock.Any()).DoAndReturn(func(label *entity.Label) error {
+			label.ID = 1
+			return nil
+		}).Times(1)
+
+		lc.CreateLabel(newContext(w, `{"name": "work", "color": "#ff0000"}`))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id": 1, "name": "work", "color": "#ff0000"}`, w.Body.String())
+	})
+
+	t.Run("Invalid label", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		mockService.EXPECT().CreateLabel(gomock.Any()).
+			Return(fmt.Errorf("%w: color must be in #rrggbb form", services.ErrInvalidLabel)).Times(1)
+
+		lc.CreateLabel(newContext(w, `{"name": "work", "color": "red"}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid label: color must be in #rrggbb form"}`, w.Body.String())
+	})
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		mockService.EXPECT().CreateLabel(gomock.Any()).Return(services.ErrLabelExists).Times(1)
+
+		lc.CreateLabel(newContext(w, `{"name": "work"}`))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Label already exists"}`, w.Body.String())
+	})
+}
+
+func TestDeleteLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockILabelService(ctrl)
+	lc := LabelController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range []struct {
+		name, id string
+		err      error
+		code     int
+		body     string
+	}{
+		{"Deleted", "1", nil, http.StatusOK, `{"message": "Label deleted successfully"}`},
+		{"Not found", "2", gorm.ErrRecordNotFound, http.StatusNotFound, `{"error": "Label not found"}`},
+		{"Service error", "3", errors.New("db error"), http.StatusInternalServerError, `{"error": "Error deleting label"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ginContext, _ := gin.CreateTestContext(w)
+			ginContext.Params = gin.Params{{Key: "id", Value: tc.id}}
+
+			mockService.EXPECT().DeleteLabel(gomock.Any()).Return(tc.err).Times(1)
+
+			lc.DeleteLabel(ginContext)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.JSONEq(t, tc.body, w.Body.String())
+		})
+	}
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "abc"}}
+
+		lc.DeleteLabel(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid label ID"}`, w.Body.String())
+	})
+}
+
+func TestTaskLabels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockILabelService(ctrl)
+	lc := LabelController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	attach := func(w *httptest.ResponseRecorder, body string) {
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/tasks/1/labels", bytes.NewBufferString(body))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		lc.AttachLabel(ginContext)
+	}
+
+	t.Run("Attach", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mockService.EXPECT().AttachLabel(1, 2).Return(nil).Times(1)
+
+		attach(w, `{"label_id": 2}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"task_id": 1, "label_id": 2}`, w.Body.String())
+	})
+
+	t.Run("Attach unknown label", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mockService.EXPECT().AttachLabel(1, 9).Return(services.ErrLabelNotFound).Times(1)
+
+		attach(w, `{"label_id": 9}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Label not found"}`, w.Body.String())
+	})
+
+	t.Run("Attach without label", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		attach(w, `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid input"}`, w.Body.String())
+	})
+
+	t.Run("List labels", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+		mockService.EXPECT().GetTaskLabels(1).Return([]entity.Label{{ID: 2, Name: "work", Color: "#808080"}}, nil).Times(1)
+
+		lc.GetTaskLabels(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data": [{"id": 2, "name": "work", "color": "#808080"}]}`, w.Body.String())
+	})
+
+	t.Run("Detach label the task does not carry", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "labelId", Value: "2"}}
+		mockService.EXPECT().DetachLabel(1, 2).Return(gorm.ErrRecordNotFound).Times(1)
+
+		lc.DetachLabel(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Task does not carry this label"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ListController struct {
+	Service services.IListService
+}
+
+// CreateList method creates a new list
+func (c *ListController) CreateList(ctx *gin.Context) {
+	var list entity.List
+	if err := ctx.ShouldBindJSON(&list); err != nil || list.Name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := c.Service.CreateList(&list); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating list"})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, list)
+}
+
+// GetLists method retrieves all lists and responds with JSON
+func (c *ListController) GetLists(ctx *gin.Context) {
+	lists, err := c.Service.GetAllLists()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching lists"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, lists)
+}
+
+// GetListById method retrieves a list by ID and responds with JSON
+func (c *ListController) GetListById(ctx *gin.Context) {
+	id, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	list, err := c.Service.GetListById(id)
+	if err != nil {
+		respondListError(ctx, err, "Error fetching list")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, list)
+}
+
+// UpdateList method handles the updating of an existing list by id
+func (c *ListController) UpdateList(ctx *gin.Context) {
+	id, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	var list entity.List
+	if err := ctx.ShouldBindJSON(&list); err != nil || list.Name == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	list.ID = uint(id)
+
+	if err := c.Service.UpdateList(&list); err != nil {
+		respondListError(ctx, err, "Error updating list")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, list)
+}
+
+// DeleteList method deletes a list by ID
+func (c *ListController) DeleteList(ctx *gin.Context) {
+	id, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteList(id); err != nil {
+		if errors.Is(err, services.ErrListNotEmpty) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "List still has tasks"})
+		} else if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting list"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "List deleted successfully"})
+}
+
+// CreateListFeed method creates the calendar feed of a list, replacing its previous feed URL. The token in
+// the URL is the only credential of the feed and is returned this time only.
+func (c *ListController) CreateListFeed(ctx *gin.Context) {
+	id, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	token, err := c.Service.CreateListFeed(id)
+	if err != nil {
+		respondListError(ctx, err, "Error creating feed")
+		return
+	}
+
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	ctx.JSON(http.StatusCreated, gin.H{
+		"list_id": id,
+		"token":   token,
+		"url":     scheme + "://" + ctx.Request.Host + "/feeds/" + token + ".ics",
+	})
+}
+
+// DeleteListFeed method revokes the calendar feed of a list, so its URL stops working
+func (c *ListController) DeleteListFeed(ctx *gin.Context) {
+	id, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.Service.DeleteListFeed(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting feed"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Feed deleted successfully"})
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCreateList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIListService(ctrl)
+	lc := ListController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful creation", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = &http.Request{
+			Method: http.MethodPost,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "Backend"}`))),
+		}
+
+		mockService.EXPECT().CreateList(gomock.Any()).DoAndReturn(func(list *entity.List) error {
+			list.ID = 1
+			return nil
+		}).Times(1)
+
+		lc.CreateList(ginContext)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id": 1, "name": "Backend", "description": ""}`, w.Body.String())
+	})
+
+	t.Run("Missing name", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = &http.Request{
+			Method: http.MethodPost,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"description": "no name"}`))),
+		}
+
+		lc.CreateList(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid input"}`, w.Body.String())
+	})
+}
+
+func TestGetListById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIListService(ctrl)
+	lc := ListController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful retrieval", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().GetListById(1).Return(entity.List{ID: 1, Name: "Backend"}, nil).Times(1)
+
+		lc.GetListById(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": 1, "name": "Backend", "description": ""}`, w.Body.String())
+	})
+
+	t.Run("List not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().GetListById(2).Return(entity.List{}, gorm.ErrRecordNotFound).Times(1)
+
+		lc.GetListById(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+	})
+
+	t.Run("Invalid list ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "abc"}}
+
+		lc.GetListById(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+	})
+}
+
+func TestDeleteList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIListService(ctrl)
+	lc := ListController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful deletion", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().DeleteList(1).Return(nil).Times(1)
+
+		lc.DeleteList(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "List deleted successfully"}`, w.Body.String())
+	})
+
+	t.Run("List still has tasks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().DeleteList(2).Return(services.ErrListNotEmpty).Times(1)
+
+		lc.DeleteList(ginContext)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Error deleting list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockService.EXPECT().DeleteList(3).Return(errors.New("deletion error")).Times(1)
+
+		lc.DeleteList(ginContext)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error deleting list"}`, w.Body.String())
+	})
+}
+
+func TestListFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIListService(ctrl)
+	lc := ListController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Create feed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "http://todo.example.com/lists/1/feed", nil)
+		ginContext.Request.Header.Set("X-Forwarded-Proto", "https")
+
+		mockService.EXPECT().CreateListFeed(1).Return("s3cr3t", nil).Times(1)
+
+		lc.CreateListFeed(ginContext)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"list_id": 1, "token": "s3cr3t", "url": "https://todo.example.com/feeds/s3cr3t.ics"}`, w.Body.String())
+	})
+
+	t.Run("Create feed of missing list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/lists/2/feed", nil)
+
+		mockService.EXPECT().CreateListFeed(2).Return("", gorm.ErrRecordNotFound).Times(1)
+
+		lc.CreateListFeed(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+	})
+
+	t.Run("Delete feed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().DeleteListFeed(1).Return(nil).Times(1)
+
+		lc.DeleteListFeed(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Feed deleted successfully"}`, w.Body.String())
+	})
+
+	t.Run("Delete missing feed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockService.EXPECT().DeleteListFeed(3).Return(gorm.ErrRecordNotFound).Times(1)
+
+		lc.DeleteListFeed(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Feed not found"}`, w.Body.String())
+	})
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/query"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// bulkItem is the response for one operation of a bulk request
+type bulkItem struct {
+	Index  int          `json:"index"`
+	Op     string       `json:"op"`
+	ID     uint         `json:"id,omitempty"`
+	Status int          `json:"status"`
+	Task   *entity.Task `json:"task,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// BulkTasks method applies a batch of operations, or an action to every task a filter matches, in one
+// transaction. Dates in the filter are days in the time zone of the tz parameter. An atomic request that
+// fails responds with the status of the failed operation and its index; otherwise every operation reports
+// its own status.
+func (c *TaskController) BulkTasks(ctx *gin.Context) {
+	var request entity.BulkRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+
+	results, err := c.Service.BulkTasks(request, loc)
+	if err != nil {
+		var bulkErr *services.BulkError
+		var queryErr *query.Error
+		switch {
+		case errors.As(err, &bulkErr):
+			status, message := bulkErrorResponse(bulkErr.Err)
+			ctx.JSON(status, gin.H{"error": message, "index": bulkErr.Index})
+		case errors.As(err, &queryErr):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error(), "position": queryErr.Pos})
+		case errors.Is(err, services.ErrInvalidBulk):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error applying bulk request"})
+		}
+		return
+	}
+
+	items := make([]bulkItem, 0, len(results))
+	failed := 0
+	for _, result := range results {
+		item := bulkItem{Index: result.Index, Op: result.Op, ID: result.ID, Status: http.StatusOK, Task: result.Task}
+		if result.Err != nil {
+			item.Status, item.Error = bulkErrorResponse(result.Err)
+			failed++
+		} else if result.Op == entity.BulkCreate {
+			item.Status = http.StatusCreated
+		}
+		items = append(items, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": items, "succeeded": len(items) - failed, "failed": failed})
+}
+
+// bulkErrorResponse maps the error of a bulk operation to a status and message. The operation is part of the
+// request body, so tasks it cannot be applied to respond 422 and stale versions 409.
+func bulkErrorResponse(err error) (int, string) {
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		return http.StatusNotFound, "Task not found"
+	case errors.Is(err, services.ErrVersionConflict):
+		return http.StatusConflict, "Task was modified, fetch the latest version and retry"
+	case errors.Is(err, services.ErrIllegalTransition):
+		return http.StatusConflict, "Task cannot move to " + entity.StatusDone
+	case errors.Is(err, services.ErrBlocked):
+		return http.StatusConflict, "Task is waiting for its blockers"
+	case errors.Is(err, services.ErrInvalidPatch):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, services.ErrPatchFailed), errors.Is(err, services.ErrReadOnlyField), errors.Is(err, services.ErrInvalidTask):
+		return http.StatusUnprocessableEntity, err.Error()
+	case errors.Is(err, services.ErrInvalidStatus):
+		return http.StatusUnprocessableEntity, "Invalid task status"
+	case errors.Is(err, services.ErrInvalidPriority):
+		return http.StatusUnprocessableEntity, "Priority must be one of less, medium, high"
+	case errors.Is(err, services.ErrInvalidRecurrence):
+		return http.StatusUnprocessableEntity, "Invalid recurrence rule"
+	case errors.Is(err, services.ErrListNotFound):
+		return http.StatusUnprocessableEntity, "List not found"
+	case errors.Is(err, services.ErrParentNotFound):
+		return http.StatusUnprocessableEntity, "Parent task not found"
+	case errors.Is(err, services.ErrTaskCycle):
+		return http.StatusUnprocessableEntity, "Task cannot be a subtask of itself"
+	}
+	return http.StatusInternalServerError, "Error applying operation"
+}
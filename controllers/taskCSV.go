@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportTasksCSV method responds with the tasks an export filter selects as a CSV file, with its times in
+// the time zone of the tz parameter
+func (c *TaskController) ExportTasksCSV(ctx *gin.Context) {
+	filter, ok := exportFilterParams(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	tasks, ok := c.exportTasks(ctx, filter, loc)
+	if !ok {
+		return
+	}
+
+	var body bytes.Buffer
+	if err := services.WriteTasksCSV(&body, tasks, loc); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting tasks"})
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="tasks.csv"`)
+	ctx.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
+
+// ImportTasks method creates tasks from a CSV file, sent as the request body or as the file field of a
+// multipart form. map[<field>]=<header> parameters name the column of a field, dates without a time zone
+// are read in the time zone of the tz parameter, and dry_run=true checks the file without storing anything.
+// When any row is rejected nothing is imported and the response lists the rejected rows with 422.
+func (c *TaskController) ImportTasks(ctx *gin.Context) {
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+	file, ok := importFile(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	options := entity.CSVImport{Columns: ctx.QueryMap("map"), DryRun: dryRun, Location: loc}
+	tasks, rowErrors, err := c.Service.ImportTasksCSV(file, options)
+	if err != nil {
+		respondImportError(ctx, err)
+		return
+	}
+	if len(rowErrors) > 0 {
+		respondRejectedRows(ctx, rowErrors)
+		return
+	}
+
+	if dryRun {
+		ctx.JSON(http.StatusOK, gin.H{"data": tasks, "dry_run": true})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": tasks, "imported": len(tasks)})
+}
@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 	"todo-lists/entity"
+	"todo-lists/query"
 	"todo-lists/services"
 
 	"github.com/gin-gonic/gin"
@@ -25,17 +29,72 @@ func (c *TaskController) CreateTask(ctx *gin.Context) {
 	}
 
 	if err := c.Service.CreateTask(&task); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating task"})
+		if errors.Is(err, services.ErrListNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "List not found"})
+		} else if errors.Is(err, services.ErrInvalidStatus) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task status"})
+		} else if errors.Is(err, services.ErrInvalidPriority) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be one of less, medium, high"})
+		} else if errors.Is(err, services.ErrInvalidRecurrence) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence rule"})
+		} else if errors.Is(err, services.ErrParentNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent task not found"})
+		} else if errors.Is(err, services.ErrTaskCycle) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Task cannot be a subtask of itself"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating task"})
+		}
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, task)
+	respondCreated(ctx, task)
 }
 
-// GetTasks method retrieves all tasks and responds with JSON
+// GetTasks method retrieves all tasks, optionally filtered by ?status=, and responds with JSON
 func (c *TaskController) GetTasks(ctx *gin.Context) {
-	tasks, err := c.Service.GetAllTasks()
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	if q, ok := ctx.GetQuery("q"); ok {
+		c.queryTasks(ctx, q, page)
+		return
+	}
+
+	tasks, err := c.Service.GetAllTasks(ctx.Query("status"), page)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatus) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task status"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tasks"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// queryTasks responds with the tasks that match the filter expression q, with its dates in the time zone of
+// the tz parameter. The status parameter cannot be combined with it, status:<value> is written inside the
+// expression instead.
+func (c *TaskController) queryTasks(ctx *gin.Context, q string, page entity.PageRequest) {
+	if ctx.Query("status") != "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Use status:<value> inside q instead of the status parameter"})
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.QueryTasks(q, loc, page)
 	if err != nil {
+		var queryErr *query.Error
+		if errors.As(err, &queryErr) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error(), "position": queryErr.Pos})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tasks"})
 		return
 	}
@@ -62,20 +121,55 @@ func (c *TaskController) GetTaskById(ctx *gin.Context) {
 		return
 	}
 
+	setETag(ctx, task.Version)
+	if notModified(ctx, task.Version) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, task)
 }
 
-// GetTaskByTag retrieves a task by its Tag name and responds with JSON
+// GetTaskTree method retrieves a task with its subtasks nested to any depth and the roll-up of their progress
+func (c *TaskController) GetTaskTree(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	tree, err := c.Service.GetTaskTree(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching task tree"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tree)
+}
+
+// GetTaskByTag retrieves the tasks that carry any of the comma separated labels in the route, or all of them
+// with ?match=all, and responds with JSON
 func (c *TaskController) GetTaskByTag(ctx *gin.Context) {
-	tag := ctx.Param("tag")
+	labels, matchAll, ok := labelParams(ctx)
+	if !ok {
+		return
+	}
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
 
-	tasks, err := c.Service.GetTasksByTag(tag)
+	tasks, err := c.Service.GetTasksByTag(labels, matchAll, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching tasks"})
 		return
 	}
 
-	if len(tasks) == 0 {
+	if len(tasks.Tasks) == 0 {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "No tasks found with the specified tag"})
 		return
 	}
@@ -102,29 +196,160 @@ func (c *TaskController) UpdateTask(ctx *gin.Context) {
 	// Set the ID on the task to ensure we update the correct one
 	task.ID = uint(id)
 
+	// Only the If-Match header decides which version the update applies to
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+	task.Version = version
+
 	if err := c.Service.UpdateTask(&task); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else if errors.Is(err, services.ErrVersionConflict) {
+			respondVersionConflict(ctx)
+		} else if errors.Is(err, services.ErrListNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "List not found"})
+		} else if errors.Is(err, services.ErrInvalidPriority) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be one of less, medium, high"})
+		} else if errors.Is(err, services.ErrInvalidRecurrence) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence rule"})
+		} else if errors.Is(err, services.ErrParentNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent task not found"})
+		} else if errors.Is(err, services.ErrTaskCycle) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Task cannot be a subtask of itself"})
 		} else {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating task"})
 		}
 		return
 	}
 
+	setETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
+}
+
+// PatchTask method applies a JSON Merge Patch or JSON Patch document to an existing task. The format is
+// chosen by the Content-Type header; plain application/json is treated as a merge patch.
+func (c *TaskController) PatchTask(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	patchType := ctx.ContentType()
+	if patchType == "application/json" {
+		patchType = services.MergePatchType
+	}
+
+	patch, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	task, err := c.Service.PatchTask(id, version, patchType, patch)
+	if err != nil {
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		case errors.Is(err, services.ErrVersionConflict):
+			respondVersionConflict(ctx)
+		case errors.Is(err, services.ErrUnsupportedPatch):
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + services.MergePatchType + " or " + services.JSONPatchType})
+		case errors.Is(err, services.ErrInvalidPatch):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPatchFailed), errors.Is(err, services.ErrReadOnlyField), errors.Is(err, services.ErrInvalidTask):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrListNotFound):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "List not found"})
+		case errors.Is(err, services.ErrParentNotFound):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Parent task not found"})
+		case errors.Is(err, services.ErrTaskCycle):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Task cannot be a subtask of itself"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating task"})
+		}
+		return
+	}
+
+	setETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
+}
+
+// StartTask method moves a task to in_progress
+func (c *TaskController) StartTask(ctx *gin.Context) {
+	c.transitionTask(ctx, entity.StatusInProgress)
+}
+
+// BlockTask method marks a task as blocked
+func (c *TaskController) BlockTask(ctx *gin.Context) {
+	c.transitionTask(ctx, entity.StatusBlocked)
+}
+
+// CompleteTask method marks a task as done and records its completion time
+func (c *TaskController) CompleteTask(ctx *gin.Context) {
+	c.transitionTask(ctx, entity.StatusDone)
+}
+
+// ReopenTask method moves a done or cancelled task back to todo
+func (c *TaskController) ReopenTask(ctx *gin.Context) {
+	c.transitionTask(ctx, entity.StatusTodo)
+}
+
+// CancelTask method marks a task as cancelled
+func (c *TaskController) CancelTask(ctx *gin.Context) {
+	c.transitionTask(ctx, entity.StatusCancelled)
+}
+
+// transitionTask moves the task given by the route to status and responds with the updated task
+func (c *TaskController) transitionTask(ctx *gin.Context, status string) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := c.Service.TransitionTask(id, status)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else if errors.Is(err, services.ErrIllegalTransition) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Task cannot move to " + status})
+		} else if errors.Is(err, services.ErrBlocked) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Task is waiting for its blockers"})
+		} else if errors.Is(err, services.ErrVersionConflict) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Task was modified concurrently, retry"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating task status"})
+		}
+		return
+	}
+
+	setETag(ctx, task.Version)
 	ctx.JSON(http.StatusOK, task)
 }
 
 // SearchTasks handles searching for tasks by keyword in the name
 func (c *TaskController) SearchTasks(ctx *gin.Context) {
 	keyword := ctx.Query("keyword")
+	page, ok := searchPageParams(ctx)
+	if !ok {
+		return
+	}
 
-	tasks, err := c.Service.SearchTasksByName(keyword)
+	tasks, err := c.Service.SearchTasksByName(keyword, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching tasks"})
 		return
 	}
 
-	if len(tasks) == 0 {
+	if len(tasks.Tasks) == 0 {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "No tasks found matching with name"})
 		return
 	}
@@ -132,7 +357,8 @@ func (c *TaskController) SearchTasks(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tasks)
 }
 
-// FilterTasksByDeadline method filters the tasks by a deadline within a specified date range
+// FilterTasksByDeadline method filters the stored tasks by a deadline within a specified date range. Virtual
+// occurrences of recurring tasks are left to GetOccurrences.
 func (c *TaskController) FilterTasksByDeadline(ctx *gin.Context) {
 	startDateStr := ctx.Query("start")
 	endDateStr := ctx.Query("end")
@@ -150,13 +376,18 @@ func (c *TaskController) FilterTasksByDeadline(ctx *gin.Context) {
 		return
 	}
 
-	tasks, err := c.Service.FilterTasksByDeadline(startDate, endDate)
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.FilterTasksByDeadline(startDate, endDate, page)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error filtering tasks"})
 		return
 	}
 
-	if len(tasks) == 0 {
+	if len(tasks.Tasks) == 0 {
 		ctx.JSON(http.StatusNotFound, gin.H{"message": "No tasks found in the specified date range"})
 		return
 	}
@@ -164,7 +395,35 @@ func (c *TaskController) FilterTasksByDeadline(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tasks)
 }
 
-// DeleteTask method deletes a task by ID
+// GetOccurrences lists the tasks due between the start and end dates, including the not yet created
+// occurrences of recurring tasks
+func (c *TaskController) GetOccurrences(ctx *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", ctx.Query("start"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", ctx.Query("end"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
+		return
+	}
+
+	occurrences, err := c.Service.GetOccurrences(startDate, endDate)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManyOccurrences) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Too many occurrences, narrow the date range"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error expanding occurrences"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": occurrences})
+}
+
+// DeleteTask method moves a task to the trash by ID
 func (c *TaskController) DeleteTask(ctx *gin.Context) {
 	idStr := ctx.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -173,11 +432,315 @@ func (c *TaskController) DeleteTask(ctx *gin.Context) {
 		return
 	}
 
-	err = c.Service.DeleteTask(id)
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = c.Service.DeleteTask(id, version)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting task"})
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else if errors.Is(err, services.ErrVersionConflict) {
+			respondVersionConflict(ctx)
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting task"})
+		}
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
+
+// GetTrash method retrieves a page of deleted tasks and responds with JSON
+func (c *TaskController) GetTrash(ctx *gin.Context) {
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.GetTrash(page)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching trash"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// RestoreTask method moves a deleted task out of the trash
+func (c *TaskController) RestoreTask(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	task, err := c.Service.RestoreTask(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found in trash"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error restoring task"})
+		}
+		return
+	}
+
+	setETag(ctx, task.Version)
+	ctx.JSON(http.StatusOK, task)
+}
+
+// PurgeTask method permanently deletes a task from the trash
+func (c *TaskController) PurgeTask(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	if err := c.Service.PurgeTask(id); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found in trash"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting task"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task permanently deleted"})
+}
+
+// CreateListTask method creates a new task inside the list given by the route
+func (c *TaskController) CreateListTask(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	var task entity.Task
+	if err := ctx.ShouldBindJSON(&task); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	task.ListID = uint(listId)
+
+	if err := c.Service.CreateTask(&task); err != nil {
+		if errors.Is(err, services.ErrListNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		} else if errors.Is(err, services.ErrInvalidStatus) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task status"})
+		} else if errors.Is(err, services.ErrInvalidPriority) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be one of less, medium, high"})
+		} else if errors.Is(err, services.ErrInvalidRecurrence) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence rule"})
+		} else if errors.Is(err, services.ErrParentNotFound) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent task not found"})
+		} else if errors.Is(err, services.ErrTaskCycle) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Task cannot be a subtask of itself"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating task"})
+		}
+		return
+	}
+
+	respondCreated(ctx, task)
+}
+
+// GetListTasks method retrieves all tasks of a list and responds with JSON
+func (c *TaskController) GetListTasks(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.GetTasksByListId(listId, page)
+	if err != nil {
+		respondListError(ctx, err, "Error fetching tasks")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// GetListTasksByTag retrieves the tasks of a list that carry any of the comma separated labels in the route, or
+// all of them with ?match=all, and responds with JSON
+func (c *TaskController) GetListTasksByTag(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+	labels, matchAll, ok := labelParams(ctx)
+	if !ok {
+		return
+	}
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.GetListTasksByTag(listId, labels, matchAll, page)
+	if err != nil {
+		respondListError(ctx, err, "Error fetching tasks")
+		return
+	}
+
+	if len(tasks.Tasks) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "No tasks found with the specified tag"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// SearchListTasks handles searching the tasks of a list by keyword in the name
+func (c *TaskController) SearchListTasks(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+	page, ok := searchPageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.SearchListTasksByName(listId, ctx.Query("keyword"), page)
+	if err != nil {
+		respondListError(ctx, err, "Error searching tasks")
+		return
+	}
+
+	if len(tasks.Tasks) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "No tasks found matching with name"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// FilterListTasksByDeadline method filters the tasks of a list by a deadline within a specified date range
+func (c *TaskController) FilterListTasksByDeadline(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", ctx.Query("start"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", ctx.Query("end"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
+		return
+	}
+
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.FilterListTasksByDeadline(listId, startDate, endDate, page)
+	if err != nil {
+		respondListError(ctx, err, "Error filtering tasks")
+		return
+	}
+
+	if len(tasks.Tasks) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"message": "No tasks found in the specified date range"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// respondCreated responds with 201 and the created task as stored, pointing the Location header at it
+func respondCreated(ctx *gin.Context, task entity.Task) {
+	ctx.Header("Location", "/tasks/"+strconv.FormatUint(uint64(task.ID), 10))
+	setETag(ctx, task.Version)
+	ctx.JSON(http.StatusCreated, task)
+}
+
+// listIdParam parses the list ID route parameter, responding with 400 when it is not a number
+func listIdParam(ctx *gin.Context) (int, bool) {
+	listId, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list ID"})
+		return 0, false
+	}
+	return listId, true
+}
+
+// respondListError responds with 404 when the list is missing and 500 with the given message otherwise
+func respondListError(ctx *gin.Context, err error, message string) {
+	if err == gorm.ErrRecordNotFound {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
+// labelParams splits the comma separated label names of the tag route parameter and parses the match query
+// parameter, which is any (the default) or all, responding with 400 when they are invalid
+func labelParams(ctx *gin.Context) ([]string, bool, bool) {
+	var labels []string
+	for _, name := range strings.Split(ctx.Param("tag"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			labels = append(labels, name)
+		}
+	}
+	if len(labels) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "At least one label is required"})
+		return nil, false, false
+	}
+
+	switch ctx.DefaultQuery("match", "any") {
+	case "any":
+		return labels, false, true
+	case "all":
+		return labels, true, true
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Match must be any or all"})
+		return nil, false, false
+	}
+}
+
+// timeZoneParam loads the IANA time zone named by the tz query parameter, UTC when there is none, responding
+// with 400 when it is unknown
+func timeZoneParam(ctx *gin.Context) (*time.Location, bool) {
+	name := ctx.Query("tz")
+	if name == "" {
+		return time.UTC, true
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unknown time zone"})
+		return nil, false
+	}
+	return loc, true
+}
+
+// pageParams parses the limit, sort and cursor query parameters, responding with 400 when they are invalid
+func pageParams(ctx *gin.Context) (entity.PageRequest, bool) {
+	page, err := entity.ParsePageRequest(ctx.Query("limit"), ctx.Query("sort"), ctx.Query("cursor"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return entity.PageRequest{}, false
+	}
+	return page, true
+}
+
+// searchPageParams is pageParams for search endpoints, whose results are sorted by relevance by default
+func searchPageParams(ctx *gin.Context) (entity.PageRequest, bool) {
+	page, err := entity.ParseSearchPageRequest(ctx.Query("limit"), ctx.Query("sort"), ctx.Query("cursor"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return entity.PageRequest{}, false
+	}
+	return page, true
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetBlockers method lists the tasks the task given by the route waits for
+func (c *TaskController) GetBlockers(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	blockers, err := c.Service.GetBlockers(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching blockers"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": blockers})
+}
+
+// AddBlocker method makes the task given by the route wait for the task given as blocker_id in the body
+func (c *TaskController) AddBlocker(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var body struct {
+		BlockerID uint `json:"blocker_id" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := c.Service.AddBlocker(id, int(body.BlockerID)); err != nil {
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		case errors.Is(err, services.ErrBlockerNotFound):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Blocker task not found"})
+		case errors.Is(err, services.ErrDependencyCycle):
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Dependency would create a cycle"})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error adding blocker"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, entity.Dependency{TaskID: uint(id), BlockerID: body.BlockerID})
+}
+
+// RemoveBlocker method deletes the dependency of the task given by the route on a blocker
+func (c *TaskController) RemoveBlocker(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+	blockerId, err := strconv.Atoi(ctx.Param("blockerId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blocker ID"})
+		return
+	}
+
+	if err := c.Service.RemoveBlocker(id, blockerId); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Dependency not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error removing blocker"})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Blocker removed"})
+}
+
+// GetPlan method responds with the open tasks in an order that respects their dependencies, optionally
+// only those of the list given by list_id
+func (c *TaskController) GetPlan(ctx *gin.Context) {
+	listId := 0
+	if value := ctx.Query("list_id"); value != "" {
+		var err error
+		if listId, err = strconv.Atoi(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list ID"})
+			return
+		}
+	}
+
+	plan, err := c.Service.GetPlan(listId)
+	if err != nil {
+		respondListError(ctx, err, "Error planning tasks")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": plan})
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFilterParams reads the tasks an export selects from the parameters of the endpoints that filter by
+// each of them: list_id, q and status like GET /tasks, tag (comma separated labels) and match like the tag
+// endpoint, keyword, and the start and end dates like the filter endpoint. It responds with 400 when one is
+// invalid.
+func exportFilterParams(ctx *gin.Context) (entity.TaskFilter, bool) {
+	filter := entity.TaskFilter{Query: ctx.Query("q"), Status: ctx.Query("status"), Keyword: ctx.Query("keyword")}
+	listId, ok := listIdQuery(ctx)
+	if !ok {
+		return entity.TaskFilter{}, false
+	}
+	filter.ListID = listId
+	for _, name := range strings.Split(ctx.Query("tag"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			filter.Labels = append(filter.Labels, name)
+		}
+	}
+	switch ctx.DefaultQuery("match", "any") {
+	case "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Match must be any or all"})
+		return entity.TaskFilter{}, false
+	}
+
+	if start := ctx.Query("start"); start != "" {
+		date, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
+			return entity.TaskFilter{}, false
+		}
+		filter.Start = &date
+	}
+	if end := ctx.Query("end"); end != "" {
+		date, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
+			return entity.TaskFilter{}, false
+		}
+		filter.End = &date
+	}
+	return filter, true
+}
+
+// listIdQuery parses the optional list_id query parameter, 0 when it is missing, responding with 400 when it
+// is not a list ID
+func listIdQuery(ctx *gin.Context) (uint, bool) {
+	value := ctx.Query("list_id")
+	if value == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid list ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// exportTasks retrieves the tasks of an export, responding with the error when that fails
+func (c *TaskController) exportTasks(ctx *gin.Context, filter entity.TaskFilter, loc *time.Location) ([]entity.Task, bool) {
+	tasks, err := c.Service.ExportTasks(filter, loc)
+	if err != nil {
+		respondExportError(ctx, err)
+		return nil, false
+	}
+	return tasks, true
+}
+
+// respondExportError responds with 400 for an invalid query or status and 500 for any other error
+func respondExportError(ctx *gin.Context, err error) {
+	var queryErr *query.Error
+	if errors.As(err, &queryErr) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error(), "position": queryErr.Pos})
+	} else if errors.Is(err, services.ErrInvalidStatus) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task status"})
+	} else {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting tasks"})
+	}
+}
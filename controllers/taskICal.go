@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// icalContentType is the media type of iCalendar responses
+const icalContentType = "text/calendar; charset=utf-8"
+
+// ExportTasksICal method responds with the tasks an export filter selects as an iCalendar file, as VTODO
+// components or, with component=event, as VEVENT components at their deadlines
+func (c *TaskController) ExportTasksICal(ctx *gin.Context) {
+	filter, ok := exportFilterParams(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	export, ok := icalExportParams(ctx)
+	if !ok {
+		return
+	}
+	tasks, ok := c.exportTasks(ctx, filter, loc)
+	if !ok {
+		return
+	}
+
+	export.Name = "Tasks"
+	var body bytes.Buffer
+	if err := services.WriteTasksICal(&body, tasks, export); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting tasks"})
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="tasks.ics"`)
+	ctx.Data(http.StatusOK, icalContentType, body.Bytes())
+}
+
+// GetFeed method responds with the calendar feed of a list, named by its token with an optional .ics
+// extension. The feed is read-only and needs no other credentials, so calendar clients can subscribe to it.
+func (c *TaskController) GetFeed(ctx *gin.Context) {
+	export, ok := icalExportParams(ctx)
+	if !ok {
+		return
+	}
+	list, tasks, err := c.Service.GetFeedTasks(strings.TrimSuffix(ctx.Param("token"), ".ics"))
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Feed not found"})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+		}
+		return
+	}
+
+	export.Name = list.Name
+	export.Refresh = services.FeedRefresh
+	var body bytes.Buffer
+	if err := services.WriteTasksICal(&body, tasks, export); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+		return
+	}
+	ctx.Header("Cache-Control", "private, max-age=0")
+	ctx.Data(http.StatusOK, icalContentType, body.Bytes())
+}
+
+// icalExportParams reads the component parameter, todo (the default) or event, responding with 400 when it
+// is neither
+func icalExportParams(ctx *gin.Context) (entity.ICalExport, bool) {
+	switch ctx.DefaultQuery("component", "todo") {
+	case "todo":
+		return entity.ICalExport{}, true
+	case "event":
+		return entity.ICalExport{Events: true}, true
+	}
+	ctx.JSON(http.StatusBadRequest, gin.H{"error": "Component must be todo or event"})
+	return entity.ICalExport{}, false
+}
+
+// ImportTasksICal method creates or updates tasks from the VTODO components of an iCalendar file, sent as the
+// request body or as the file field of a multipart form. A VTODO with the UID of a task updates it; the
+// others create tasks in the list of the list_id parameter, if any. Floating times and dates are read in
+// the time zone of the tz parameter, and dry_run=true checks the file without storing anything. When any
+// VTODO is rejected nothing is imported and the response lists them, by the line of their BEGIN, with 422.
+func (c *TaskController) ImportTasksICal(ctx *gin.Context) {
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+	listId, ok := listIdQuery(ctx)
+	if !ok {
+		return
+	}
+	file, ok := importFile(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	options := entity.ICalImport{ListID: listId, DryRun: dryRun, Location: loc}
+	result, rowErrors, err := c.Service.ImportTasksICal(file, options)
+	if err != nil {
+		respondImportError(ctx, err)
+		return
+	}
+	if len(rowErrors) > 0 {
+		respondRejectedRows(ctx, rowErrors)
+		return
+	}
+
+	response := gin.H{"data": result.Tasks, "created": result.Created, "updated": result.Updated}
+	if dryRun {
+		response["dry_run"] = true
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
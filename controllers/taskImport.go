@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importRowItem is the response for a row an import rejected
+type importRowItem struct {
+	Line  int    `json:"line"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+}
+
+// importFile returns the file of an import: the file field of a multipart form, or else the request body. It
+// responds with 400 when a form has no file.
+func importFile(ctx *gin.Context) (io.ReadCloser, bool) {
+	if ctx.ContentType() != "multipart/form-data" {
+		return ctx.Request.Body, true
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Missing file field"})
+		return nil, false
+	}
+	upload, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error importing tasks"})
+		return nil, false
+	}
+	return upload, true
+}
+
+// dryRunParam parses the dry_run query parameter, responding with 400 when it is not a boolean
+func dryRunParam(ctx *gin.Context) (bool, bool) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Dry run must be true or false"})
+		return false, false
+	}
+	return dryRun, true
+}
+
+// respondImportError responds with 400 for a file an import cannot read and 500 for any other error
+func respondImportError(ctx *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidImport) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	} else if errors.Is(err, services.ErrListNotFound) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "List not found"})
+	} else {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error importing tasks"})
+	}
+}
+
+// respondRejectedRows responds with 422 and the rows an import rejected, of which none were imported
+func respondRejectedRows(ctx *gin.Context, rowErrors []entity.ImportRowError) {
+	items := make([]importRowItem, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		items = append(items, importRowItem{Line: rowErr.Line, Field: rowErr.Field, Error: importErrorMessage(rowErr.Err)})
+	}
+	ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Rows were rejected, nothing was imported", "errors": items})
+}
+
+// importErrorMessage describes why an import rejected a row, like a bulk operation that stores the task
+func importErrorMessage(err error) string {
+	if errors.Is(err, services.ErrInvalidImport) {
+		return err.Error()
+	}
+	_, message := bulkErrorResponse(err)
+	return message
+}
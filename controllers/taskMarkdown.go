@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ExportListTasksMarkdown method responds with the tasks of the list given by the route as a Markdown
+// document of task lists grouped by label, with due dates in the time zone of the tz parameter. The other
+// parameters of the exports narrow down the tasks.
+func (c *TaskController) ExportListTasksMarkdown(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+	filter, ok := exportFilterParams(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	list, tasks, err := c.Service.ExportListTasks(listId, filter, loc)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		} else {
+			respondExportError(ctx, err)
+		}
+		return
+	}
+
+	var body bytes.Buffer
+	if err := services.WriteTasksMarkdown(&body, list.Name, tasks, loc); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting tasks"})
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="tasks.md"`)
+	ctx.Data(http.StatusOK, "text/markdown; charset=utf-8", body.Bytes())
+}
+
+// ImportListTasksMarkdown method reconciles the list given by the route with the task list items of a
+// Markdown document, sent as the request body or as the file field of a multipart form. Items with the
+// comment of a task update it and the others create tasks. Due dates without a time zone are read in the
+// time zone of the tz parameter, and dry_run=true checks the document without storing anything. When any
+// item is rejected nothing is imported and the response lists them with 422.
+func (c *TaskController) ImportListTasksMarkdown(ctx *gin.Context) {
+	listId, ok := listIdParam(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+	file, ok := importFile(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	options := entity.MarkdownImport{ListID: uint(listId), DryRun: dryRun, Location: loc}
+	result, rowErrors, err := c.Service.ImportTasksMarkdown(file, options)
+	if err != nil {
+		if errors.Is(err, services.ErrListNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "List not found"})
+		} else {
+			respondImportError(ctx, err)
+		}
+		return
+	}
+	if len(rowErrors) > 0 {
+		respondRejectedRows(ctx, rowErrors)
+		return
+	}
+
+	response := gin.H{"data": result.Tasks, "created": result.Created, "updated": result.Updated}
+	if dryRun {
+		response["dry_run"] = true
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+	ctx.JSON(http.StatusCreated, response)
+}
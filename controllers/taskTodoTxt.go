@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportTasksTodoTxt method responds with the tasks an export filter selects as a todo.txt file, with their
+// lists as projects, their labels as contexts and their due dates in the time zone of the tz parameter
+func (c *TaskController) ExportTasksTodoTxt(ctx *gin.Context) {
+	filter, ok := exportFilterParams(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	tasks, err := c.Service.ExportLabeledTasks(filter, loc)
+	if err != nil {
+		respondExportError(ctx, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := services.WriteTasksTodoTxt(&body, tasks, loc); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error exporting tasks"})
+		return
+	}
+	ctx.Header("Content-Disposition", `attachment; filename="todo.txt"`)
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", body.Bytes())
+}
+
+// ImportTasksTodoTxt method creates tasks from the lines of a todo.txt file, sent as the request body or as
+// the file field of a multipart form. Tasks without a project go to the list of the list_id parameter, if
+// any, due dates are days in the time zone of the tz parameter, and dry_run=true checks the file without
+// storing anything. When any line is rejected nothing is imported and the response lists them with 422.
+func (c *TaskController) ImportTasksTodoTxt(ctx *gin.Context) {
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+	dryRun, ok := dryRunParam(ctx)
+	if !ok {
+		return
+	}
+	listId, ok := listIdQuery(ctx)
+	if !ok {
+		return
+	}
+	file, ok := importFile(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	options := entity.TodoTxtImport{ListID: listId, DryRun: dryRun, Location: loc}
+	tasks, rowErrors, err := c.Service.ImportTasksTodoTxt(file, options)
+	if err != nil {
+		respondImportError(ctx, err)
+		return
+	}
+	if len(rowErrors) > 0 {
+		respondRejectedRows(ctx, rowErrors)
+		return
+	}
+
+	if dryRun {
+		ctx.JSON(http.StatusOK, gin.H{"data": tasks, "dry_run": true})
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"data": tasks, "imported": len(tasks)})
+}
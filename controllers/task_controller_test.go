@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	"todo-lists/entity"
 	"todo-lists/mocks"
+	"todo-lists/query"
+	"todo-lists/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
@@ -18,6 +23,11 @@ import (
 	"gorm.io/gorm"
 )
 
+// due returns a deadline for a task literal
+func due(deadline time.Time) *time.Time {
+	return &deadline
+}
+
 func TestCreateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -37,12 +47,27 @@ func TestCreateTask(t *testing.T) {
 			Header: map[string][]string{
 				"Content-Type": []string{"application/json"},
 			},
-			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "tag":"high"}`))),
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority":"high"}`))),
 		}
-		mockService.EXPECT().CreateTask(gomock.Any()).Return(nil).Times(1)
+		mockService.EXPECT().CreateTask(gomock.Any()).DoAndReturn(func(task *entity.Task) error {
+			task.ID = 7
+			task.Version = 1
+			task.Status = entity.StatusTodo
+			task.CreatedAt = time.Date(2024, 10, 20, 8, 0, 0, 0, time.UTC)
+			task.UpdatedAt = task.CreatedAt
+			return nil
+		}).Times(1)
 		tc.CreateTask(ginContext)
 
+		// The response carries what the server assigned and points at the new task
 		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/tasks/7", w.Header().Get("Location"))
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+		var created entity.Task
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, uint(7), created.ID)
+		assert.Equal(t, entity.StatusTodo, created.Status)
+		assert.True(t, time.Date(2024, 10, 20, 8, 0, 0, 0, time.UTC).Equal(created.CreatedAt))
 	})
 	t.Run("Invalid payload", func(t *testing.T) {
 		tc := TaskController{
@@ -77,7 +102,7 @@ func TestCreateTask(t *testing.T) {
 			Header: map[string][]string{
 				"Content-Type": {"application/json"},
 			},
-			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "tag":"high"}`))),
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority":"high"}`))),
 		}
 
 		mockService.EXPECT().CreateTask(gomock.Any()).Return(errors.New("creation error")).Times(1)
@@ -105,25 +130,27 @@ func TestGetTasks(t *testing.T) {
 		ginContext, _ := gin.CreateTestContext(w)
 
 		expectedTasks := []entity.Task{
-			{Name: "Task 1", Deadline: time.Now(), Tag: "high"},
-			{Name: "Task 2", Deadline: time.Now().Add(48 * time.Hour), Tag: "medium"},
+			{Name: "Task 1", Deadline: due(time.Now()), Priority: "high"},
+			{Name: "Task 2", Deadline: due(time.Now().Add(48 * time.Hour)), Priority: "medium"},
 		}
 
-		mockService.EXPECT().GetAllTasks().Return(expectedTasks, nil).Times(1)
+		mockService.EXPECT().GetAllTasks("", gomock.Any()).Return(entity.TaskPage{Tasks: expectedTasks}, nil).Times(1)
 
 		tc.GetTasks(ginContext)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
 		// Unmarshal response into a variable for comparison
-		var actualTasks []map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &actualTasks)
+		var actualPage struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &actualPage)
 		assert.NoError(t, err)
 
 		// Compare relevant fields
-		for i, task := range actualTasks {
+		for i, task := range actualPage.Data {
 			assert.Equal(t, expectedTasks[i].Name, task["name"])
-			assert.Equal(t, expectedTasks[i].Tag, task["tag"])
+			assert.Equal(t, expectedTasks[i].Priority, task["priority"])
 		}
 	})
 
@@ -131,7 +158,7 @@ func TestGetTasks(t *testing.T) {
 		w := httptest.NewRecorder()
 		ginContext, _ := gin.CreateTestContext(w)
 
-		mockService.EXPECT().GetAllTasks().Return(nil, errors.New("fetching error")).Times(1)
+		mockService.EXPECT().GetAllTasks("", gomock.Any()).Return(entity.TaskPage{}, errors.New("fetching error")).Times(1)
 
 		tc.GetTasks(ginContext)
 
@@ -143,12 +170,12 @@ func TestGetTasks(t *testing.T) {
 		w := httptest.NewRecorder()
 		ginContext, _ := gin.CreateTestContext(w)
 
-		mockService.EXPECT().GetAllTasks().Return([]entity.Task{}, nil).Times(1)
+		mockService.EXPECT().GetAllTasks("", gomock.Any()).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil).Times(1)
 
 		tc.GetTasks(ginContext)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.JSONEq(t, `[]`, w.Body.String())
+		assert.JSONEq(t, `{"data": []}`, w.Body.String())
 	})
 
 }
@@ -173,8 +200,8 @@ func TestUpdateTask(t *testing.T) {
 		taskToUpdate := entity.Task{
 			ID:       1,
 			Name:     "Updated Task",
-			Deadline: time.Now(),
-			Tag:      "medium",
+			Deadline: due(time.Now()),
+			Priority: "medium",
 		}
 
 		// Set up the request body
@@ -197,7 +224,7 @@ func TestUpdateTask(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &updatedTask)
 		assert.NoError(t, err)
 		assert.Equal(t, taskToUpdate.Name, updatedTask.Name)
-		assert.Equal(t, taskToUpdate.Tag, updatedTask.Tag)
+		assert.Equal(t, taskToUpdate.Priority, updatedTask.Priority)
 	})
 
 	t.Run("Invalid ID format", func(t *testing.T) {
@@ -305,20 +332,20 @@ func TestSearchTasks(t *testing.T) {
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/search?keyword=test", nil)
 
 		expectedTasks := []entity.Task{
-			{Name: "Test Task 1", Deadline: time.Now(), Tag: "high"},
-			{Name: "Test Task 2", Deadline: time.Now().Add(48 * time.Hour), Tag: "medium"},
+			{Name: "Test Task 1", Deadline: due(time.Now()), Priority: "high"},
+			{Name: "Test Task 2", Deadline: due(time.Now().Add(48 * time.Hour)), Priority: "medium"},
 		}
 
-		mockService.EXPECT().SearchTasksByName("test").Return(expectedTasks, nil).Times(1)
+		mockService.EXPECT().SearchTasksByName("test", gomock.Any()).Return(entity.TaskPage{Tasks: expectedTasks}, nil).Times(1)
 
 		tc.SearchTasks(ginContext)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var actualTasks []entity.Task
-		err := json.Unmarshal(w.Body.Bytes(), &actualTasks)
+		var actualPage entity.TaskPage
+		err := json.Unmarshal(w.Body.Bytes(), &actualPage)
 		assert.NoError(t, err)
-		assert.Equal(t, len(expectedTasks), len(actualTasks))
+		assert.Equal(t, len(expectedTasks), len(actualPage.Tasks))
 	})
 
 	t.Run("No tasks found", func(t *testing.T) {
@@ -328,7 +355,7 @@ func TestSearchTasks(t *testing.T) {
 		// Set the query parameter for the search
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/search?keyword=test", nil)
 
-		mockService.EXPECT().SearchTasksByName("test").Return([]entity.Task{}, nil).Times(1)
+		mockService.EXPECT().SearchTasksByName("test", gomock.Any()).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil).Times(1)
 
 		tc.SearchTasks(ginContext)
 
@@ -343,13 +370,36 @@ func TestSearchTasks(t *testing.T) {
 		// Set the query parameter for the search
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/search?keyword=test", nil)
 
-		mockService.EXPECT().SearchTasksByName("test").Return(nil, errors.New("search error")).Times(1)
+		mockService.EXPECT().SearchTasksByName("test", gomock.Any()).Return(entity.TaskPage{}, errors.New("search error")).Times(1)
 
 		tc.SearchTasks(ginContext)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.JSONEq(t, `{"error": "Error searching tasks"}`, w.Body.String())
 	})
+
+	t.Run("Results are ranked by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/search?keyword=test", nil)
+
+		page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: entity.SortRank}
+		mockService.EXPECT().SearchTasksByName("test", page).Return(entity.TaskPage{Tasks: []entity.Task{{ID: 1}}}, nil).Times(1)
+
+		tc.SearchTasks(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Rank cannot be reversed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/search?keyword=test&sort=-rank", nil)
+
+		tc.SearchTasks(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 func TestFilterTasksByDeadline(t *testing.T) {
@@ -372,20 +422,20 @@ func TestFilterTasksByDeadline(t *testing.T) {
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/filter?start=2024-10-01&end=2024-10-31", nil)
 
 		expectedTasks := []entity.Task{
-			{Name: "Task 1", Deadline: time.Now(), Tag: "high"},
-			{Name: "Task 2", Deadline: time.Now().Add(48 * time.Hour), Tag: "medium"},
+			{Name: "Task 1", Deadline: due(time.Now()), Priority: "high"},
+			{Name: "Task 2", Deadline: due(time.Now().Add(48 * time.Hour)), Priority: "medium"},
 		}
 
-		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any()).Return(expectedTasks, nil).Times(1)
+		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.TaskPage{Tasks: expectedTasks}, nil).Times(1)
 
 		tc.FilterTasksByDeadline(ginContext)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var actualTasks []entity.Task
-		err := json.Unmarshal(w.Body.Bytes(), &actualTasks)
+		var actualPage entity.TaskPage
+		err := json.Unmarshal(w.Body.Bytes(), &actualPage)
 		assert.NoError(t, err)
-		assert.Equal(t, len(expectedTasks), len(actualTasks))
+		assert.Equal(t, len(expectedTasks), len(actualPage.Tasks))
 	})
 
 	t.Run("Invalid start date format", func(t *testing.T) {
@@ -421,7 +471,7 @@ func TestFilterTasksByDeadline(t *testing.T) {
 		// Set the query parameters for the date range
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/filter?start=2024-10-01&end=2024-10-31", nil)
 
-		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any()).Return([]entity.Task{}, nil).Times(1)
+		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil).Times(1)
 
 		tc.FilterTasksByDeadline(ginContext)
 
@@ -436,7 +486,7 @@ func TestFilterTasksByDeadline(t *testing.T) {
 		// Set the query parameters for the date range
 		ginContext.Request, _ = http.NewRequest(http.MethodGet, "/tasks/filter?start=2024-10-01&end=2024-10-31", nil)
 
-		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any()).Return(nil, errors.New("filter error")).Times(1)
+		mockService.EXPECT().FilterTasksByDeadline(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.TaskPage{}, errors.New("filter error")).Times(1)
 
 		tc.FilterTasksByDeadline(ginContext)
 
@@ -465,8 +515,9 @@ func TestDeleteTask(t *testing.T) {
 		ginContext.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
+		ginContext.Request = httptest.NewRequest(http.MethodDelete, "/tasks/1", nil)
 
-		mockService.EXPECT().DeleteTask(1).Return(nil).Times(1)
+		mockService.EXPECT().DeleteTask(1, uint(0)).Return(nil).Times(1)
 
 		tc.DeleteTask(ginContext)
 
@@ -497,8 +548,9 @@ func TestDeleteTask(t *testing.T) {
 		ginContext.Params = gin.Params{
 			{Key: "id", Value: "1"},
 		}
+		ginContext.Request = httptest.NewRequest(http.MethodDelete, "/tasks/1", nil)
 
-		mockService.EXPECT().DeleteTask(1).Return(errors.New("deletion error")).Times(1)
+		mockService.EXPECT().DeleteTask(1, uint(0)).Return(errors.New("deletion error")).Times(1)
 
 		tc.DeleteTask(ginContext)
 
@@ -529,22 +581,22 @@ func TestGetTaskByTag(t *testing.T) {
 		}
 
 		tasks := []entity.Task{
-			{ID: 1, Name: "Task 1", Tag: "important"},
-			{ID: 2, Name: "Task 2", Tag: "important"},
+			{ID: 1, Name: "Task 1", Priority: "high"},
+			{ID: 2, Name: "Task 2", Priority: "high"},
 		}
 
-		mockService.EXPECT().GetTasksByTag("important").Return(tasks, nil).Times(1)
+		mockService.EXPECT().GetTasksByTag([]string{"important"}, false, gomock.Any()).Return(entity.TaskPage{Tasks: tasks}, nil).Times(1)
 
 		tc.GetTaskByTag(ginContext)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
-		var response []entity.Task
+		var response entity.TaskPage
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, 2, len(response))
-		assert.Equal(t, uint(1), response[0].ID)
-		assert.Equal(t, "Task 1", response[0].Name)
+		assert.Equal(t, 2, len(response.Tasks))
+		assert.Equal(t, uint(1), response.Tasks[0].ID)
+		assert.Equal(t, "Task 1", response.Tasks[0].Name)
 	})
 
 	t.Run("No tasks found for the specified tag", func(t *testing.T) {
@@ -556,7 +608,7 @@ func TestGetTaskByTag(t *testing.T) {
 			{Key: "tag", Value: "nonexistent"},
 		}
 
-		mockService.EXPECT().GetTasksByTag("nonexistent").Return([]entity.Task{}, nil).Times(1)
+		mockService.EXPECT().GetTasksByTag([]string{"nonexistent"}, false, gomock.Any()).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil).Times(1)
 
 		tc.GetTaskByTag(ginContext)
 
@@ -573,11 +625,1641 @@ func TestGetTaskByTag(t *testing.T) {
 			{Key: "tag", Value: "error"},
 		}
 
-		mockService.EXPECT().GetTasksByTag("error").Return(nil, errors.New("service error")).Times(1)
+		mockService.EXPECT().GetTasksByTag([]string{"error"}, false, gomock.Any()).Return(entity.TaskPage{}, errors.New("service error")).Times(1)
+
+		tc.GetTaskByTag(ginContext)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error fetching tasks"}`, w.Body.String())
+	})
+
+	t.Run("Several labels matched all at once", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/tag/work,%20urgent,?match=all", nil)
+		ginContext.Params = gin.Params{
+			{Key: "tag", Value: "work, urgent,"},
+		}
+
+		tasks := []entity.Task{{ID: 3, Name: "Task 3"}}
+		mockService.EXPECT().GetTasksByTag([]string{"work", "urgent"}, true, gomock.Any()).Return(entity.TaskPage{Tasks: tasks}, nil).Times(1)
+
+		tc.GetTaskByTag(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid match mode", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/tag/work?match=some", nil)
+		ginContext.Params = gin.Params{
+			{Key: "tag", Value: "work"},
+		}
+
+		tc.GetTaskByTag(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Match must be any or all"}`, w.Body.String())
+	})
+
+	t.Run("No label names", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{
+			{Key: "tag", Value: " , "},
+		}
 
 		tc.GetTaskByTag(ginContext)
 
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "At least one label is required"}`, w.Body.String())
+	})
+}
+
+func TestGetListTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful retrieval of list tasks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		expectedTasks := []entity.Task{{ID: 1, ListID: 1, Name: "Task 1", Priority: "high"}}
+		mockService.EXPECT().GetTasksByListId(1, gomock.Any()).Return(entity.TaskPage{Tasks: expectedTasks}, nil).Times(1)
+
+		tc.GetListTasks(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var actualPage entity.TaskPage
+		err := json.Unmarshal(w.Body.Bytes(), &actualPage)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), actualPage.Tasks[0].ListID)
+	})
+
+	t.Run("List not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().GetTasksByListId(2, gomock.Any()).Return(entity.TaskPage{}, gorm.ErrRecordNotFound).Times(1)
+
+		tc.GetListTasks(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+	})
+}
+
+func TestCreateListTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Task is created in the route list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "4"}}
+		ginContext.Request = &http.Request{
+			Method: http.MethodPost,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority":"high"}`))),
+		}
+
+		mockService.EXPECT().CreateTask(gomock.Any()).DoAndReturn(func(task *entity.Task) error {
+			assert.Equal(t, uint(4), task.ListID)
+			task.ID = 8
+			return nil
+		}).Times(1)
+
+		tc.CreateListTask(ginContext)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/tasks/8", w.Header().Get("Location"))
+	})
+
+	t.Run("List not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "5"}}
+		ginContext.Request = &http.Request{
+			Method: http.MethodPost,
+			Header: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			Body: io.NopCloser(bytes.NewBuffer([]byte(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority":"high"}`))),
+		}
+
+		mockService.EXPECT().CreateTask(gomock.Any()).Return(services.ErrListNotFound).Times(1)
+
+		tc.CreateListTask(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+	})
+}
+
+func TestCompleteTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful completion", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+
+		completedAt := time.Now()
+		mockService.EXPECT().TransitionTask(1, entity.StatusDone).
+			Return(entity.Task{ID: 1, Status: entity.StatusDone, CompletedAt: &completedAt}, nil).Times(1)
+
+		tc.CompleteTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var task entity.Task
+		err := json.Unmarshal(w.Body.Bytes(), &task)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusDone, task.Status)
+		assert.NotNil(t, task.CompletedAt)
+	})
+
+	t.Run("Illegal transition", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().TransitionTask(2, entity.StatusDone).Return(entity.Task{}, services.ErrIllegalTransition).Times(1)
+
+		tc.CompleteTask(ginContext)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Task cannot move to done"}`, w.Body.String())
+	})
+
+	t.Run("Task not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "3"}}
+
+		mockService.EXPECT().TransitionTask(3, entity.StatusTodo).Return(entity.Task{}, gorm.ErrRecordNotFound).Times(1)
+
+		tc.ReopenTask(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetTasksByQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		tc.GetTasks(ginContext)
+		return w
+	}
+
+	t.Run("Matching tasks", func(t *testing.T) {
+		tasks := []entity.Task{{ID: 1, Name: "Write report"}}
+		mockService.EXPECT().QueryTasks(`label:work AND name~"report"`, time.UTC, gomock.Any()).Return(entity.TaskPage{Tasks: tasks}, nil).Times(1)
+
+		w := get("/tasks?q=label%3Awork+AND+name~%22report%22")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"Write report"`)
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		mockService.EXPECT().QueryTasks("color:red", time.UTC, gomock.Any()).
+			Return(entity.TaskPage{}, &query.Error{Pos: 1, Msg: `unknown field "color"`}).Times(1)
+
+		w := get("/tasks?q=color:red")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid query at position 1: unknown field \"color\"", "position": 1}`, w.Body.String())
+	})
+
+	t.Run("Time zone", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+		mockService.EXPECT().QueryTasks("deadline:today", berlin, gomock.Any()).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil).Times(1)
+
+		w := get("/tasks?q=deadline:today&tz=Europe/Berlin")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = get("/tasks?q=deadline:today&tz=Mars/Olympus")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Unknown time zone"}`, w.Body.String())
+	})
+
+	t.Run("Status parameter with a query", func(t *testing.T) {
+		w := get("/tasks?q=label:work&status=done")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Use status:<value> inside q instead of the status parameter"}`, w.Body.String())
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().QueryTasks("label:work", time.UTC, gomock.Any()).Return(entity.TaskPage{}, errors.New("db error")).Times(1)
+
+		w := get("/tasks?q=label:work")
+
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.JSONEq(t, `{"error": "Error fetching tasks"}`, w.Body.String())
 	})
 }
+
+func TestGetTasksByStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Invalid status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks?status=finished", nil)
+
+		mockService.EXPECT().GetAllTasks("finished", gomock.Any()).Return(entity.TaskPage{}, services.ErrInvalidStatus).Times(1)
+
+		tc.GetTasks(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid task status"}`, w.Body.String())
+	})
+}
+
+func TestGetTasksPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Page parameters are passed to the service", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks?limit=1&sort=-deadline", nil)
+
+		expectedPage := entity.PageRequest{Limit: 1, Sort: "deadline", Desc: true}
+		mockService.EXPECT().GetAllTasks("", expectedPage).
+			Return(entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Task 1"}}, Next: "abc"}, nil).Times(1)
+
+		tc.GetTasks(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var actualPage entity.TaskPage
+		err := json.Unmarshal(w.Body.Bytes(), &actualPage)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", actualPage.Next)
+	})
+
+	t.Run("Invalid sort field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks?sort=status", nil)
+
+		tc.GetTasks(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid sort field"}`, w.Body.String())
+	})
+
+	t.Run("Cursor issued for another sort", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		cursor := entity.Cursor{Sort: "name", Value: "a", ID: 1}.Encode()
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks?sort=deadline&cursor="+cursor, nil)
+
+		tc.GetTasks(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid page cursor"}`, w.Body.String())
+	})
+}
+
+func TestPatchTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Successful merge patch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPatch, "/tasks/1", bytes.NewBufferString(`{"name": "Renamed"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/merge-patch+json")
+
+		mockService.EXPECT().PatchTask(1, uint(0), services.MergePatchType, []byte(`{"name": "Renamed"}`)).
+			Return(entity.Task{ID: 1, Name: "Renamed", Priority: "high"}, nil).Times(1)
+
+		tc.PatchTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Plain JSON is treated as merge patch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPatch, "/tasks/1", bytes.NewBufferString(`{"name": "Renamed"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+
+		mockService.EXPECT().PatchTask(1, uint(0), services.MergePatchType, gomock.Any()).
+			Return(entity.Task{ID: 1, Name: "Renamed"}, nil).Times(1)
+
+		tc.PatchTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPatch, "/tasks/1", bytes.NewBufferString(`name=x`))
+		ginContext.Request.Header.Set("Content-Type", "text/plain")
+
+		mockService.EXPECT().PatchTask(1, uint(0), "text/plain", gomock.Any()).Return(entity.Task{}, services.ErrUnsupportedPatch).Times(1)
+
+		tc.PatchTask(ginContext)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+
+	t.Run("Task not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "9"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPatch, "/tasks/9", bytes.NewBufferString(`{"name": "x"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/merge-patch+json")
+
+		mockService.EXPECT().PatchTask(9, uint(0), services.MergePatchType, gomock.Any()).Return(entity.Task{}, gorm.ErrRecordNotFound).Times(1)
+
+		tc.PatchTask(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Task not found"}`, w.Body.String())
+	})
+
+	t.Run("Read only field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPatch, "/tasks/1", bytes.NewBufferString(`{"status": "done"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/merge-patch+json")
+
+		mockService.EXPECT().PatchTask(1, uint(0), services.MergePatchType, gomock.Any()).
+			Return(entity.Task{}, fmt.Errorf("%w: status", services.ErrReadOnlyField)).Times(1)
+
+		tc.PatchTask(ginContext)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{"error": "field cannot be patched: status"}`, w.Body.String())
+	})
+}
+
+func TestGetTaskById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Response carries the version as ETag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+
+		mockService.EXPECT().GetTaskById(1).Return(entity.Task{ID: 1, Name: "Task 1", Version: 3}, nil).Times(1)
+
+		tc.GetTaskById(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Matching If-None-Match is not modified", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+		ginContext.Request.Header.Set("If-None-Match", `"2", "3"`)
+
+		mockService.EXPECT().GetTaskById(1).Return(entity.Task{ID: 1, Name: "Task 1", Version: 3}, nil).Times(1)
+
+		tc.GetTaskById(ginContext)
+		ginContext.Writer.WriteHeaderNow()
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("Task not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "2"}}
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/2", nil)
+
+		mockService.EXPECT().GetTaskById(2).Return(entity.Task{}, gorm.ErrRecordNotFound).Times(1)
+
+		tc.GetTaskById(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestConditionalWrites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("If-Match version is passed to the update", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPut, "/tasks/1",
+			bytes.NewBufferString(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority": "high", "version": 9}`))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		ginContext.Request.Header.Set("If-Match", `"4"`)
+
+		mockService.EXPECT().UpdateTask(gomock.Any()).DoAndReturn(func(task *entity.Task) error {
+			assert.Equal(t, uint(4), task.Version)
+			task.Version = 5
+			return nil
+		}).Times(1)
+
+		tc.UpdateTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Outdated If-Match on update", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPut, "/tasks/1",
+			bytes.NewBufferString(`{"name": "test", "deadline": "2024-10-22T17:00:00+05:30", "priority": "high"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		ginContext.Request.Header.Set("If-Match", `"3"`)
+
+		mockService.EXPECT().UpdateTask(gomock.Any()).Return(services.ErrVersionConflict).Times(1)
+
+		tc.UpdateTask(ginContext)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("Outdated If-Match on delete", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodDelete, "/tasks/1", nil)
+		ginContext.Request.Header.Set("If-Match", `W/"2"`)
+
+		mockService.EXPECT().DeleteTask(1, uint(2)).Return(services.ErrVersionConflict).Times(1)
+
+		tc.DeleteTask(ginContext)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("Unparseable If-Match never matches", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodDelete, "/tasks/1", nil)
+		ginContext.Request.Header.Set("If-Match", `"abc"`)
+
+		tc.DeleteTask(ginContext)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{Service: mockService}
+	gin.SetMode(gin.TestMode)
+
+	t.Run("List trash", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/trash", nil)
+
+		deletedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().GetTrash(gomock.Any()).
+			Return(entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Task 1", DeletedAt: &deletedAt}}}, nil)
+
+		tc.GetTrash(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"deleted_at":"2026-10-01T00:00:00Z"`)
+	})
+
+	t.Run("Restore task", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().RestoreTask(1).Return(entity.Task{ID: 1, Name: "Task 1", Version: 3}, nil)
+
+		tc.RestoreTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Restore task not in trash", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().RestoreTask(2).Return(entity.Task{}, gorm.ErrRecordNotFound)
+
+		tc.RestoreTask(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Task not found in trash"}`, w.Body.String())
+	})
+
+	t.Run("Purge task", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().PurgeTask(1).Return(nil)
+
+		tc.PurgeTask(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "Task permanently deleted"}`, w.Body.String())
+	})
+
+	t.Run("Purge task not in trash", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().PurgeTask(2).Return(gorm.ErrRecordNotFound)
+
+		tc.PurgeTask(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRecurrence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{Service: mockService}
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Invalid recurrence rule", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/tasks",
+			bytes.NewBufferString(`{"name": "test", "deadline": "2026-11-02T09:00:00Z", "priority": "high", "recurrence": "FREQ=YEARLY"}`))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+
+		mockService.EXPECT().CreateTask(gomock.Any()).Return(fmt.Errorf("%w: unsupported FREQ YEARLY", services.ErrInvalidRecurrence))
+
+		tc.CreateTask(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid recurrence rule"}`, w.Body.String())
+	})
+
+	t.Run("Occurrences", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/occurrences?start=2026-11-01&end=2026-11-30", nil)
+
+		start := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		end := time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().GetOccurrences(start, end).Return([]entity.Occurrence{
+			{Task: entity.Task{ID: 1, Name: "Standup", Recurrence: "FREQ=WEEKLY"}},
+			{Task: entity.Task{ID: 1, Name: "Standup", Recurrence: "FREQ=WEEKLY"}, Virtual: true},
+		}, nil)
+
+		tc.GetOccurrences(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data []entity.Occurrence `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Data, 2)
+		assert.True(t, body.Data[1].Virtual)
+	})
+
+	t.Run("Too many occurrences", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/occurrences?start=2026-01-01&end=2036-01-01", nil)
+
+		mockService.EXPECT().GetOccurrences(gomock.Any(), gomock.Any()).Return(nil, services.ErrTooManyOccurrences)
+
+		tc.GetOccurrences(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/occurrences?start=soon&end=2026-11-30", nil)
+
+		tc.GetOccurrences(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetTaskTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{Service: mockService}
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Nested subtasks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+
+		mockService.EXPECT().GetTaskTree(1).Return(entity.TaskTree{
+			Task:     entity.Task{ID: 1, Name: "Release"},
+			Progress: entity.Progress{Total: 2, Done: 1, Percent: 50},
+			Subtasks: []entity.TaskTree{
+				{Task: entity.Task{ID: 2, ParentID: 1, Name: "Docs", Status: entity.StatusDone}, Subtasks: []entity.TaskTree{}},
+				{Task: entity.Task{ID: 3, ParentID: 1, Name: "Build"}, Subtasks: []entity.TaskTree{}},
+			},
+		}, nil)
+
+		tc.GetTaskTree(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var tree entity.TaskTree
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &tree))
+		assert.Equal(t, 50, tree.Progress.Percent)
+		assert.Len(t, tree.Subtasks, 2)
+		assert.Equal(t, uint(1), tree.Subtasks[1].ParentID)
+	})
+
+	t.Run("Task not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "9"}}
+
+		mockService.EXPECT().GetTaskTree(9).Return(entity.TaskTree{}, gorm.ErrRecordNotFound)
+
+		tc.GetTaskTree(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "1"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPut, "/tasks/1",
+			bytes.NewBufferString(`{"name": "Release", "deadline": "2026-11-02T09:00:00Z", "priority": "high", "parent_id": 3}`))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+
+		mockService.EXPECT().UpdateTask(gomock.Any()).Return(services.ErrTaskCycle)
+
+		tc.UpdateTask(ginContext)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Task cannot be a subtask of itself"}`, w.Body.String())
+	})
+}
+
+func TestTaskDependencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{Service: mockService}
+	gin.SetMode(gin.TestMode)
+
+	addBlocker := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "2"}}
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/tasks/2/blockers", bytes.NewBufferString(body))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		tc.AddBlocker(ginContext)
+		return w
+	}
+
+	t.Run("Add blocker", func(t *testing.T) {
+		mockService.EXPECT().AddBlocker(2, 1).Return(nil)
+
+		w := addBlocker(`{"blocker_id": 1}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"task_id": 2, "blocker_id": 1}`, w.Body.String())
+	})
+
+	t.Run("Add blocker closing a cycle", func(t *testing.T) {
+		mockService.EXPECT().AddBlocker(2, 3).Return(services.ErrDependencyCycle)
+
+		w := addBlocker(`{"blocker_id": 3}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Dependency would create a cycle"}`, w.Body.String())
+	})
+
+	t.Run("Add blocker without blocker ID", func(t *testing.T) {
+		w := addBlocker(`{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Remove missing blocker", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "2"}, {Key: "blockerId", Value: "5"}}
+
+		mockService.EXPECT().RemoveBlocker(2, 5).Return(gorm.ErrRecordNotFound)
+
+		tc.RemoveBlocker(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Start blocked task", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{{Key: "id", Value: "2"}}
+
+		mockService.EXPECT().TransitionTask(2, entity.StatusInProgress).Return(entity.Task{}, services.ErrBlocked)
+
+		tc.StartTask(ginContext)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Task is waiting for its blockers"}`, w.Body.String())
+	})
+
+	t.Run("Plan of list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/plan?list_id=4", nil)
+
+		mockService.EXPECT().GetPlan(4).Return([]entity.PlanItem{
+			{Task: entity.Task{ID: 1}, BlockedBy: []uint{}},
+			{Task: entity.Task{ID: 2}, BlockedBy: []uint{1}, DeadlineConflict: true},
+		}, nil)
+
+		tc.GetPlan(ginContext)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"blocked_by":[1],"deadline_conflict":true`)
+	})
+
+	t.Run("Plan of missing list", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/tasks/plan?list_id=9", nil)
+
+		mockService.EXPECT().GetPlan(9).Return(nil, gorm.ErrRecordNotFound)
+
+		tc.GetPlan(ginContext)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestBulkTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, target, bytes.NewBufferString(body))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		tc.BulkTasks(ginContext)
+		return w
+	}
+
+	t.Run("Results per operation", func(t *testing.T) {
+		mockService.EXPECT().BulkTasks(gomock.Any(), time.UTC).DoAndReturn(func(request entity.BulkRequest, loc *time.Location) ([]entity.BulkResult, error) {
+			assert.False(t, request.Atomic)
+			assert.Len(t, request.Operations, 3)
+			assert.JSONEq(t, `{"name": "Write tests"}`, string(request.Operations[1].Patch))
+			return []entity.BulkResult{
+				{Index: 0, Op: entity.BulkCreate, ID: 5, Task: &entity.Task{ID: 5, Name: "Docs"}},
+				{Index: 1, Op: entity.BulkUpdate, ID: 2, Err: fmt.Errorf("%w: name is required", services.ErrInvalidTask)},
+				{Index: 2, Op: entity.BulkDelete, ID: 9, Err: gorm.ErrRecordNotFound},
+			}, nil
+		}).Times(1)
+
+		w := post("/tasks/bulk", `{"operations": [
+			{"op": "create", "task": {"name": "Docs"}},
+			{"op": "update", "id": 2, "patch": {"name": "Write tests"}},
+			{"op": "delete", "id": 9}
+		]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data      []bulkItem `json:"data"`
+			Succeeded int        `json:"succeeded"`
+			Failed    int        `json:"failed"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Succeeded)
+		assert.Equal(t, 2, body.Failed)
+		if assert.Len(t, body.Data, 3) {
+			assert.Equal(t, http.StatusCreated, body.Data[0].Status)
+			assert.Equal(t, "Docs", body.Data[0].Task.Name)
+			assert.Equal(t, bulkItem{Index: 1, Op: "update", ID: 2, Status: http.StatusUnprocessableEntity, Error: "invalid task: name is required"}, body.Data[1])
+			assert.Equal(t, bulkItem{Index: 2, Op: "delete", ID: 9, Status: http.StatusNotFound, Error: "Task not found"}, body.Data[2])
+		}
+	})
+
+	t.Run("Atomic failure", func(t *testing.T) {
+		mockService.EXPECT().BulkTasks(gomock.Any(), gomock.Any()).
+			Return(nil, &services.BulkError{Index: 1, Err: services.ErrVersionConflict}).Times(1)
+
+		w := post("/tasks/bulk", `{"atomic": true, "operations": [{"op": "complete", "id": 1}, {"op": "delete", "id": 2, "version": 3}]}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "Task was modified, fetch the latest version and retry", "index": 1}`, w.Body.String())
+	})
+
+	t.Run("Filter and action", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+		request := entity.BulkRequest{Filter: "label:medium", Action: &entity.BulkOperation{Op: entity.BulkShiftDeadline, Days: 3}}
+		mockService.EXPECT().BulkTasks(request, berlin).Return([]entity.BulkResult{}, nil).Times(1)
+
+		w := post("/tasks/bulk?tz=Europe/Berlin", `{"filter": "label:medium", "action": {"op": "shift_deadline", "days": 3}}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data": [], "succeeded": 0, "failed": 0}`, w.Body.String())
+	})
+
+	t.Run("Invalid filter", func(t *testing.T) {
+		mockService.EXPECT().BulkTasks(gomock.Any(), gomock.Any()).
+			Return(nil, &query.Error{Pos: 10, Msg: `unknown priority "urgent"`}).Times(1)
+
+		w := post("/tasks/bulk", `{"filter": "priority:urgent", "action": {"op": "delete"}}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid query at position 10: unknown priority \"urgent\"", "position": 10}`, w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		mockService.EXPECT().BulkTasks(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("%w: no operations", services.ErrInvalidBulk)).Times(1)
+
+		w := post("/tasks/bulk", `{}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid bulk request: no operations"}`, w.Body.String())
+
+		w = post("/tasks/bulk", `{"operations": {}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid input"}`, w.Body.String())
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().BulkTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error")).Times(1)
+
+		w := post("/tasks/bulk", `{"operations": [{"op": "delete", "id": 2}]}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error applying bulk request"}`, w.Body.String())
+	})
+}
+
+func TestExportTasksCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		tc.ExportTasksCSV(ginContext)
+		return w
+	}
+
+	t.Run("Filtered export", func(t *testing.T) {
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+		mockService.EXPECT().ExportTasks(gomock.Any(), berlin).DoAndReturn(func(filter entity.TaskFilter, loc *time.Location) ([]entity.Task, error) {
+			assert.Equal(t, "priority:high", filter.Query)
+			assert.Equal(t, []string{"work", "home"}, filter.Labels)
+			assert.True(t, filter.MatchAll)
+			assert.Equal(t, "report", filter.Keyword)
+			assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), *filter.Start)
+			assert.Nil(t, filter.End)
+			return []entity.Task{{ID: 1, Name: "Write report", Priority: "high", Status: entity.StatusTodo, CreatedAt: created, UpdatedAt: created}}, nil
+		}).Times(1)
+
+		w := get("/tasks/export.csv?q=priority:high&tag=work,%20home&match=all&keyword=report&start=2026-11-01&tz=Europe/Berlin")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="tasks.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "id,name,deadline,priority,status,list_id,parent_id,recurrence,completed_at,created_at,updated_at\n"+
+			"1,Write report,,high,todo,,,,,2026-11-01T09:00:00+01:00,2026-11-01T09:00:00+01:00\n", w.Body.String())
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		w := get("/tasks/export.csv?end=tomorrow")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid end date format"}`, w.Body.String())
+
+		w = get("/tasks/export.csv?match=some")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		mockService.EXPECT().ExportTasks(gomock.Any(), gomock.Any()).Return(nil, services.ErrInvalidStatus).Times(1)
+		w = get("/tasks/export.csv?status=finished")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid task status"}`, w.Body.String())
+
+		mockService.EXPECT().ExportTasks(gomock.Any(), gomock.Any()).Return(nil, &query.Error{Pos: 9, Msg: "unknown priority"}).Times(1)
+		w = get("/tasks/export.csv?q=priority:urgent")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"position":9`)
+	})
+}
+
+func TestImportTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(target, contentType string, body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, target, body)
+		ginContext.Request.Header.Set("Content-Type", contentType)
+		tc.ImportTasks(ginContext)
+		return w
+	}
+	file := "Title,deadline\nWrite report,2026-11-02\n"
+
+	t.Run("Successful import", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksCSV(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.CSVImport) ([]entity.Task, []entity.ImportRowError, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, file, string(content))
+			assert.Equal(t, map[string]string{"name": "Title"}, options.Columns)
+			assert.False(t, options.DryRun)
+			assert.Equal(t, "America/New_York", options.Location.String())
+			return []entity.Task{{ID: 4, Name: "Write report"}}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import?map[name]=Title&tz=America/New_York", "text/csv", strings.NewReader(file))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var body struct {
+			Data     []entity.Task `json:"data"`
+			Imported int           `json:"imported"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Imported)
+		assert.Equal(t, "Write report", body.Data[0].Name)
+	})
+
+	t.Run("Multipart upload and dry run", func(t *testing.T) {
+		var form bytes.Buffer
+		writer := multipart.NewWriter(&form)
+		part, _ := writer.CreateFormFile("file", "tasks.csv")
+		part.Write([]byte(file))
+		writer.Close()
+
+		mockService.EXPECT().ImportTasksCSV(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.CSVImport) ([]entity.Task, []entity.ImportRowError, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, file, string(content))
+			assert.True(t, options.DryRun)
+			return []entity.Task{{Name: "Write report"}}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import?dry_run=true", writer.FormDataContentType(), &form)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"dry_run":true`)
+
+		w = post("/tasks/import", writer.FormDataContentType(), strings.NewReader("--"+writer.Boundary()+"--\r\n"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Missing file field"}`, w.Body.String())
+	})
+
+	t.Run("Rejected rows", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksCSV(gomock.Any(), gomock.Any()).Return(nil, []entity.ImportRowError{
+			{Line: 2, Field: "priority", Err: fmt.Errorf("%w: urgent", services.ErrInvalidPriority)},
+			{Line: 5, Field: "list_id", Err: services.ErrListNotFound},
+			{Line: 7, Err: fmt.Errorf("%w: bare \" in non-quoted field", services.ErrInvalidImport)},
+		}, nil).Times(1)
+
+		w := post("/tasks/import", "text/csv", strings.NewReader(file))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{
+			"error": "Rows were rejected, nothing was imported",
+			"errors": [
+				{"line": 2, "field": "priority", "error": "Priority must be one of less, medium, high"},
+				{"line": 5, "field": "list_id", "error": "List not found"},
+				{"line": 7, "error": "invalid import file: bare \" in non-quoted field"}
+			]
+		}`, w.Body.String())
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksCSV(gomock.Any(), gomock.Any()).Return(nil, nil, fmt.Errorf("%w: no name column", services.ErrInvalidImport)).Times(1)
+		w := post("/tasks/import", "text/csv", strings.NewReader("title\n"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid import file: no name column"}`, w.Body.String())
+
+		w = post("/tasks/import?dry_run=maybe", "text/csv", strings.NewReader(file))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		mockService.EXPECT().ImportTasksCSV(gomock.Any(), gomock.Any()).Return(nil, nil, errors.New("disk full")).Times(1)
+		w = post("/tasks/import", "text/csv", strings.NewReader(file))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestExportTasksICal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		tc.ExportTasksICal(ginContext)
+		return w
+	}
+	created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	tasks := []entity.Task{
+		{ID: 1, UID: "task-1@todo-lists", Name: "Write report", Deadline: due(time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)), Priority: "high", Status: entity.StatusTodo, Version: 1, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, UID: "task-2@todo-lists", Name: "Review report", Priority: "medium", Status: entity.StatusTodo, Version: 1, CreatedAt: created, UpdatedAt: created},
+	}
+
+	t.Run("Todos", func(t *testing.T) {
+		mockService.EXPECT().ExportTasks(gomock.Any(), time.UTC).DoAndReturn(func(filter entity.TaskFilter, loc *time.Location) ([]entity.Task, error) {
+			assert.Equal(t, uint(3), filter.ListID)
+			assert.Equal(t, []string{"work"}, filter.Labels)
+			return tasks, nil
+		}).Times(1)
+
+		w := get("/tasks/export.ics?list_id=3&tag=work")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="tasks.ics"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, 2, strings.Count(w.Body.String(), "BEGIN:VTODO"))
+		assert.Contains(t, w.Body.String(), "UID:task-1@todo-lists\r\n")
+		assert.Contains(t, w.Body.String(), "DUE:20261102T170000Z\r\n")
+		assert.Contains(t, w.Body.String(), "X-WR-CALNAME:Tasks\r\n")
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		mockService.EXPECT().ExportTasks(gomock.Any(), gomock.Any()).Return(tasks, nil).Times(1)
+
+		w := get("/tasks/export.ics?component=event")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, strings.Count(w.Body.String(), "BEGIN:VEVENT"))
+		assert.NotContains(t, w.Body.String(), "VTODO")
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		w := get("/tasks/export.ics?component=journal")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Component must be todo or event"}`, w.Body.String())
+
+		w = get("/tasks/export.ics?list_id=backend")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+	})
+}
+
+func TestGetFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(token, query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "token", Value: token}}
+		ginContext.Request = httptest.NewRequest(http.MethodGet, "/feeds/"+token+query, nil)
+		tc.GetFeed(ginContext)
+		return w
+	}
+
+	t.Run("Feed of a list", func(t *testing.T) {
+		created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+		mockService.EXPECT().GetFeedTasks("s3cr3t").Return(entity.List{ID: 1, Name: "Backend"}, []entity.Task{
+			{ID: 1, UID: "task-1@todo-lists", ListID: 1, Name: "Write report", Priority: "high", Status: entity.StatusTodo, Version: 1, CreatedAt: created, UpdatedAt: created},
+		}, nil).Times(2)
+
+		w := get("s3cr3t.ics", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.Contains(t, w.Body.String(), "X-WR-CALNAME:Backend\r\n")
+		assert.Contains(t, w.Body.String(), "REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n")
+		assert.Contains(t, w.Body.String(), "UID:task-1@todo-lists\r\n")
+
+		// The extension is optional
+		w = get("s3cr3t", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		mockService.EXPECT().GetFeedTasks("revoked").Return(entity.List{}, nil, gorm.ErrRecordNotFound).Times(1)
+
+		w := get("revoked.ics", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "Feed not found"}`, w.Body.String())
+	})
+
+	t.Run("Error fetching feed", func(t *testing.T) {
+		mockService.EXPECT().GetFeedTasks("s3cr3t").Return(entity.List{}, nil, errors.New("connection refused")).Times(1)
+
+		w := get("s3cr3t.ics", "?component=event")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestImportTasksICal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(target string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		ginContext.Request.Header.Set("Content-Type", "text/calendar")
+		tc.ImportTasksICal(ginContext)
+		return w
+	}
+	file := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Write report\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	t.Run("Successful import", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksICal(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.ICalImport) (entity.ImportResult, []entity.ImportRowError, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, file, string(content))
+			assert.Equal(t, uint(2), options.ListID)
+			assert.Equal(t, "Europe/Berlin", options.Location.String())
+			assert.False(t, options.DryRun)
+			return entity.ImportResult{Tasks: []entity.Task{{ID: 4, Name: "Write report"}, {ID: 1, Name: "Review"}}, Created: 1, Updated: 1}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import.ics?list_id=2&tz=Europe/Berlin", file)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var body struct {
+			Data    []entity.Task `json:"data"`
+			Created int           `json:"created"`
+			Updated int           `json:"updated"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Created)
+		assert.Equal(t, 1, body.Updated)
+		assert.Len(t, body.Data, 2)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksICal(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.ICalImport) (entity.ImportResult, []entity.ImportRowError, error) {
+			assert.True(t, options.DryRun)
+			return entity.ImportResult{Tasks: []entity.Task{{Name: "Write report"}}, Created: 1}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import.ics?dry_run=1", file)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	})
+
+	t.Run("Rejected components", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksICal(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, []entity.ImportRowError{
+			{Line: 2, Field: "status", Err: services.ErrIllegalTransition},
+		}, nil).Times(1)
+
+		w := post("/tasks/import.ics", file)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{
+			"error": "Rows were rejected, nothing was imported",
+			"errors": [{"line": 2, "field": "status", "error": "Task cannot move to done"}]
+		}`, w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksICal(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, nil, fmt.Errorf("%w: no VTODO components", services.ErrInvalidImport)).Times(1)
+		w := post("/tasks/import.ics", file)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid import file: no VTODO components"}`, w.Body.String())
+
+		mockService.EXPECT().ImportTasksICal(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, nil, services.ErrListNotFound).Times(1)
+		w = post("/tasks/import.ics?list_id=9", file)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+
+		w = post("/tasks/import.ics?list_id=-1", file)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+	})
+}
+
+func TestExportTasksTodoTxt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		tc.ExportTasksTodoTxt(ginContext)
+		return w
+	}
+
+	t.Run("Filtered export", func(t *testing.T) {
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+		mockService.EXPECT().ExportLabeledTasks(gomock.Any(), berlin).DoAndReturn(func(filter entity.TaskFilter, loc *time.Location) ([]entity.LabeledTask, error) {
+			assert.Equal(t, uint(2), filter.ListID)
+			assert.Equal(t, []string{"work"}, filter.Labels)
+			return []entity.LabeledTask{{
+				Task:   entity.Task{ID: 1, Name: "Write report", Deadline: due(time.Date(2026, 11, 2, 23, 30, 0, 0, time.UTC)), Priority: "high", Status: entity.StatusTodo, CreatedAt: created},
+				List:   "Home Office",
+				Labels: []string{"work"},
+			}}, nil
+		}).Times(1)
+
+		w := get("/tasks/export.todotxt?list_id=2&tag=work&tz=Europe/Berlin")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="todo.txt"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "(A) 2026-11-01 Write report +Home_Office @work due:2026-11-03\n", w.Body.String())
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		w := get("/tasks/export.todotxt?tz=Mars/Olympus")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		mockService.EXPECT().ExportLabeledTasks(gomock.Any(), gomock.Any()).Return(nil, services.ErrInvalidStatus).Times(1)
+		w = get("/tasks/export.todotxt?status=finished")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid task status"}`, w.Body.String())
+
+		mockService.EXPECT().ExportLabeledTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error")).Times(1)
+		w = get("/tasks/export.todotxt")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error exporting tasks"}`, w.Body.String())
+	})
+}
+
+func TestImportTasksTodoTxt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(target string, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		ginContext.Request.Header.Set("Content-Type", "text/plain")
+		tc.ImportTasksTodoTxt(ginContext)
+		return w
+	}
+	file := "(A) Write report +work due:2026-11-02\nx Review\n"
+
+	t.Run("Successful import", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksTodoTxt(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.TodoTxtImport) ([]entity.Task, []entity.ImportRowError, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, file, string(content))
+			assert.Equal(t, uint(3), options.ListID)
+			assert.Equal(t, "Europe/Berlin", options.Location.String())
+			assert.False(t, options.DryRun)
+			return []entity.Task{{ID: 1, Name: "Write report"}, {ID: 2, Name: "Review"}}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import.todotxt?list_id=3&tz=Europe/Berlin", file)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var body struct {
+			Data     []entity.Task `json:"data"`
+			Imported int           `json:"imported"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Imported)
+		assert.Len(t, body.Data, 2)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksTodoTxt(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.TodoTxtImport) ([]entity.Task, []entity.ImportRowError, error) {
+			assert.True(t, options.DryRun)
+			return []entity.Task{{Name: "Write report"}}, nil, nil
+		}).Times(1)
+
+		w := post("/tasks/import.todotxt?dry_run=true", file)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	})
+
+	t.Run("Rejected lines", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksTodoTxt(gomock.Any(), gomock.Any()).Return(nil, []entity.ImportRowError{
+			{Line: 1, Field: "deadline", Err: fmt.Errorf("%w: due:tomorrow is not a date", services.ErrInvalidTask)},
+		}, nil).Times(1)
+
+		w := post("/tasks/import.todotxt", file)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{
+			"error": "Rows were rejected, nothing was imported",
+			"errors": [{"line": 1, "field": "deadline", "error": "invalid task: due:tomorrow is not a date"}]
+		}`, w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksTodoTxt(gomock.Any(), gomock.Any()).Return(nil, nil, fmt.Errorf("%w: no tasks", services.ErrInvalidImport)).Times(1)
+		w := post("/tasks/import.todotxt", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid import file: no tasks"}`, w.Body.String())
+
+		w = post("/tasks/import.todotxt?dry_run=maybe", file)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = post("/tasks/import.todotxt?list_id=abc", file)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+	})
+}
+
+func TestExportListTasksMarkdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(target, listId string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		ginContext.Params = gin.Params{{Key: "id", Value: listId}}
+		tc.ExportListTasksMarkdown(ginContext)
+		return w
+	}
+
+	t.Run("Successful export", func(t *testing.T) {
+		berlin, _ := time.LoadLocation("Europe/Berlin")
+		mockService.EXPECT().ExportListTasks(1, gomock.Any(), berlin).DoAndReturn(func(listId int, filter entity.TaskFilter, loc *time.Location) (entity.List, []entity.LabeledTask, error) {
+			assert.Equal(t, entity.StatusTodo, filter.Status)
+			return entity.List{ID: 1, Name: "Backend"}, []entity.LabeledTask{
+				{Task: entity.Task{ID: 4, Name: "Write report", Deadline: due(time.Date(2026, 11, 1, 23, 0, 0, 0, time.UTC)), Status: entity.StatusTodo}, Labels: []string{"work"}},
+			}, nil
+		}).Times(1)
+
+		w := get("/lists/1/tasks/export.md?status=todo&tz=Europe/Berlin", "1")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/markdown; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="tasks.md"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "# Backend\n\n## work\n\n- [ ] Write report (due 2026-11-02) <!-- task:4 -->\n", w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		w := get("/lists/abc/tasks/export.md", "abc")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+
+		mockService.EXPECT().ExportListTasks(9, gomock.Any(), gomock.Any()).Return(entity.List{}, nil, gorm.ErrRecordNotFound).Times(1)
+		w = get("/lists/9/tasks/export.md", "9")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+
+		mockService.EXPECT().ExportListTasks(1, gomock.Any(), gomock.Any()).Return(entity.List{}, nil, services.ErrInvalidStatus).Times(1)
+		w = get("/lists/1/tasks/export.md?status=finished", "1")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid task status"}`, w.Body.String())
+	})
+}
+
+func TestImportListTasksMarkdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIService(ctrl)
+	tc := TaskController{
+		Service: mockService,
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(target, listId, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		ginContext.Request.Header.Set("Content-Type", "text/markdown")
+		ginContext.Params = gin.Params{{Key: "id", Value: listId}}
+		tc.ImportListTasksMarkdown(ginContext)
+		return w
+	}
+	document := "# Backend\n\n- [x] Write report <!-- task:4 -->\n- [ ] Review\n"
+
+	t.Run("Successful import", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksMarkdown(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.MarkdownImport) (entity.ImportResult, []entity.ImportRowError, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, document, string(content))
+			assert.Equal(t, uint(1), options.ListID)
+			assert.Equal(t, "Europe/Berlin", options.Location.String())
+			assert.False(t, options.DryRun)
+			return entity.ImportResult{Tasks: []entity.Task{{ID: 4, Name: "Write report"}, {ID: 5, Name: "Review"}}, Created: 1, Updated: 1}, nil, nil
+		}).Times(1)
+
+		w := post("/lists/1/tasks/import.md?tz=Europe/Berlin", "1", document)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var body struct {
+			Data    []entity.Task `json:"data"`
+			Created int           `json:"created"`
+			Updated int           `json:"updated"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 1, body.Created)
+		assert.Equal(t, 1, body.Updated)
+		assert.Len(t, body.Data, 2)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksMarkdown(gomock.Any(), gomock.Any()).DoAndReturn(func(r io.Reader, options entity.MarkdownImport) (entity.ImportResult, []entity.ImportRowError, error) {
+			assert.True(t, options.DryRun)
+			return entity.ImportResult{Tasks: []entity.Task{{Name: "Review"}}, Created: 1}, nil, nil
+		}).Times(1)
+
+		w := post("/lists/1/tasks/import.md?dry_run=true", "1", document)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"dry_run":true`)
+	})
+
+	t.Run("Rejected items", func(t *testing.T) {
+		mockService.EXPECT().ImportTasksMarkdown(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, []entity.ImportRowError{
+			{Line: 3, Field: "id", Err: fmt.Errorf("%w: task 4 does not exist", services.ErrInvalidTask)},
+		}, nil).Times(1)
+
+		w := post("/lists/1/tasks/import.md", "1", document)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.JSONEq(t, `{
+			"error": "Rows were rejected, nothing was imported",
+			"errors": [{"line": 3, "field": "id", "error": "invalid task: task 4 does not exist"}]
+		}`, w.Body.String())
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		w := post("/lists/abc/tasks/import.md", "abc", document)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid list ID"}`, w.Body.String())
+
+		mockService.EXPECT().ImportTasksMarkdown(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, nil, services.ErrListNotFound).Times(1)
+		w = post("/lists/9/tasks/import.md", "9", document)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "List not found"}`, w.Body.String())
+
+		mockService.EXPECT().ImportTasksMarkdown(gomock.Any(), gomock.Any()).Return(entity.ImportResult{}, nil, fmt.Errorf("%w: no task list items", services.ErrInvalidImport)).Times(1)
+		w = post("/lists/1/tasks/import.md", "1", "# Backend\n")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid import file: no task list items"}`, w.Body.String())
+	})
+}
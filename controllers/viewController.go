@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"todo-lists/entity"
+	"todo-lists/query"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ViewController struct {
+	Service services.IViewService
+}
+
+// CreateView method saves a new view
+func (c *ViewController) CreateView(ctx *gin.Context) {
+	var view entity.View
+	if err := ctx.ShouldBindJSON(&view); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	view.ID = ""
+
+	if err := c.Service.CreateView(&view); err != nil {
+		respondViewError(ctx, err, "Error creating view")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, view)
+}
+
+// GetViews method retrieves the built-in and saved views and responds with JSON
+func (c *ViewController) GetViews(ctx *gin.Context) {
+	views, err := c.Service.GetAllViews()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching views"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views)
+}
+
+// GetViewById method retrieves a view by ID or built-in key and responds with JSON
+func (c *ViewController) GetViewById(ctx *gin.Context) {
+	view, err := c.Service.GetView(ctx.Param("id"))
+	if err != nil {
+		respondViewError(ctx, err, "Error fetching view")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// UpdateView method handles renaming a saved view or replacing its query
+func (c *ViewController) UpdateView(ctx *gin.Context) {
+	var view entity.View
+	if err := ctx.ShouldBindJSON(&view); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	view.ID = ctx.Param("id")
+
+	if err := c.Service.UpdateView(&view); err != nil {
+		respondViewError(ctx, err, "Error updating view")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, view)
+}
+
+// DeleteView method deletes a saved view by ID
+func (c *ViewController) DeleteView(ctx *gin.Context) {
+	if err := c.Service.DeleteView(ctx.Param("id")); err != nil {
+		respondViewError(ctx, err, "Error deleting view")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "View deleted successfully"})
+}
+
+// GetViewTasks method evaluates a view in the time zone of the tz parameter and responds with a page of its
+// tasks and the counts over all of them
+func (c *ViewController) GetViewTasks(ctx *gin.Context) {
+	page, ok := pageParams(ctx)
+	if !ok {
+		return
+	}
+	loc, ok := timeZoneParam(ctx)
+	if !ok {
+		return
+	}
+
+	tasks, err := c.Service.GetViewTasks(ctx.Param("id"), loc, page)
+	if err != nil {
+		respondViewError(ctx, err, "Error fetching view tasks")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// respondViewError maps view service errors to responses, using 500 with the given message for unknown errors
+func respondViewError(ctx *gin.Context, err error, message string) {
+	var queryErr *query.Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+	case errors.As(err, &queryErr):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": queryErr.Error(), "position": queryErr.Pos})
+	case errors.Is(err, services.ErrInvalidView):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrViewExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "View already exists"})
+	case errors.Is(err, services.ErrBuiltInView):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Built-in views cannot be changed"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/query"
+	"todo-lists/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCreateView(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIViewService(ctrl)
+	vc := ViewController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Request = httptest.NewRequest(http.MethodPost, "/views", strings.NewReader(body))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		vc.CreateView(ginContext)
+		return w
+	}
+
+	t.Run("Successful creation", func(t *testing.T) {
+		mockService.EXPECT().CreateView(gomock.Any()).DoAndReturn(func(view *entity.View) error {
+			assert.Empty(t, view.ID)
+			view.ID = "1"
+			return nil
+		}).Times(1)
+
+		w := post(`{"id": "today", "name": "Work", "query": "label:work"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id": "1", "name": "Work", "query": "label:work", "built_in": false}`, w.Body.String())
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		mockService.EXPECT().CreateView(gomock.Any()).
+			Return(&query.Error{Pos: 10, Msg: "deadline must be a YYYY-MM-DD date"}).Times(1)
+
+		w := post(`{"name": "Broken", "query": "deadline<someday"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid query at position 10: deadline must be a YYYY-MM-DD date", "position": 10}`, w.Body.String())
+	})
+
+	t.Run("Invalid view", func(t *testing.T) {
+		mockService.EXPECT().CreateView(gomock.Any()).Return(services.ErrInvalidView).Times(1)
+
+		w := post(`{"name": "", "query": "label:work"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "invalid view"}`, w.Body.String())
+	})
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		mockService.EXPECT().CreateView(gomock.Any()).Return(services.ErrViewExists).Times(1)
+
+		w := post(`{"name": "Today", "query": "label:work"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"error": "View already exists"}`, w.Body.String())
+	})
+
+	t.Run("Invalid input", func(t *testing.T) {
+		w := post(`{"name": 1}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Invalid input"}`, w.Body.String())
+	})
+}
+
+func TestChangeView(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIViewService(ctrl)
+	vc := ViewController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	send := func(method, id, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		ginContext.Request = httptest.NewRequest(method, "/views/"+id, strings.NewReader(body))
+		ginContext.Request.Header.Set("Content-Type", "application/json")
+		if method == http.MethodPut {
+			vc.UpdateView(ginContext)
+		} else {
+			vc.DeleteView(ginContext)
+		}
+		return w
+	}
+
+	t.Run("Successful update", func(t *testing.T) {
+		mockService.EXPECT().UpdateView(&entity.View{ID: "2", Name: "Work", Query: "label:work"}).Return(nil).Times(1)
+
+		w := send(http.MethodPut, "2", `{"id": "5", "name": "Work", "query": "label:work"}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id": "2", "name": "Work", "query": "label:work", "built_in": false}`, w.Body.String())
+	})
+
+	t.Run("Built-in view", func(t *testing.T) {
+		mockService.EXPECT().UpdateView(gomock.Any()).Return(services.ErrBuiltInView).Times(1)
+		mockService.EXPECT().DeleteView("today").Return(services.ErrBuiltInView).Times(1)
+
+		w := send(http.MethodPut, "today", `{"name": "Today", "query": "label:work"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"error": "Built-in views cannot be changed"}`, w.Body.String())
+
+		w = send(http.MethodDelete, "today", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Successful deletion", func(t *testing.T) {
+		mockService.EXPECT().DeleteView("2").Return(nil).Times(1)
+
+		w := send(http.MethodDelete, "2", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"message": "View deleted successfully"}`, w.Body.String())
+	})
+
+	t.Run("View not found", func(t *testing.T) {
+		mockService.EXPECT().DeleteView("9").Return(gorm.ErrRecordNotFound).Times(1)
+
+		w := send(http.MethodDelete, "9", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error": "View not found"}`, w.Body.String())
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().UpdateView(gomock.Any()).Return(errors.New("db error")).Times(1)
+
+		w := send(http.MethodPut, "2", `{"name": "Work", "query": "label:work"}`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "Error updating view"}`, w.Body.String())
+	})
+}
+
+func TestGetViewTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockIViewService(ctrl)
+	vc := ViewController{Service: mockService}
+
+	gin.SetMode(gin.TestMode)
+
+	get := func(id, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		ginContext, _ := gin.CreateTestContext(w)
+		ginContext.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		ginContext.Request = httptest.NewRequest(http.MethodGet, target, nil)
+		vc.GetViewTasks(ginContext)
+		return w
+	}
+
+	t.Run("Tasks and counts", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		assert.NoError(t, err)
+		page := entity.PageRequest{Limit: 1, Sort: "deadline"}
+		mockService.EXPECT().GetViewTasks("overdue", berlin, page).Return(entity.ViewTasks{
+			View:     entity.View{ID: "overdue", Name: "Overdue", Query: "deadline<today", BuiltIn: true},
+			TaskPage: entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Write report"}}, Next: "abc"},
+			Counts:   entity.ViewCounts{Total: 3, Open: 3, Overdue: 3},
+		}, nil).Times(1)
+
+		w := get("overdue", "/views/overdue/tasks?limit=1&sort=deadline&tz=Europe/Berlin")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `"view":{"id":"overdue","name":"Overdue","query":"deadline\u003ctoday","built_in":true}`)
+		assert.Contains(t, body, `"name":"Write report"`)
+		assert.Contains(t, body, `"next":"abc"`)
+		assert.Contains(t, body, `"counts":{"total":3,"open":3,"overdue":3}`)
+	})
+
+	t.Run("Unknown time zone", func(t *testing.T) {
+		w := get("today", "/views/today/tasks?tz=Local")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error": "Unknown time zone"}`, w.Body.String())
+	})
+
+	t.Run("View not found", func(t *testing.T) {
+		mockService.EXPECT().GetViewTasks("someday", time.UTC, gomock.Any()).Return(entity.ViewTasks{}, gorm.ErrRecordNotFound).Times(1)
+
+		w := get("someday", "/views/someday/tasks")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
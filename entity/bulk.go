@@ -0,0 +1,44 @@
+package entity
+
+import "encoding/json"
+
+// Bulk operation kinds
+const (
+	BulkCreate   = "create"
+	BulkUpdate   = "update"
+	BulkDelete   = "delete"
+	BulkComplete = "complete"
+	// BulkShiftDeadline moves the deadline of every task a filter matches by a number of days
+	BulkShiftDeadline = "shift_deadline"
+)
+
+// BulkRequest is either a batch of operations or a filter with an action applied to every task it matches.
+// An atomic request applies all of them or none, otherwise each one succeeds or fails on its own.
+type BulkRequest struct {
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations"`
+	Filter     string          `json:"filter"`
+	Action     *BulkOperation  `json:"action"`
+}
+
+// BulkOperation is one step of a bulk request. Task is the task to create and Patch the JSON Merge Patch an
+// update applies. A non-zero Version must match the task for an update or delete. As the action of a filter
+// the operation has no ID and applies to each matching task in turn.
+type BulkOperation struct {
+	Op      string          `json:"op"`
+	ID      uint            `json:"id"`
+	Version uint            `json:"version"`
+	Task    *Task           `json:"task"`
+	Patch   json.RawMessage `json:"patch"`
+	Days    int             `json:"days"`
+}
+
+// BulkResult is the outcome of one operation of a bulk request, in request order or, for a filter, in the
+// order of the task IDs. Task is the task after a create, update or complete; Err is set when it failed.
+type BulkResult struct {
+	Index int
+	Op    string
+	ID    uint
+	Task  *Task
+	Err   error
+}
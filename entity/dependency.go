@@ -0,0 +1,15 @@
+package entity
+
+// Dependency is an edge between two tasks: the task cannot start until the blocker is done
+type Dependency struct {
+	TaskID    uint `json:"task_id"`
+	BlockerID uint `json:"blocker_id"`
+}
+
+// PlanItem is a task in a plan, with the open tasks it waits for. DeadlineConflict is set when the task is
+// due before one of its blockers.
+type PlanItem struct {
+	Task
+	BlockedBy        []uint `json:"blocked_by"`
+	DeadlineConflict bool   `json:"deadline_conflict"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// MaxIdempotencyKeyLength is the longest Idempotency-Key the idempotency_keys table can hold
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is the response stored for an Idempotency-Key. RequestHash identifies the request the key
+// was first used with, and Status is 0 until that request has been handled.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	Status      int
+	Header      map[string]string
+	Body        []byte
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "regexp"
+
+// DefaultLabelColor is the color of a label created without one
+const DefaultLabelColor = "#808080"
+
+// MaxLabelNameLength is the longest label name the labels table can hold
+const MaxLabelNameLength = 64
+
+var labelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Label is a user defined label with a display color in #rrggbb form
+type Label struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ValidLabelColor reports whether color is a hex color in #rrggbb form
+func ValidLabelColor(color string) bool {
+	return labelColor.MatchString(color)
+}
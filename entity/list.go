@@ -0,0 +1,14 @@
+package entity
+
+type List struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ListFeed is the calendar feed of a list. Only the SHA-256 hash of its token is stored, so the feed URL
+// cannot be read back and a lost one is replaced by a new token.
+type ListFeed struct {
+	ListID    uint
+	TokenHash string
+}
@@ -0,0 +1,8 @@
+package entity
+
+// Occurrence is a task instance due within a date window. A virtual occurrence is a future instance of a
+// recurring task that has not been created yet; it carries the ID of the recurring task it comes from.
+type Occurrence struct {
+	Task
+	Virtual bool `json:"virtual"`
+}
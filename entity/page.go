@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPageLimit is used when a request does not ask for a page size
+	DefaultPageLimit = 50
+	// MaxPageLimit caps the page size a client can request
+	MaxPageLimit = 200
+)
+
+// SortFields lists the task fields a page can be ordered by, and their aliases
+var SortFields = []string{"id", "deadline", "name", "priority", "tag"}
+
+// sortAliases maps the sort fields kept for older clients to the fields they order by. The priority was the
+// tag of a task before labels were added.
+var sortAliases = map[string]string{"tag": "priority"}
+
+// SortRank orders search results by relevance, best match first. It is the default order of searches and
+// cannot be reversed.
+const SortRank = "rank"
+
+var (
+	ErrInvalidLimit  = errors.New("invalid page limit")
+	ErrInvalidSort   = errors.New("invalid sort field")
+	ErrInvalidCursor = errors.New("invalid page cursor")
+)
+
+// PageRequest describes one page of a keyset paginated task query
+type PageRequest struct {
+	Limit int
+	Sort  string
+	Desc  bool
+	After *Cursor
+}
+
+// Cursor marks the last row of a page: the value of the sort field and the row ID as a tie breaker
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v,omitempty"`
+	ID    uint   `json:"i"`
+}
+
+// TaskPage is the response envelope for paginated task queries
+type TaskPage struct {
+	Tasks []Task `json:"data"`
+	Next  string `json:"next,omitempty"`
+}
+
+// ParsePageRequest validates the limit, sort and cursor query values. Sort is one of SortFields, prefixed
+// with "-" for descending order, and a cursor is only accepted for the sort it was issued for.
+func ParsePageRequest(limit, sort, cursor string) (PageRequest, error) {
+	return parsePageRequest(limit, sort, cursor, "id", SortFields)
+}
+
+// ParseSearchPageRequest is ParsePageRequest for search results, which may also be sorted by SortRank
+// and are by default
+func ParseSearchPageRequest(limit, sort, cursor string) (PageRequest, error) {
+	if sort == "-"+SortRank {
+		return PageRequest{}, ErrInvalidSort
+	}
+	return parsePageRequest(limit, sort, cursor, SortRank, append([]string{SortRank}, SortFields...))
+}
+
+func parsePageRequest(limit, sort, cursor, defaultSort string, fields []string) (PageRequest, error) {
+	page := PageRequest{Limit: DefaultPageLimit, Sort: defaultSort}
+
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > MaxPageLimit {
+			return PageRequest{}, ErrInvalidLimit
+		}
+		page.Limit = n
+	}
+
+	if sort != "" {
+		page.Desc = strings.HasPrefix(sort, "-")
+		page.Sort = strings.TrimPrefix(sort, "-")
+		if !validSortField(page.Sort, fields) {
+			return PageRequest{}, ErrInvalidSort
+		}
+		if field, ok := sortAliases[page.Sort]; ok {
+			page.Sort = field
+		}
+	}
+
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil || after.Sort != page.sortKey() {
+			return PageRequest{}, ErrInvalidCursor
+		}
+		// An empty deadline marks a task without one
+		if page.Sort == "deadline" && after.Value != "" {
+			if _, err := time.Parse(time.RFC3339Nano, after.Value); err != nil {
+				return PageRequest{}, ErrInvalidCursor
+			}
+		}
+		if page.Sort == SortRank && after.Value != "" {
+			if _, err := strconv.ParseFloat(after.Value, 32); err != nil {
+				return PageRequest{}, ErrInvalidCursor
+			}
+		}
+		page.After = &after
+	}
+
+	return page, nil
+}
+
+// NewCursor builds the cursor that continues a page after the row with the given sort value and ID
+func (p PageRequest) NewCursor(value string, id uint) Cursor {
+	return Cursor{Sort: p.sortKey(), Value: value, ID: id}
+}
+
+// sortKey identifies sort field and direction, so a cursor cannot be replayed against another order
+func (p PageRequest) sortKey() string {
+	if p.Desc {
+		return "-" + p.Sort
+	}
+	return p.Sort
+}
+
+// Encode returns the opaque string form of the cursor handed to clients
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor parses a cursor previously returned by Encode
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+func validSortField(field string, fields []string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package entity
+
+// Task priorities
+const (
+	PriorityLess   = "less"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
+// ValidPriority reports whether priority is one of the known task priorities
+func ValidPriority(priority string) bool {
+	return priority == PriorityLess || priority == PriorityMedium || priority == PriorityHigh
+}
+
+// PriorityRank orders priorities from less to high, for sorting. Unknown priorities rank with high.
+func PriorityRank(priority string) int {
+	switch priority {
+	case PriorityLess:
+		return 0
+	case PriorityMedium:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,35 @@
+package entity
+
+// Task lifecycle states
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusBlocked    = "blocked"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+// transitions lists the states a task may move to from each state
+var transitions = map[string][]string{
+	StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusBlocked, StatusDone, StatusCancelled},
+	StatusBlocked:    {StatusInProgress, StatusCancelled},
+	StatusDone:       {StatusTodo},
+	StatusCancelled:  {StatusTodo},
+}
+
+// ValidStatus reports whether status is one of the known lifecycle states
+func ValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// CanTransition reports whether a task may move from one state to another
+func CanTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
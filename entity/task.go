@@ -3,8 +3,32 @@ package entity
 import "time"
 
 type Task struct {
-	ID       uint      `json:"id"`
-	Name     string    `json:"name"`
-	Deadline time.Time `json:"deadline"`
-	Tag      string    `json:"tag"`
+	ID          uint       `json:"id"`
+	ListID      uint       `json:"list_id"`
+	ParentID    uint       `json:"parent_id"`
+	Name        string     `json:"name"`
+	Deadline    *time.Time `json:"deadline"`
+	Priority    string     `json:"priority"`
+	Status      string     `json:"status"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Version     uint       `json:"version"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	UID         string     `json:"uid,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CompareDeadlines orders two optional deadlines like the deadline sort of the repositories: a task without a
+// deadline comes after every task with one
+func CompareDeadlines(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
 }
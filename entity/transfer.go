@@ -0,0 +1,83 @@
+package entity
+
+import "time"
+
+// TaskFilter selects the tasks of an export. Every field that is set narrows the selection, and an empty
+// filter selects all tasks. Query is an expression of the query language, Keyword a substring of the name,
+// and Start and End include the tasks due on them.
+type TaskFilter struct {
+	ListID   uint
+	Query    string
+	Status   string
+	Labels   []string
+	MatchAll bool
+	Keyword  string
+	Start    *time.Time
+	End      *time.Time
+}
+
+// CSVImport configures a CSV import. Columns maps a task field to the header of the column holding it; a
+// field that is not mapped is read from the column named like the field, if there is one. Dates without a
+// time zone are read in Location. A dry run checks every row without storing anything.
+type CSVImport struct {
+	Columns  map[string]string
+	DryRun   bool
+	Location *time.Location
+}
+
+// ICalExport configures an iCalendar export. Tasks are VTODO components, or with Events VEVENT components
+// at their deadline, which calendars without task support show. Name names the calendar, and a Refresh
+// interval is suggested to clients that subscribe to it.
+type ICalExport struct {
+	Name    string
+	Events  bool
+	Refresh time.Duration
+}
+
+// ICalImport configures an iCalendar import. Tasks it creates go to the list ListID, or to none when it is
+// zero, and floating times and dates are read in Location. A dry run checks everything without storing it.
+type ICalImport struct {
+	ListID   uint
+	DryRun   bool
+	Location *time.Location
+}
+
+// TodoTxtImport configures a todo.txt import. Tasks without a +project go to the list ListID, or to none
+// when it is zero, and due dates are days in Location. A dry run checks everything without storing it.
+type TodoTxtImport struct {
+	ListID   uint
+	DryRun   bool
+	Location *time.Location
+}
+
+// MarkdownImport configures a Markdown import into the list ListID. Due dates without a time zone are read in
+// Location. A dry run checks everything without storing it.
+type MarkdownImport struct {
+	ListID   uint
+	DryRun   bool
+	Location *time.Location
+}
+
+// LabeledTask is a task of an export with the name of its list, empty when it has none, and the names of its
+// labels in name order
+type LabeledTask struct {
+	Task
+	List   string
+	Labels []string
+}
+
+// ImportResult is the outcome of an import that updates the tasks it finds again: the tasks in the order of
+// the file, and how many of them were created and updated
+type ImportResult struct {
+	Tasks   []Task
+	Created int
+	Updated int
+}
+
+// ImportRowError is a row an import rejects, with its line in the file, starting at 1 for the header, and the
+// field at fault when there is one
+type ImportRowError struct {
+	Line  int
+	Field string
+	Err   error
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// TaskTree is a task with its subtasks, nested to any depth
+type TaskTree struct {
+	Task
+	Progress Progress   `json:"progress"`
+	Subtasks []TaskTree `json:"subtasks"`
+}
+
+// Progress rolls up the subtasks of a task at every depth. Cancelled subtasks do not count. Percent is the
+// share of the counted subtasks that are done, and EarliestDeadline the first deadline among the open ones.
+type Progress struct {
+	Total            int        `json:"total"`
+	Done             int        `json:"done"`
+	Percent          int        `json:"percent"`
+	EarliestDeadline *time.Time `json:"earliest_deadline"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+// MaxViewNameLength is the longest view name the views table can hold
+const MaxViewNameLength = 64
+
+// View is a named task query. Saved views have numeric IDs; the built-in views are identified by a key such
+// as "today" and cannot be changed.
+type View struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Query   string `json:"query"`
+	BuiltIn bool   `json:"built_in"`
+}
+
+// ViewCounts summarizes all tasks a view matches, not just the page returned with them. Open counts the tasks
+// that are neither done nor cancelled, and Overdue the open ones due before today.
+type ViewCounts struct {
+	Total   int64 `json:"total"`
+	Open    int64 `json:"open"`
+	Overdue int64 `json:"overdue"`
+}
+
+// ViewTasks is a page of the tasks a view matches, with the view and its counts
+type ViewTasks struct {
+	View View `json:"view"`
+	TaskPage
+	Counts ViewCounts `json:"counts"`
+}
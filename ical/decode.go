@@ -0,0 +1,172 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalid is returned for data that is not iCalendar
+var ErrInvalid = errors.New("invalid iCalendar data")
+
+// Decode reads one component, normally a VCALENDAR, with everything nested in it. Folded lines are unfolded,
+// names are upper case, and lines may end with CRLF or LF alone. Values are kept as written, so text values
+// are unescaped with Unescape.
+func Decode(r io.Reader) (Component, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var stack []*Component
+	var root *Component
+	var line string
+	start, number := 0, 0
+
+	// handle processes the content line that ends before the current one
+	handle := func() error {
+		if line == "" {
+			return nil
+		}
+		property, err := parseLine(line)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalid, start, err)
+		}
+
+		switch property.Name {
+		case "BEGIN":
+			if root != nil && len(stack) == 0 {
+				return fmt.Errorf("%w: line %d: content after END:%s", ErrInvalid, start, root.Name)
+			}
+			stack = append(stack, &Component{Name: strings.ToUpper(property.Value), Line: start})
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return fmt.Errorf("%w: line %d: unexpected END:%s", ErrInvalid, start, property.Value)
+			}
+			done := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				root = done
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, *done)
+			}
+		default:
+			if len(stack) == 0 {
+				return fmt.Errorf("%w: line %d: %s outside of a component", ErrInvalid, start, property.Name)
+			}
+			current := stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+		return nil
+	}
+
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line += text[1:]
+			continue
+		}
+		if err := handle(); err != nil {
+			return Component{}, err
+		}
+		line, start = text, number
+	}
+	if err := scanner.Err(); err != nil {
+		return Component{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if err := handle(); err != nil {
+		return Component{}, err
+	}
+
+	if len(stack) > 0 {
+		return Component{}, fmt.Errorf("%w: missing END:%s", ErrInvalid, stack[len(stack)-1].Name)
+	}
+	if root == nil {
+		return Component{}, fmt.Errorf("%w: no component", ErrInvalid)
+	}
+	return *root, nil
+}
+
+// parseLine reads a content line: a name, parameters separated by semicolons and a value after the first
+// colon that is not inside a quoted parameter value
+func parseLine(line string) (Property, error) {
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return Property{}, errors.New("expected a property name and a value")
+	}
+	property := Property{Name: strings.ToUpper(line[:end])}
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.Index(rest, "=")
+		if eq <= 0 {
+			return Property{}, fmt.Errorf("invalid parameter of %s", property.Name)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value strings.Builder
+		quoted := false
+		i := 0
+		for ; i < len(rest); i++ {
+			c := rest[i]
+			if c == '"' {
+				quoted = !quoted
+				continue
+			}
+			if !quoted && (c == ';' || c == ':') {
+				break
+			}
+			value.WriteByte(c)
+		}
+		if quoted {
+			return Property{}, fmt.Errorf("unterminated quote in parameter %s", name)
+		}
+		if property.Params == nil {
+			property.Params = map[string]string{}
+		}
+		property.Params[name] = value.String()
+		rest = rest[i:]
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return Property{}, fmt.Errorf("missing value of %s", property.Name)
+	}
+	property.Value = rest[1:]
+	return property, nil
+}
+
+// Get returns the first property of the component with the given name
+func (c Component) Get(name string) (Property, bool) {
+	for _, property := range c.Properties {
+		if property.Name == name {
+			return property, true
+		}
+	}
+	return Property{}, false
+}
+
+// Unescape reads a value of the TEXT type
+func Unescape(value string) string {
+	var text strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			text.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			text.WriteByte('\n')
+		default:
+			text.WriteByte(value[i])
+		}
+	}
+	return text.String()
+}
@@ -0,0 +1,112 @@
+// Package ical reads and writes the RFC 5545 iCalendar format: components of properties, with text escaping
+// and lines folded at 75 octets, and the date and time values of properties in their time zones.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLine is the length in octets a content line is folded at, without its line break
+const maxLine = 75
+
+// Property is a content line such as "DUE:20261102T090000Z". Value is written as is, so text values are
+// escaped with Text first.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Component is a calendar component such as VCALENDAR or VTODO, with its properties and nested components.
+// Line is the line of its BEGIN when it was decoded.
+type Component struct {
+	Name       string
+	Properties []Property
+	Components []Component
+	Line       int
+}
+
+// Add appends a property to the component
+func (c *Component) Add(name, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Value: value})
+}
+
+// AddParams appends a property with parameters to the component
+func (c *Component) AddParams(name string, params map[string]string, value string) {
+	c.Properties = append(c.Properties, Property{Name: name, Params: params, Value: value})
+}
+
+// Encode writes the component and everything nested in it, with CRLF line breaks
+func (c Component) Encode(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	c.encode(writer)
+	return writer.Flush()
+}
+
+func (c Component) encode(w *bufio.Writer) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, property := range c.Properties {
+		writeLine(w, property.line())
+	}
+	for _, component := range c.Components {
+		component.encode(w)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// line formats the property as one unfolded content line. Parameters are written in name order, and
+// quoted when their value holds a character that separates parameters.
+func (p Property) line() string {
+	var line strings.Builder
+	line.WriteString(p.Name)
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := p.Params[name]
+		if strings.ContainsAny(value, ":;,") {
+			value = `"` + value + `"`
+		}
+		line.WriteString(";" + name + "=" + value)
+	}
+	line.WriteString(":" + p.Value)
+	return line.String()
+}
+
+// writeLine writes a content line, folded into lines of at most 75 octets that continue with a space. A
+// line is never folded inside a UTF-8 sequence.
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLine
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The space that continues a line counts towards its length
+		limit = maxLine - 1
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// textEscaper escapes the characters a TEXT value cannot hold as they are. Carriage returns are dropped, as
+// line breaks are written as \n.
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// Text escapes a value of the TEXT type
+func Text(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// DateTime formats a DATE-TIME value in UTC, such as 20261102T090000Z
+func DateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
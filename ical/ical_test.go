@@ -0,0 +1,207 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	todo := Component{Name: "VTODO"}
+	todo.Add("UID", "task-1@todo-lists")
+	todo.Add("SUMMARY", Text("Write report; draft, v2\nthen review"))
+	todo.AddParams("RELATED-TO", map[string]string{"RELTYPE": "PARENT"}, "task-0@todo-lists")
+	todo.AddParams("X-NOTE", map[string]string{"X-B": "a:b", "X-A": "plain"}, "value")
+	calendar := Component{Name: "VCALENDAR", Components: []Component{todo}}
+	calendar.Add("VERSION", "2.0")
+
+	var out strings.Builder
+	require.NoError(t, calendar.Encode(&out))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"BEGIN:VTODO\r\n"+
+		"UID:task-1@todo-lists\r\n"+
+		`SUMMARY:Write report\; draft\, v2\nthen review`+"\r\n"+
+		"RELATED-TO;RELTYPE=PARENT:task-0@todo-lists\r\n"+
+		`X-NOTE;X-A=plain;X-B="a:b":value`+"\r\n"+
+		"END:VTODO\r\n"+
+		"END:VCALENDAR\r\n", out.String())
+}
+
+func TestFolding(t *testing.T) {
+	component := Component{Name: "VTODO"}
+	component.Add("SUMMARY", strings.Repeat("a", 200))
+	// A three octet character that would straddle the first fold
+	component.Add("DESCRIPTION", strings.Repeat("b", 61)+"€€€")
+
+	var out strings.Builder
+	require.NoError(t, component.Encode(&out))
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n")
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	assert.Equal(t, []string{
+		"BEGIN:VTODO",
+		"SUMMARY:" + strings.Repeat("a", 67),
+		" " + strings.Repeat("a", 74),
+		" " + strings.Repeat("a", 59),
+		"DESCRIPTION:" + strings.Repeat("b", 61),
+		" €€€",
+		"END:VTODO",
+	}, lines)
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, `C:\\temp\, notes\; more\nlines\n`, Text("C:\\temp, notes; more\r\nlines\n"))
+}
+
+func TestDateTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "20261102T090000Z", DateTime(time.Date(2026, 11, 2, 10, 0, 0, 0, berlin)))
+}
+
+func TestDecode(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"begin:vtodo\r\n" +
+		"UID:abc@example.com\r\n" +
+		"SUMMARY:Write a very long report that is folded onto the next line\r\n" +
+		"  of the file\n" +
+		`DESCRIPTION:One\, two\; three\nfour \\ five` + "\r\n" +
+		`DUE;TZID="America/New_York";VALUE=DATE-TIME:20261102T090000` + "\r\n" +
+		"X-EMPTY:\r\n" +
+		"END:VTODO\r\n" +
+		"END:VCALENDAR\r\n"
+
+	calendar, err := Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "VCALENDAR", calendar.Name)
+	require.Len(t, calendar.Components, 1)
+	todo := calendar.Components[0]
+	assert.Equal(t, "VTODO", todo.Name)
+	assert.Equal(t, 3, todo.Line)
+
+	summary, ok := todo.Get("SUMMARY")
+	assert.True(t, ok)
+	assert.Equal(t, "Write a very long report that is folded onto the next line of the file", summary.Value)
+	description, _ := todo.Get("DESCRIPTION")
+	assert.Equal(t, "One, two; three\nfour \\ five", Unescape(description.Value))
+	due, _ := todo.Get("DUE")
+	assert.Equal(t, Property{Name: "DUE", Params: map[string]string{"TZID": "America/New_York", "VALUE": "DATE-TIME"}, Value: "20261102T090000"}, due)
+	empty, ok := todo.Get("X-EMPTY")
+	assert.True(t, ok)
+	assert.Empty(t, empty.Value)
+	_, ok = todo.Get("DTSTART")
+	assert.False(t, ok)
+
+	// What is encoded decodes to the same properties
+	var out strings.Builder
+	require.NoError(t, todo.Encode(&out))
+	again, err := Decode(strings.NewReader(out.String()))
+	require.NoError(t, err)
+	assert.Equal(t, todo.Properties, again.Properties)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, data := range []string{
+		"",
+		"SUMMARY:outside\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VTODO\r\n",
+		"BEGIN:VCALENDAR\r\nno colon\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nDUE;TZID=\"Europe/Berlin:20261102T090000\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\nBEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n",
+	} {
+		_, err := Decode(strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalid, data)
+	}
+}
+
+// outlookCalendar defines a time zone by a Windows name, as Outlook does
+const outlookCalendar = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:W. Europe Standard Time\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:16011028T030000\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10\r\n" +
+	"TZOFFSETFROM:+0200\r\n" +
+	"TZOFFSETTO:+0100\r\n" +
+	"END:STANDARD\r\n" +
+	"BEGIN:DAYLIGHT\r\n" +
+	"DTSTART:16010325T020000\r\n" +
+	"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3\r\n" +
+	"TZOFFSETFROM:+0100\r\n" +
+	"TZOFFSETTO:+0200\r\n" +
+	"END:DAYLIGHT\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Custom\r\n" +
+	"X-LIC-LOCATION:Asia/Kolkata\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:Fixed\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19700101T000000\r\n" +
+	"TZOFFSETFROM:-0300\r\n" +
+	"TZOFFSETTO:-0330\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestZonesTime(t *testing.T) {
+	calendar, err := Decode(strings.NewReader(outlookCalendar))
+	require.NoError(t, err)
+	zones := NewZones(calendar)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		property Property
+		instant  time.Time
+		date     bool
+	}{
+		{Property{Value: "20261102T090000Z"}, time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC), false},
+		// Floating times and dates are read in the given location
+		{Property{Value: "20261102T090000"}, time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), false},
+		{Property{Value: "20261102"}, time.Date(2026, 11, 1, 15, 0, 0, 0, time.UTC), true},
+		{Property{Params: map[string]string{"VALUE": "DATE"}, Value: "20261102"}, time.Date(2026, 11, 1, 15, 0, 0, 0, time.UTC), true},
+		// IANA names, also at the end of a longer TZID
+		{Property{Params: map[string]string{"TZID": "America/New_York"}, Value: "20260701T090000"}, time.Date(2026, 7, 1, 13, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "/mozilla.org/20050126_1/Europe/Berlin"}, Value: "20261102T090000"}, time.Date(2026, 11, 2, 8, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "Custom"}, Value: "20261102T090000"}, time.Date(2026, 11, 2, 3, 30, 0, 0, time.UTC), false},
+		// A VTIMEZONE with daylight saving time, on both sides of its changes
+		{Property{Params: map[string]string{"TZID": "W. Europe Standard Time"}, Value: "20260215T090000"}, time.Date(2026, 2, 15, 8, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "W. Europe Standard Time"}, Value: "20260329T013000"}, time.Date(2026, 3, 29, 0, 30, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "W. Europe Standard Time"}, Value: "20260329T030000"}, time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "W. Europe Standard Time"}, Value: "20260701T090000"}, time.Date(2026, 7, 1, 7, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "W. Europe Standard Time"}, Value: "20261025T040000"}, time.Date(2026, 10, 25, 3, 0, 0, 0, time.UTC), false},
+		{Property{Params: map[string]string{"TZID": "Fixed"}, Value: "20261102T090000"}, time.Date(2026, 11, 2, 12, 30, 0, 0, time.UTC), false},
+	} {
+		instant, date, err := zones.Time(test.property, tokyo)
+		require.NoError(t, err, test.property)
+		assert.True(t, test.instant.Equal(instant), "%v: %v", test.property, instant.UTC())
+		assert.Equal(t, test.date, date, test.property)
+	}
+
+	for _, property := range []Property{
+		{Value: "2026-11-02"},
+		{Value: "20261102T9"},
+		{Value: "20261302T090000Z"},
+		{Params: map[string]string{"TZID": "Mars/Olympus_Mons"}, Value: "20261102T090000"},
+	} {
+		_, _, err := zones.Time(property, time.UTC)
+		assert.ErrorIs(t, err, ErrInvalid, property)
+	}
+}
+
+func TestNthWeekday(t *testing.T) {
+	assert.Equal(t, 29, nthWeekday(2026, time.March, time.Sunday, -1))
+	assert.Equal(t, 25, nthWeekday(2026, time.October, time.Sunday, -1))
+	assert.Equal(t, 8, nthWeekday(2026, time.March, time.Sunday, 2))
+	assert.Equal(t, 1, nthWeekday(2026, time.November, time.Sunday, 1))
+	assert.Equal(t, 24, nthWeekday(2026, time.February, time.Tuesday, -1))
+}
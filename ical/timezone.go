@@ -0,0 +1,254 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Zones resolves the TZID parameters of a calendar. An IANA name is used as is, also when it ends a longer
+// TZID such as "/mozilla.org/20050126_1/Europe/Berlin". Other names, such as the Windows names Outlook
+// writes, are evaluated from the VTIMEZONE definition of the calendar.
+type Zones struct {
+	definitions map[string]Component
+}
+
+// NewZones collects the VTIMEZONE definitions of a calendar
+func NewZones(calendar Component) Zones {
+	zones := Zones{definitions: map[string]Component{}}
+	for _, component := range calendar.Components {
+		if component.Name != "VTIMEZONE" {
+			continue
+		}
+		if tzid, ok := component.Get("TZID"); ok {
+			zones.definitions[tzid.Value] = component
+		}
+	}
+	return zones
+}
+
+// Time reads the DATE or DATE-TIME value of a property, reporting whether it is a date. A UTC time or a time
+// with a TZID is an instant; a floating time, which has neither, and a date are read in floating.
+func (z Zones) Time(property Property, floating *time.Location) (time.Time, bool, error) {
+	value := property.Value
+	if property.Params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, floating)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid date %q", ErrInvalid, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%w: invalid time %q", ErrInvalid, value)
+		}
+		return t, false, nil
+	}
+	wall, err := time.Parse("20060102T150405", value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: invalid time %q", ErrInvalid, value)
+	}
+	tzid, ok := property.Params["TZID"]
+	if !ok {
+		return inLocation(wall, floating), false, nil
+	}
+	if loc, ok := ianaLocation(tzid); ok {
+		return inLocation(wall, loc), false, nil
+	}
+	if definition, ok := z.definitions[tzid]; ok {
+		if location, ok := definition.Get("X-LIC-LOCATION"); ok {
+			if loc, ok := ianaLocation(location.Value); ok {
+				return inLocation(wall, loc), false, nil
+			}
+		}
+		if offset, ok := observedOffset(definition, wall); ok {
+			return inLocation(wall, time.FixedZone(tzid, offset)), false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%w: unknown time zone %q", ErrInvalid, tzid)
+}
+
+// inLocation reads the wall clock of t, which is in UTC, in loc
+func inLocation(wall time.Time, loc *time.Location) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, loc)
+}
+
+// ianaLocation loads a TZID as an IANA time zone, or the longest IANA name it ends with
+func ianaLocation(tzid string) (*time.Location, bool) {
+	name := strings.Trim(tzid, "/ ")
+	for name != "" {
+		if name != "Local" {
+			if loc, err := time.LoadLocation(name); err == nil {
+				return loc, true
+			}
+		}
+		_, rest, ok := strings.Cut(name, "/")
+		if !ok {
+			break
+		}
+		name = rest
+	}
+	return nil, false
+}
+
+// observedOffset returns the UTC offset a VTIMEZONE observes at a wall clock time, in seconds. It is the
+// offset after the latest onset of a STANDARD or DAYLIGHT observance before that time; onsets repeat by a
+// yearly RRULE with BYMONTH and BYDAY, as time zones define them. Before the first onset the offset it
+// changes from applies.
+func observedOffset(definition Component, wall time.Time) (int, bool) {
+	var latest, first time.Time
+	offset, ok := 0, false
+	for _, observance := range definition.Components {
+		if observance.Name != "STANDARD" && observance.Name != "DAYLIGHT" {
+			continue
+		}
+		start, from, to, valid := observanceFields(observance)
+		if !valid {
+			continue
+		}
+		if onset, found := latestOnset(observance, start, wall); found && (!ok || onset.After(latest)) {
+			latest, offset, ok = onset, to, true
+		}
+		if !ok && (first.IsZero() || start.Before(first)) {
+			first, offset = start, from
+		}
+	}
+	return offset, ok || !first.IsZero()
+}
+
+// observanceFields reads the onset and the offsets of an observance
+func observanceFields(observance Component) (time.Time, int, int, bool) {
+	dtstart, ok1 := observance.Get("DTSTART")
+	from, ok2 := observance.Get("TZOFFSETFROM")
+	to, ok3 := observance.Get("TZOFFSETTO")
+	if !ok1 || !ok2 || !ok3 {
+		return time.Time{}, 0, 0, false
+	}
+	start, err := time.Parse("20060102T150405", dtstart.Value)
+	fromOffset, err2 := parseOffset(from.Value)
+	toOffset, err3 := parseOffset(to.Value)
+	if err != nil || err2 != nil || err3 != nil {
+		return time.Time{}, 0, 0, false
+	}
+	return start, fromOffset, toOffset, true
+}
+
+// latestOnset returns the latest onset of an observance at or before a wall clock time
+func latestOnset(observance Component, start, wall time.Time) (time.Time, bool) {
+	if start.After(wall) {
+		return time.Time{}, false
+	}
+	property, ok := observance.Get("RRULE")
+	if !ok {
+		return start, true
+	}
+	month, weekday, ordinal, until, ok := yearlyRule(property.Value)
+	if !ok {
+		return start, true
+	}
+
+	for year := wall.Year(); year >= wall.Year()-1 && year >= start.Year(); year-- {
+		day := nthWeekday(year, month, weekday, ordinal)
+		onset := time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+		if onset.Before(start) || onset.After(wall) {
+			continue
+		}
+		if until != nil && onset.After(*until) {
+			// The rule ended, its last onset is still in effect
+			return latestUntil(month, weekday, ordinal, start, *until), true
+		}
+		return onset, true
+	}
+	return start, true
+}
+
+// latestUntil returns the last onset of a yearly rule that ended at until
+func latestUntil(month time.Month, weekday time.Weekday, ordinal int, start, until time.Time) time.Time {
+	for year := until.Year(); year >= start.Year(); year-- {
+		onset := time.Date(year, month, nthWeekday(year, month, weekday, ordinal), start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+		if !onset.After(until) && !onset.Before(start) {
+			return onset
+		}
+	}
+	return start
+}
+
+// yearlyRule reads a rule such as FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU, which time zones change offsets by
+func yearlyRule(rule string) (time.Month, time.Weekday, int, *time.Time, bool) {
+	var month time.Month
+	var weekday time.Weekday
+	var ordinal int
+	var until *time.Time
+	yearly, byDay := false, false
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		name, value, _ := strings.Cut(part, "=")
+		switch name {
+		case "FREQ":
+			yearly = value == "YEARLY"
+		case "BYMONTH":
+			m, err := strconv.Atoi(value)
+			if err != nil || m < 1 || m > 12 {
+				return 0, 0, 0, nil, false
+			}
+			month = time.Month(m)
+		case "BYDAY":
+			if len(value) < 3 {
+				return 0, 0, 0, nil, false
+			}
+			day, ok := weekdays[value[len(value)-2:]]
+			n, err := strconv.Atoi(value[:len(value)-2])
+			if !ok || err != nil || n == 0 || n < -5 || n > 5 {
+				return 0, 0, 0, nil, false
+			}
+			weekday, ordinal, byDay = day, n, true
+		case "UNTIL":
+			t, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				return 0, 0, 0, nil, false
+			}
+			until = &t
+		}
+	}
+	return month, weekday, ordinal, until, yearly && month != 0 && byDay
+}
+
+// weekdays maps the RFC 5545 day codes to weekdays
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// nthWeekday returns the day of the month of the nth such weekday, counting from the end when n is negative
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) int {
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return 1 + (int(weekday)-int(first.Weekday())+7)%7 + (n-1)*7
+	}
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	return last.Day() - (int(last.Weekday())-int(weekday)+7)%7 + (n+1)*7
+}
+
+// parseOffset reads a UTC offset such as +0100 or -053000 in seconds
+func parseOffset(value string) (int, error) {
+	if len(value) != 5 && len(value) != 7 || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	digits := value[1:]
+	hours, err1 := strconv.Atoi(digits[0:2])
+	minutes, err2 := strconv.Atoi(digits[2:4])
+	seconds, err3 := 0, error(nil)
+	if len(digits) == 6 {
+		seconds, err3 = strconv.Atoi(digits[4:6])
+	}
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid offset %q", value)
+	}
+	offset := hours*3600 + minutes*60 + seconds
+	if value[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"log"
+	"os"
 	"todo-lists/config"
 	"todo-lists/controllers"
+	"todo-lists/migrations"
 	"todo-lists/repositories"
 	"todo-lists/routing"
 	"todo-lists/services"
+
+	// Embed the time zone database, views and queries resolve dates in the caller's time zone
+	_ "time/tzdata"
 )
 
 func main() {
@@ -15,11 +20,51 @@ func main() {
 	log.Println("Initializig configuration")
 	db := config.ConnectDB()
 
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatal("Error loading migrations:", err)
+	}
+
+	// "todo-lists migrate up|down|status" manages the schema instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(migrator, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if config.AutoMigrate() {
+		if _, err := migrator.Up(); err != nil {
+			log.Fatal("Error migrating the database:", err)
+		}
+	}
+	if err := migrator.Check(); err != nil {
+		log.Fatal("Refusing to start: ", err)
+	}
+
 	// Initialize the repository, service, and controller
 	taskRepo := &repositories.TaskRepository{DB: db}
-	taskService := &services.TaskService{Repo: taskRepo}
+	listRepo := &repositories.ListRepository{DB: db}
+	labelRepo := &repositories.LabelRepository{DB: db}
+	viewRepo := &repositories.ViewRepository{DB: db}
+	idempotencyRepo := &repositories.IdempotencyRepository{DB: db}
+	taskService := &services.TaskService{Repo: taskRepo, Lists: listRepo, Labels: labelRepo}
+	listService := &services.ListService{Repo: listRepo}
+	labelService := &services.LabelService{Repo: labelRepo, Tasks: taskRepo}
+	viewService := &services.ViewService{Repo: viewRepo, Tasks: taskRepo}
+	idempotencyService := &services.IdempotencyService{Repo: idempotencyRepo, TTL: config.IdempotencyTTL(), Lease: config.IdempotencyLease()}
 	taskController := &controllers.TaskController{Service: taskService}
+	listController := &controllers.ListController{Service: listService}
+	labelController := &controllers.LabelController{Service: labelService}
+	viewController := &controllers.ViewController{Service: viewService}
+	idempotency := &controllers.IdempotencyMiddleware{Service: idempotencyService}
+
+	// Empty expired tasks from the trash in the background
+	stopPurge := services.StartTrashPurge(taskService, config.TrashRetention(), config.TrashPurgeInterval())
+	defer stopPurge()
+	stopIdempotencyPurge := services.StartIdempotencyPurge(idempotencyService, config.IdempotencyPurgeInterval())
+	defer stopIdempotencyPurge()
 
-	// Start the server with the task controller
-	routing.StartServer(taskController)
+	// Start the server with the task and list controllers
+	routing.StartServer(taskController, listController, labelController, viewController, idempotency)
 }
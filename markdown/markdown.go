@@ -0,0 +1,144 @@
+// Package markdown reads and writes GitHub-flavored Markdown task lists: items such as
+// "- [x] Write report (due 2026-11-02) <!-- task:12 -->" in sections under level two headings. The comment
+// carries the ID of the task an item was written for, so the item can be matched to it when it is read back.
+package markdown
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	itemPattern    = regexp.MustCompile(`^\s*(?:[-*+]|\d{1,9}[.)])\s+\[([ xX])\]\s*(.*)$`)
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	idPattern      = regexp.MustCompile(`\s*<!--\s*task:(\d{1,9})\s*-->\s*$`)
+	duePattern     = regexp.MustCompile(`\s*\(due ([^()]*)\)$`)
+)
+
+// Item is a task list item. Text is its text without the struck through markers, the due date and the task
+// comment, which are kept apart. Section is the title of the level two or lower heading it is under, empty
+// before the first one.
+type Item struct {
+	Line    int
+	Section string
+	Checked bool
+	Struck  bool
+	Text    string
+	Due     string
+	ID      uint
+}
+
+// Section is a heading with the items under it
+type Section struct {
+	Title string
+	Items []Item
+}
+
+// Parse reads the task list items of a document with the heading each is under. Other lines, and lines in
+// fenced code blocks, are skipped. Items may be nested and their lists may be ordered.
+func Parse(r io.Reader) ([]Item, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var items []Item
+	var section, fence string
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		trimmed := strings.TrimSpace(text)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if match := headingPattern.FindStringSubmatch(text); match != nil {
+			if len(match[1]) == 1 {
+				section = ""
+			} else {
+				section = match[2]
+			}
+			continue
+		}
+		if match := itemPattern.FindStringSubmatch(text); match != nil {
+			item := parseItem(match[2])
+			item.Line = line
+			item.Section = section
+			item.Checked = match[1] != " "
+			items = append(items, item)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// parseItem reads the text of an item from its end: the task comment, then the due date, then the struck
+// through markers
+func parseItem(text string) Item {
+	var item Item
+	if match := idPattern.FindStringSubmatchIndex(text); match != nil {
+		id, _ := strconv.ParseUint(text[match[2]:match[3]], 10, 32)
+		item.ID = uint(id)
+		text = text[:match[0]]
+	}
+	text = strings.TrimSpace(text)
+	if match := duePattern.FindStringSubmatchIndex(text); match != nil {
+		item.Due = strings.TrimSpace(text[match[2]:match[3]])
+		text = text[:match[0]]
+	}
+	if len(text) > 4 && strings.HasPrefix(text, "~~") && strings.HasSuffix(text, "~~") {
+		item.Struck = true
+		text = text[2 : len(text)-2]
+	}
+	item.Text = strings.TrimSpace(text)
+	return item
+}
+
+// Write writes a document with a level one title and a level two heading for each section, with its items
+// as a task list. The Line and Section of the items are not written.
+func Write(w io.Writer, title string, sections []Section) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "# %s\n", title)
+	for _, section := range sections {
+		fmt.Fprintf(writer, "\n## %s\n\n", section.Title)
+		for _, item := range section.Items {
+			writer.WriteString(item.String() + "\n")
+		}
+	}
+	return writer.Flush()
+}
+
+// String formats the item as a line of a task list
+func (i Item) String() string {
+	var line strings.Builder
+	if i.Checked {
+		line.WriteString("- [x] ")
+	} else {
+		line.WriteString("- [ ] ")
+	}
+	if i.Struck {
+		line.WriteString("~~" + i.Text + "~~")
+	} else {
+		line.WriteString(i.Text)
+	}
+	if i.Due != "" {
+		line.WriteString(" (due " + i.Due + ")")
+	}
+	if i.ID != 0 {
+		fmt.Fprintf(&line, " <!-- task:%d -->", i.ID)
+	}
+	return line.String()
+}
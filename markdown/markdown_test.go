@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	document := "# Backend\n" +
+		"\n" +
+		"Some notes about the list.\n" +
+		"- [ ] Write report (due 2026-11-02) <!-- task:12 -->\n" +
+		"## work ##\n" +
+		"- [x] ~~Old idea~~ <!-- task:13 -->\n" +
+		"  * [X] Review (due 2026-11-03 17:00)\n" +
+		"1. [ ] Ship\n" +
+		"- [] Not an item\n" +
+		"- plain list item\n" +
+		"```\n" +
+		"- [ ] Inside a code block\n" +
+		"```\n" +
+		"### Later\n" +
+		"- [ ] Plan (due soon) the launch <!--task:7-->\n"
+
+	items, err := Parse(strings.NewReader(document))
+	require.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Line: 4, Text: "Write report", Due: "2026-11-02", ID: 12},
+		{Line: 6, Section: "work", Checked: true, Struck: true, Text: "Old idea", ID: 13},
+		{Line: 7, Section: "work", Checked: true, Text: "Review", Due: "2026-11-03 17:00"},
+		{Line: 8, Section: "work", Text: "Ship"},
+		{Line: 15, Section: "Later", Text: "Plan (due soon) the launch", ID: 7},
+	}, items)
+}
+
+func TestWrite(t *testing.T) {
+	var out strings.Builder
+	require.NoError(t, Write(&out, "Backend", []Section{
+		{Title: "work", Items: []Item{
+			{Text: "Write report", Due: "2026-11-02", ID: 12},
+			{Checked: true, Struck: true, Text: "Old idea", ID: 13},
+		}},
+		{Title: "Untagged", Items: []Item{{Checked: true, Text: "Review"}}},
+	}))
+	document := "# Backend\n" +
+		"\n" +
+		"## work\n" +
+		"\n" +
+		"- [ ] Write report (due 2026-11-02) <!-- task:12 -->\n" +
+		"- [x] ~~Old idea~~ <!-- task:13 -->\n" +
+		"\n" +
+		"## Untagged\n" +
+		"\n" +
+		"- [x] Review\n"
+	assert.Equal(t, document, out.String())
+
+	// What is written reads back as the same items
+	items, err := Parse(strings.NewReader(document))
+	require.NoError(t, err)
+	assert.Equal(t, []Item{
+		{Line: 5, Section: "work", Text: "Write report", Due: "2026-11-02", ID: 12},
+		{Line: 6, Section: "work", Checked: true, Struck: true, Text: "Old idea", ID: 13},
+		{Line: 10, Section: "Untagged", Checked: true, Text: "Review"},
+	}, items)
+}
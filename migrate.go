@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"todo-lists/migrations"
+)
+
+// runMigrate runs the migrate subcommand: "up" applies pending migrations, "down" reverts the latest one and
+// "status" lists which migrations are applied
+func runMigrate(migrator *migrations.Migrator, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: todo-lists migrate up|down|status")
+	}
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %d migrations\n", count)
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %04d_%s\n", migration.Version, migration.Name)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+	return nil
+}
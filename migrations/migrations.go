@@ -0,0 +1,264 @@
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// scripts holds the up and down SQL scripts of every dialect, named <version>_<name>.<up|down>.sql
+//
+//go:embed sql
+var scripts embed.FS
+
+var (
+	ErrSchemaBehind  = errors.New("database schema is behind the code, run \"migrate up\"")
+	ErrNothingToUndo = errors.New("no migration has been applied")
+	ErrUnversioned   = errors.New("database has tables created before versioned migrations, migrate a new database and copy the data over")
+)
+
+// unversionedTables are the tables a database created before versioned migrations has
+var unversionedTables = []string{"lists", "tasks"}
+
+// Migration is one versioned step of the schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied to the database
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// schemaMigration is a row of the schema_migrations table, one per applied migration
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies the migrations of the database dialect in version order
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration
+}
+
+// New returns a migrator with the migrations for the dialect of db
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: migrations}, nil
+}
+
+// Load reads the migrations of a dialect sorted by version. Every version needs both an up and a down script.
+func Load(dialect string) ([]Migration, error) {
+	dir := path.Join("sql", dialect)
+	entries, err := fs.ReadDir(scripts, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for dialect %q", dialect)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		script, err := fs.ReadFile(scripts, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseFileName splits a script name such as 0002_create_tasks.up.sql into its parts
+func parseFileName(file string) (version int, name, direction string, err error) {
+	base, ok := strings.CutSuffix(file, ".sql")
+	if ok {
+		base, direction, ok = cutLast(base, ".")
+	}
+	if ok && (direction == "up" || direction == "down") {
+		var number string
+		number, name, ok = strings.Cut(base, "_")
+		if ok {
+			version, err = strconv.Atoi(number)
+			if err == nil && version > 0 && name != "" {
+				return version, name, direction, nil
+			}
+		}
+	}
+	return 0, "", "", fmt.Errorf("invalid migration file name %q", file)
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// Up applies every pending migration and returns how many were applied. It refuses with ErrUnversioned to
+// migrate a database whose tables were created without migrations, whose schema no migration can tell.
+func (m *Migrator) Up() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) == 0 {
+		for _, table := range unversionedTables {
+			if m.DB.Migrator().HasTable(table) {
+				return 0, ErrUnversioned
+			}
+		}
+	}
+
+	count := 0
+	for _, migration := range m.Migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.DB.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Up); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now().UTC()}).Error
+		})
+		if err != nil {
+			return count, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// Down reverts the most recently applied migration and returns it
+func (m *Migrator) Down() (Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return Migration{}, err
+	}
+
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		migration := m.Migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := m.DB.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return Migration{}, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return migration, nil
+	}
+	return Migration{}, ErrNothingToUndo
+}
+
+// Status lists every known migration in version order with the time it was applied, if it was
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check returns ErrSchemaBehind when a migration of the code has not been applied to the database yet
+func (m *Migrator) Check() error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			return ErrSchemaBehind
+		}
+	}
+	return nil
+}
+
+// applied returns the rows of schema_migrations by version, creating the table on first use
+func (m *Migrator) applied() (map[int]schemaMigration, error) {
+	if err := m.DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := m.DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// execScript runs the statements of a script one by one, since not every driver accepts several per call
+func execScript(tx *gorm.DB, script string) error {
+	for _, statement := range strings.Split(script, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
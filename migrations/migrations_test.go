@@ -0,0 +1,162 @@
+package migrations
+
+import (
+	"testing"
+	"todo-lists/models"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func setupSQLite(t *testing.T) *Migrator {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	migrator, err := New(db)
+	assert.NoError(t, err)
+	return migrator
+}
+
+func TestLoad(t *testing.T) {
+	var versions [][]int
+	for _, dialect := range []string{"mysql", "sqlite", "postgres"} {
+		migrations, err := Load(dialect)
+		assert.NoError(t, err)
+
+		var dialectVersions []int
+		for _, migration := range migrations {
+			assert.NotEmpty(t, migration.Up)
+			assert.NotEmpty(t, migration.Down)
+			dialectVersions = append(dialectVersions, migration.Version)
+		}
+		versions = append(versions, dialectVersions)
+	}
+
+	// Every dialect has the same migration history
+	assert.Equal(t, versions[0], versions[1])
+	assert.Equal(t, versions[0], versions[2])
+
+	_, err := Load("oracle")
+	assert.Error(t, err)
+}
+
+func TestParseFileName(t *testing.T) {
+	version, name, direction, err := parseFileName("0002_create_tasks.up.sql")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "create_tasks", name)
+	assert.Equal(t, "up", direction)
+
+	for _, file := range []string{"create_tasks.up.sql", "0002_create_tasks.sql", "0002_create_tasks.sideways.sql", "0000_init.up.sql", "0003_.down.sql"} {
+		_, _, _, err := parseFileName(file)
+		assert.Error(t, err, file)
+	}
+}
+
+func TestUpDownStatus(t *testing.T) {
+	migrator := setupSQLite(t)
+	total := len(migrator.Migrations)
+
+	// A fresh database is behind the code
+	assert.Equal(t, ErrSchemaBehind, migrator.Check())
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	assert.Len(t, statuses, total)
+	assert.Nil(t, statuses[0].AppliedAt)
+
+	count, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, total, count)
+	assert.NoError(t, migrator.Check())
+
+	// Applying again is a no-op
+	count, err = migrator.Up()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// Down reverts the latest migration only
+	reverted, err := migrator.Down()
+	assert.NoError(t, err)
+	assert.Equal(t, migrator.Migrations[total-1].Version, reverted.Version)
+	assert.Equal(t, ErrSchemaBehind, migrator.Check())
+	statuses, err = migrator.Status()
+	assert.NoError(t, err)
+	assert.NotNil(t, statuses[total-2].AppliedAt)
+	assert.Nil(t, statuses[total-1].AppliedAt)
+
+	// Reverting everything drops the tables again
+	for i := 1; i < total; i++ {
+		_, err = migrator.Down()
+		assert.NoError(t, err)
+	}
+	_, err = migrator.Down()
+	assert.Equal(t, ErrNothingToUndo, err)
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.Task{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.List{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.TaskDependency{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.Label{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.TaskLabel{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.View{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.IdempotencyKey{}))
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.ListFeed{}))
+}
+
+func TestSchemaMatchesModels(t *testing.T) {
+	migrator := setupSQLite(t)
+	_, err := migrator.Up()
+	assert.NoError(t, err)
+
+	// Every column the models read and write exists after migrating
+	for _, model := range []interface{}{&models.Task{}, &models.List{}, &models.TaskDependency{}, &models.Label{}, &models.TaskLabel{}, &models.View{}, &models.IdempotencyKey{}, &models.ListFeed{}} {
+		parsed, err := schema.Parse(model, &migrator.DB.Statement.Settings, migrator.DB.NamingStrategy)
+		assert.NoError(t, err)
+		for _, field := range parsed.Fields {
+			if field.DBName != "" {
+				assert.True(t, migrator.DB.Migrator().HasColumn(model, field.DBName), "%s.%s", parsed.Table, field.DBName)
+			}
+		}
+	}
+}
+
+func TestBackfillTaskUID(t *testing.T) {
+	migrator := setupSQLite(t)
+	all := migrator.Migrations
+	migrator.Migrations = all[:13]
+	_, err := migrator.Up()
+	assert.NoError(t, err)
+	assert.NoError(t, migrator.DB.Exec("INSERT INTO tasks (name, priority, uid) VALUES ('Write report', 'high', ''), ('Review', 'less', ''), ('Ship', 'medium', 'ship@example.com')").Error)
+
+	// Tasks without a UID get a random one, the others keep theirs
+	migrator.Migrations = all
+	_, err = migrator.Up()
+	assert.NoError(t, err)
+	var uids []string
+	assert.NoError(t, migrator.DB.Raw("SELECT uid FROM tasks ORDER BY id").Scan(&uids).Error)
+	if assert.Len(t, uids, 3) {
+		assert.Regexp(t, `^[0-9a-f]{32}@todo-lists$`, uids[0])
+		assert.Regexp(t, `^[0-9a-f]{32}@todo-lists$`, uids[1])
+		assert.NotEqual(t, uids[0], uids[1])
+		assert.Equal(t, "ship@example.com", uids[2])
+	}
+}
+
+func TestUpRefusesUnversionedTables(t *testing.T) {
+	migrator := setupSQLite(t)
+	assert.NoError(t, migrator.DB.Exec("CREATE TABLE tasks (id integer PRIMARY KEY AUTOINCREMENT, name text NOT NULL, tag text NOT NULL)").Error)
+
+	// A tasks table no migration created is left alone instead of being recorded as migrated
+	count, err := migrator.Up()
+	assert.Equal(t, ErrUnversioned, err)
+	assert.Zero(t, count)
+	statuses, err := migrator.Status()
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt, status.Name)
+	}
+	assert.False(t, migrator.DB.Migrator().HasTable(&models.List{}))
+}
@@ -0,0 +1,5 @@
+CREATE TABLE lists (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    name longtext NOT NULL,
+    description longtext
+);
@@ -0,0 +1,16 @@
+CREATE TABLE tasks (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    list_id bigint unsigned,
+    name longtext NOT NULL,
+    deadline datetime(3) NOT NULL,
+    tag varchar(16) NOT NULL,
+    status varchar(16) NOT NULL DEFAULT 'todo',
+    completed_at datetime(3) NULL,
+    version bigint unsigned NOT NULL DEFAULT 1,
+    deleted_at datetime(3) NULL,
+    INDEX idx_tasks_list_id (list_id),
+    INDEX idx_tasks_status (status),
+    INDEX idx_tasks_deleted_at (deleted_at),
+    CONSTRAINT chk_tasks_tag CHECK (tag IN ('less', 'medium', 'high')),
+    CONSTRAINT chk_tasks_status CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled'))
+);
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN recurrence varchar(255) NOT NULL DEFAULT '';
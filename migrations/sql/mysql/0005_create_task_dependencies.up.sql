@@ -0,0 +1,6 @@
+CREATE TABLE task_dependencies (
+    task_id bigint unsigned NOT NULL,
+    blocker_id bigint unsigned NOT NULL,
+    PRIMARY KEY (task_id, blocker_id),
+    INDEX idx_task_dependencies_blocker_id (blocker_id)
+);
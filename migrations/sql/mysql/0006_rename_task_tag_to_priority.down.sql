@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP CHECK chk_tasks_priority;
+ALTER TABLE tasks ALTER COLUMN priority DROP DEFAULT;
+ALTER TABLE tasks RENAME COLUMN priority TO tag;
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_tag CHECK (tag IN ('less', 'medium', 'high'));
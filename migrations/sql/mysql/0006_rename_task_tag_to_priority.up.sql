@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP CHECK chk_tasks_tag;
+ALTER TABLE tasks RENAME COLUMN tag TO priority;
+ALTER TABLE tasks ALTER COLUMN priority SET DEFAULT 'medium';
+ALTER TABLE tasks ADD CONSTRAINT chk_tasks_priority CHECK (priority IN ('less', 'medium', 'high'));
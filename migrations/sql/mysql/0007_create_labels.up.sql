@@ -0,0 +1,12 @@
+CREATE TABLE labels (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    name varchar(64) NOT NULL,
+    color varchar(7) NOT NULL DEFAULT '',
+    CONSTRAINT uni_labels_name UNIQUE (name)
+);
+CREATE TABLE task_labels (
+    task_id bigint unsigned NOT NULL,
+    label_id bigint unsigned NOT NULL,
+    PRIMARY KEY (task_id, label_id),
+    INDEX idx_task_labels_label_id (label_id)
+);
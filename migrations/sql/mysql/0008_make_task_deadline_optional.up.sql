@@ -0,0 +1 @@
+ALTER TABLE tasks MODIFY deadline datetime(3) NULL;
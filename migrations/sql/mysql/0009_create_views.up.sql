@@ -0,0 +1,6 @@
+CREATE TABLE views (
+    id bigint unsigned AUTO_INCREMENT PRIMARY KEY,
+    name varchar(64) NOT NULL,
+    query varchar(2000) NOT NULL,
+    CONSTRAINT uni_views_name UNIQUE (name)
+);
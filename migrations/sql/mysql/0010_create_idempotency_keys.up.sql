@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(255) PRIMARY KEY,
+    request_hash varchar(64) NOT NULL,
+    status bigint NOT NULL DEFAULT 0,
+    headers longtext NOT NULL,
+    body longblob,
+    expires_at datetime(3) NOT NULL,
+    INDEX idx_idempotency_keys_expires_at (expires_at)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN created_at datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3);
+ALTER TABLE tasks ADD COLUMN updated_at datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3);
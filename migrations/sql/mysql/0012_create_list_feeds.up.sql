@@ -0,0 +1,5 @@
+CREATE TABLE list_feeds (
+    list_id bigint unsigned PRIMARY KEY,
+    token_hash varchar(64) NOT NULL,
+    CONSTRAINT uni_list_feeds_token_hash UNIQUE (token_hash)
+);
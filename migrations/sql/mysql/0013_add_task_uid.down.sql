@@ -0,0 +1,2 @@
+DROP INDEX idx_tasks_uid ON tasks;
+ALTER TABLE tasks DROP COLUMN uid;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN uid varchar(255) NOT NULL DEFAULT '';
+CREATE INDEX idx_tasks_uid ON tasks (uid);
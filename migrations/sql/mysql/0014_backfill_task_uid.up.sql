@@ -0,0 +1,2 @@
+-- Tasks get a random calendar UID when they are created, and existing tasks without one get one here
+UPDATE tasks SET uid = CONCAT(LOWER(HEX(RANDOM_BYTES(16))), '@todo-lists') WHERE uid = '';
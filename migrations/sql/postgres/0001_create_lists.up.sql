@@ -0,0 +1,5 @@
+CREATE TABLE lists (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    description text
+);
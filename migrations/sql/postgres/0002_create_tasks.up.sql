@@ -0,0 +1,15 @@
+CREATE TABLE tasks (
+    id bigserial PRIMARY KEY,
+    list_id bigint,
+    name text NOT NULL,
+    deadline timestamptz NOT NULL,
+    tag varchar(16) NOT NULL CONSTRAINT chk_tasks_tag CHECK (tag IN ('less', 'medium', 'high')),
+    status varchar(16) NOT NULL DEFAULT 'todo' CONSTRAINT chk_tasks_status CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled')),
+    completed_at timestamptz,
+    version bigint NOT NULL DEFAULT 1,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_tasks_list_id ON tasks (list_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks (status);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_name_fts ON tasks USING GIN (to_tsvector('english', name));
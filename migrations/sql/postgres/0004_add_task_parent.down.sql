@@ -0,0 +1,2 @@
+DROP INDEX idx_tasks_parent_id;
+ALTER TABLE tasks DROP COLUMN parent_id;
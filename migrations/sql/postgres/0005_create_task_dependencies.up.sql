@@ -0,0 +1,6 @@
+CREATE TABLE task_dependencies (
+    task_id bigint NOT NULL,
+    blocker_id bigint NOT NULL,
+    PRIMARY KEY (task_id, blocker_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies (blocker_id);
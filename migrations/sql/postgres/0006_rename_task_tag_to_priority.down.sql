@@ -0,0 +1,3 @@
+ALTER TABLE tasks ALTER COLUMN priority DROP DEFAULT;
+ALTER TABLE tasks RENAME CONSTRAINT chk_tasks_priority TO chk_tasks_tag;
+ALTER TABLE tasks RENAME COLUMN priority TO tag;
@@ -0,0 +1,3 @@
+ALTER TABLE tasks RENAME COLUMN tag TO priority;
+ALTER TABLE tasks RENAME CONSTRAINT chk_tasks_tag TO chk_tasks_priority;
+ALTER TABLE tasks ALTER COLUMN priority SET DEFAULT 'medium';
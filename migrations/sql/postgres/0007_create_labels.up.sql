@@ -0,0 +1,11 @@
+CREATE TABLE labels (
+    id bigserial PRIMARY KEY,
+    name varchar(64) NOT NULL CONSTRAINT uni_labels_name UNIQUE,
+    color varchar(7) NOT NULL DEFAULT ''
+);
+CREATE TABLE task_labels (
+    task_id bigint NOT NULL,
+    label_id bigint NOT NULL,
+    PRIMARY KEY (task_id, label_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels (label_id);
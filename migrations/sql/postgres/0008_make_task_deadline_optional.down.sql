@@ -0,0 +1,2 @@
+-- Fails while a task has no deadline, give those tasks one before reverting
+ALTER TABLE tasks ALTER COLUMN deadline SET NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE tasks ALTER COLUMN deadline DROP NOT NULL;
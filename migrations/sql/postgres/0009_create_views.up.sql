@@ -0,0 +1,5 @@
+CREATE TABLE views (
+    id bigserial PRIMARY KEY,
+    name varchar(64) NOT NULL CONSTRAINT uni_views_name UNIQUE,
+    query varchar(2000) NOT NULL
+);
@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key varchar(255) PRIMARY KEY,
+    request_hash varchar(64) NOT NULL,
+    status bigint NOT NULL DEFAULT 0,
+    headers text NOT NULL,
+    body bytea,
+    expires_at timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
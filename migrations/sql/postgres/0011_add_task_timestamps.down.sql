@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN updated_at;
+ALTER TABLE tasks DROP COLUMN created_at;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,2 @@
+-- The UIDs stay: calendars that imported the tasks know them by these UIDs now
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Tasks get a random calendar UID when they are created, and existing tasks without one get one here
+UPDATE tasks SET uid = md5(random()::text || clock_timestamp()::text || id::text) || '@todo-lists' WHERE uid = '';
@@ -0,0 +1,5 @@
+CREATE TABLE lists (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    description text
+);
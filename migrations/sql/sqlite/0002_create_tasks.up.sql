@@ -0,0 +1,14 @@
+CREATE TABLE tasks (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    list_id integer,
+    name text NOT NULL,
+    deadline datetime NOT NULL,
+    tag text NOT NULL CONSTRAINT chk_tasks_tag CHECK (tag IN ('less', 'medium', 'high')),
+    status text NOT NULL DEFAULT 'todo' CONSTRAINT chk_tasks_status CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled')),
+    completed_at datetime,
+    version integer NOT NULL DEFAULT 1,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_tasks_list_id ON tasks (list_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks (status);
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks (deleted_at);
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN recurrence text NOT NULL DEFAULT '';
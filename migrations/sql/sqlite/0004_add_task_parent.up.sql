@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN parent_id integer NOT NULL DEFAULT 0;
+CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
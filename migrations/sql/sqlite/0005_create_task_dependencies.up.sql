@@ -0,0 +1,6 @@
+CREATE TABLE task_dependencies (
+    task_id integer NOT NULL,
+    blocker_id integer NOT NULL,
+    PRIMARY KEY (task_id, blocker_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies (blocker_id);
@@ -0,0 +1 @@
+ALTER TABLE tasks RENAME COLUMN priority TO tag;
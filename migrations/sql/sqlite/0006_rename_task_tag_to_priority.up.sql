@@ -0,0 +1,2 @@
+-- SQLite keeps the check constraint, which follows the column, but cannot add a column default
+ALTER TABLE tasks RENAME COLUMN tag TO priority;
@@ -0,0 +1,11 @@
+CREATE TABLE labels (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL CONSTRAINT uni_labels_name UNIQUE,
+    color text NOT NULL DEFAULT ''
+);
+CREATE TABLE task_labels (
+    task_id integer NOT NULL,
+    label_id integer NOT NULL,
+    PRIMARY KEY (task_id, label_id)
+);
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels (label_id);
@@ -0,0 +1,25 @@
+-- SQLite cannot change a column constraint, so the table is rebuilt with the same columns and indexes
+CREATE TABLE tasks_rebuilt (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    list_id integer,
+    name text NOT NULL,
+    deadline datetime,
+    priority text NOT NULL CONSTRAINT chk_tasks_priority CHECK (priority IN ('less', 'medium', 'high')),
+    status text NOT NULL DEFAULT 'todo' CONSTRAINT chk_tasks_status CHECK (status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled')),
+    completed_at datetime,
+    version integer NOT NULL DEFAULT 1,
+    deleted_at datetime,
+    recurrence text NOT NULL DEFAULT '',
+    parent_id integer NOT NULL DEFAULT 0
+);
+INSERT INTO tasks_rebuilt (id, list_id, name, deadline, priority, status, completed_at, version, deleted_at, recurrence, parent_id)
+    SELECT id, list_id, name, deadline, priority, status, completed_at, version, deleted_at, recurrence, parent_id FROM tasks;
+-- Keep the ID sequence, so IDs of purged tasks are not handed out again
+DELETE FROM sqlite_sequence WHERE name = 'tasks_rebuilt';
+INSERT INTO sqlite_sequence (name, seq) SELECT 'tasks_rebuilt', seq FROM sqlite_sequence WHERE name = 'tasks';
+DROP TABLE tasks;
+ALTER TABLE tasks_rebuilt RENAME TO tasks;
+CREATE INDEX idx_tasks_list_id ON tasks (list_id);
+CREATE INDEX idx_tasks_status ON tasks (status);
+CREATE INDEX idx_tasks_deleted_at ON tasks (deleted_at);
+CREATE INDEX idx_tasks_parent_id ON tasks (parent_id);
@@ -0,0 +1,5 @@
+CREATE TABLE views (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL CONSTRAINT uni_views_name UNIQUE,
+    query text NOT NULL
+);
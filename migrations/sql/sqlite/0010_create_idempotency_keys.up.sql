@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+    idempotency_key text PRIMARY KEY,
+    request_hash text NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    headers text NOT NULL,
+    body blob,
+    expires_at datetime NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,4 @@
+-- SQLite only accepts a constant default when adding a column, so existing tasks are stamped afterwards
+ALTER TABLE tasks ADD COLUMN created_at datetime NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE tasks ADD COLUMN updated_at datetime NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE tasks SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
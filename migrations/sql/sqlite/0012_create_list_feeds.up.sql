@@ -0,0 +1,4 @@
+CREATE TABLE list_feeds (
+    list_id integer PRIMARY KEY,
+    token_hash text NOT NULL CONSTRAINT uni_list_feeds_token_hash UNIQUE
+);
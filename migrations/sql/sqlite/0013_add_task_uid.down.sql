@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_uid;
+ALTER TABLE tasks DROP COLUMN uid;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks ADD COLUMN uid text NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_tasks_uid ON tasks (uid);
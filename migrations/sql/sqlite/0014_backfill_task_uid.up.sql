@@ -0,0 +1,2 @@
+-- Tasks get a random calendar UID when they are created, and existing tasks without one get one here
+UPDATE tasks SET uid = lower(hex(randomblob(16))) || '@todo-lists' WHERE uid = '';
@@ -1,7 +1,7 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: todo-lists/controllers (interfaces: IController)
 
-// Package controller is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
@@ -34,6 +34,78 @@ func (m *MockIController) EXPECT() *MockIControllerMockRecorder {
 	return m.recorder
 }
 
+// AddBlocker mocks base method.
+func (m *MockIController) AddBlocker(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddBlocker", arg0)
+}
+
+// AddBlocker indicates an expected call of AddBlocker.
+func (mr *MockIControllerMockRecorder) AddBlocker(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlocker", reflect.TypeOf((*MockIController)(nil).AddBlocker), arg0)
+}
+
+// BlockTask mocks base method.
+func (m *MockIController) BlockTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BlockTask", arg0)
+}
+
+// BlockTask indicates an expected call of BlockTask.
+func (mr *MockIControllerMockRecorder) BlockTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockTask", reflect.TypeOf((*MockIController)(nil).BlockTask), arg0)
+}
+
+// BulkTasks mocks base method.
+func (m *MockIController) BulkTasks(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "BulkTasks", arg0)
+}
+
+// BulkTasks indicates an expected call of BulkTasks.
+func (mr *MockIControllerMockRecorder) BulkTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkTasks", reflect.TypeOf((*MockIController)(nil).BulkTasks), arg0)
+}
+
+// CancelTask mocks base method.
+func (m *MockIController) CancelTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CancelTask", arg0)
+}
+
+// CancelTask indicates an expected call of CancelTask.
+func (mr *MockIControllerMockRecorder) CancelTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTask", reflect.TypeOf((*MockIController)(nil).CancelTask), arg0)
+}
+
+// CompleteTask mocks base method.
+func (m *MockIController) CompleteTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CompleteTask", arg0)
+}
+
+// CompleteTask indicates an expected call of CompleteTask.
+func (mr *MockIControllerMockRecorder) CompleteTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTask", reflect.TypeOf((*MockIController)(nil).CompleteTask), arg0)
+}
+
+// CreateListTask mocks base method.
+func (m *MockIController) CreateListTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateListTask", arg0)
+}
+
+// CreateListTask indicates an expected call of CreateListTask.
+func (mr *MockIControllerMockRecorder) CreateListTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListTask", reflect.TypeOf((*MockIController)(nil).CreateListTask), arg0)
+}
+
 // CreateTask mocks base method.
 func (m *MockIController) CreateTask(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -58,6 +130,66 @@ func (mr *MockIControllerMockRecorder) DeleteTask(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockIController)(nil).DeleteTask), arg0)
 }
 
+// ExportListTasksMarkdown mocks base method.
+func (m *MockIController) ExportListTasksMarkdown(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExportListTasksMarkdown", arg0)
+}
+
+// ExportListTasksMarkdown indicates an expected call of ExportListTasksMarkdown.
+func (mr *MockIControllerMockRecorder) ExportListTasksMarkdown(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportListTasksMarkdown", reflect.TypeOf((*MockIController)(nil).ExportListTasksMarkdown), arg0)
+}
+
+// ExportTasksCSV mocks base method.
+func (m *MockIController) ExportTasksCSV(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExportTasksCSV", arg0)
+}
+
+// ExportTasksCSV indicates an expected call of ExportTasksCSV.
+func (mr *MockIControllerMockRecorder) ExportTasksCSV(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasksCSV", reflect.TypeOf((*MockIController)(nil).ExportTasksCSV), arg0)
+}
+
+// ExportTasksICal mocks base method.
+func (m *MockIController) ExportTasksICal(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExportTasksICal", arg0)
+}
+
+// ExportTasksICal indicates an expected call of ExportTasksICal.
+func (mr *MockIControllerMockRecorder) ExportTasksICal(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasksICal", reflect.TypeOf((*MockIController)(nil).ExportTasksICal), arg0)
+}
+
+// ExportTasksTodoTxt mocks base method.
+func (m *MockIController) ExportTasksTodoTxt(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ExportTasksTodoTxt", arg0)
+}
+
+// ExportTasksTodoTxt indicates an expected call of ExportTasksTodoTxt.
+func (mr *MockIControllerMockRecorder) ExportTasksTodoTxt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasksTodoTxt", reflect.TypeOf((*MockIController)(nil).ExportTasksTodoTxt), arg0)
+}
+
+// FilterListTasksByDeadline mocks base method.
+func (m *MockIController) FilterListTasksByDeadline(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FilterListTasksByDeadline", arg0)
+}
+
+// FilterListTasksByDeadline indicates an expected call of FilterListTasksByDeadline.
+func (mr *MockIControllerMockRecorder) FilterListTasksByDeadline(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterListTasksByDeadline", reflect.TypeOf((*MockIController)(nil).FilterListTasksByDeadline), arg0)
+}
+
 // FilterTasksByDeadline mocks base method.
 func (m *MockIController) FilterTasksByDeadline(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -70,6 +202,78 @@ func (mr *MockIControllerMockRecorder) FilterTasksByDeadline(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterTasksByDeadline", reflect.TypeOf((*MockIController)(nil).FilterTasksByDeadline), arg0)
 }
 
+// GetBlockers mocks base method.
+func (m *MockIController) GetBlockers(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetBlockers", arg0)
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockIControllerMockRecorder) GetBlockers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockIController)(nil).GetBlockers), arg0)
+}
+
+// GetFeed mocks base method.
+func (m *MockIController) GetFeed(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetFeed", arg0)
+}
+
+// GetFeed indicates an expected call of GetFeed.
+func (mr *MockIControllerMockRecorder) GetFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeed", reflect.TypeOf((*MockIController)(nil).GetFeed), arg0)
+}
+
+// GetListTasks mocks base method.
+func (m *MockIController) GetListTasks(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetListTasks", arg0)
+}
+
+// GetListTasks indicates an expected call of GetListTasks.
+func (mr *MockIControllerMockRecorder) GetListTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTasks", reflect.TypeOf((*MockIController)(nil).GetListTasks), arg0)
+}
+
+// GetListTasksByTag mocks base method.
+func (m *MockIController) GetListTasksByTag(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetListTasksByTag", arg0)
+}
+
+// GetListTasksByTag indicates an expected call of GetListTasksByTag.
+func (mr *MockIControllerMockRecorder) GetListTasksByTag(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTasksByTag", reflect.TypeOf((*MockIController)(nil).GetListTasksByTag), arg0)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockIController) GetOccurrences(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetOccurrences", arg0)
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockIControllerMockRecorder) GetOccurrences(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockIController)(nil).GetOccurrences), arg0)
+}
+
+// GetPlan mocks base method.
+func (m *MockIController) GetPlan(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetPlan", arg0)
+}
+
+// GetPlan indicates an expected call of GetPlan.
+func (mr *MockIControllerMockRecorder) GetPlan(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlan", reflect.TypeOf((*MockIController)(nil).GetPlan), arg0)
+}
+
 // GetTaskById mocks base method.
 func (m *MockIController) GetTaskById(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -94,6 +298,18 @@ func (mr *MockIControllerMockRecorder) GetTaskByTag(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByTag", reflect.TypeOf((*MockIController)(nil).GetTaskByTag), arg0)
 }
 
+// GetTaskTree mocks base method.
+func (m *MockIController) GetTaskTree(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetTaskTree", arg0)
+}
+
+// GetTaskTree indicates an expected call of GetTaskTree.
+func (mr *MockIControllerMockRecorder) GetTaskTree(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTree", reflect.TypeOf((*MockIController)(nil).GetTaskTree), arg0)
+}
+
 // GetTasks mocks base method.
 func (m *MockIController) GetTasks(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -106,6 +322,138 @@ func (mr *MockIControllerMockRecorder) GetTasks(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasks", reflect.TypeOf((*MockIController)(nil).GetTasks), arg0)
 }
 
+// GetTrash mocks base method.
+func (m *MockIController) GetTrash(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetTrash", arg0)
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockIControllerMockRecorder) GetTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockIController)(nil).GetTrash), arg0)
+}
+
+// ImportListTasksMarkdown mocks base method.
+func (m *MockIController) ImportListTasksMarkdown(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportListTasksMarkdown", arg0)
+}
+
+// ImportListTasksMarkdown indicates an expected call of ImportListTasksMarkdown.
+func (mr *MockIControllerMockRecorder) ImportListTasksMarkdown(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportListTasksMarkdown", reflect.TypeOf((*MockIController)(nil).ImportListTasksMarkdown), arg0)
+}
+
+// ImportTasks mocks base method.
+func (m *MockIController) ImportTasks(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportTasks", arg0)
+}
+
+// ImportTasks indicates an expected call of ImportTasks.
+func (mr *MockIControllerMockRecorder) ImportTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasks", reflect.TypeOf((*MockIController)(nil).ImportTasks), arg0)
+}
+
+// ImportTasksICal mocks base method.
+func (m *MockIController) ImportTasksICal(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportTasksICal", arg0)
+}
+
+// ImportTasksICal indicates an expected call of ImportTasksICal.
+func (mr *MockIControllerMockRecorder) ImportTasksICal(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksICal", reflect.TypeOf((*MockIController)(nil).ImportTasksICal), arg0)
+}
+
+// ImportTasksTodoTxt mocks base method.
+func (m *MockIController) ImportTasksTodoTxt(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportTasksTodoTxt", arg0)
+}
+
+// ImportTasksTodoTxt indicates an expected call of ImportTasksTodoTxt.
+func (mr *MockIControllerMockRecorder) ImportTasksTodoTxt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksTodoTxt", reflect.TypeOf((*MockIController)(nil).ImportTasksTodoTxt), arg0)
+}
+
+// PatchTask mocks base method.
+func (m *MockIController) PatchTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PatchTask", arg0)
+}
+
+// PatchTask indicates an expected call of PatchTask.
+func (mr *MockIControllerMockRecorder) PatchTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTask", reflect.TypeOf((*MockIController)(nil).PatchTask), arg0)
+}
+
+// PurgeTask mocks base method.
+func (m *MockIController) PurgeTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PurgeTask", arg0)
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockIControllerMockRecorder) PurgeTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockIController)(nil).PurgeTask), arg0)
+}
+
+// RemoveBlocker mocks base method.
+func (m *MockIController) RemoveBlocker(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RemoveBlocker", arg0)
+}
+
+// RemoveBlocker indicates an expected call of RemoveBlocker.
+func (mr *MockIControllerMockRecorder) RemoveBlocker(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBlocker", reflect.TypeOf((*MockIController)(nil).RemoveBlocker), arg0)
+}
+
+// ReopenTask mocks base method.
+func (m *MockIController) ReopenTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReopenTask", arg0)
+}
+
+// ReopenTask indicates an expected call of ReopenTask.
+func (mr *MockIControllerMockRecorder) ReopenTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReopenTask", reflect.TypeOf((*MockIController)(nil).ReopenTask), arg0)
+}
+
+// RestoreTask mocks base method.
+func (m *MockIController) RestoreTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RestoreTask", arg0)
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockIControllerMockRecorder) RestoreTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockIController)(nil).RestoreTask), arg0)
+}
+
+// SearchListTasks mocks base method.
+func (m *MockIController) SearchListTasks(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SearchListTasks", arg0)
+}
+
+// SearchListTasks indicates an expected call of SearchListTasks.
+func (mr *MockIControllerMockRecorder) SearchListTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchListTasks", reflect.TypeOf((*MockIController)(nil).SearchListTasks), arg0)
+}
+
 // SearchTasks mocks base method.
 func (m *MockIController) SearchTasks(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -118,6 +466,18 @@ func (mr *MockIControllerMockRecorder) SearchTasks(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasks", reflect.TypeOf((*MockIController)(nil).SearchTasks), arg0)
 }
 
+// StartTask mocks base method.
+func (m *MockIController) StartTask(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartTask", arg0)
+}
+
+// StartTask indicates an expected call of StartTask.
+func (mr *MockIControllerMockRecorder) StartTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartTask", reflect.TypeOf((*MockIController)(nil).StartTask), arg0)
+}
+
 // UpdateTask mocks base method.
 func (m *MockIController) UpdateTask(arg0 *gin.Context) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/repositories (interfaces: IIdempotencyRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdempotencyRepo is a mock of IIdempotencyRepo interface.
+type MockIIdempotencyRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyRepoMockRecorder
+}
+
+// MockIIdempotencyRepoMockRecorder is the mock recorder for MockIIdempotencyRepo.
+type MockIIdempotencyRepoMockRecorder struct {
+	mock *MockIIdempotencyRepo
+}
+
+// NewMockIIdempotencyRepo creates a new mock instance.
+func NewMockIIdempotencyRepo(ctrl *gomock.Controller) *MockIIdempotencyRepo {
+	mock := &MockIIdempotencyRepo{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyRepo) EXPECT() *MockIIdempotencyRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateIdempotencyRecord mocks base method.
+func (m *MockIIdempotencyRepo) CreateIdempotencyRecord(arg0 *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotencyRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdempotencyRecord indicates an expected call of CreateIdempotencyRecord.
+func (mr *MockIIdempotencyRepoMockRecorder) CreateIdempotencyRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotencyRecord", reflect.TypeOf((*MockIIdempotencyRepo)(nil).CreateIdempotencyRecord), arg0)
+}
+
+// DeleteExpiredIdempotencyRecord mocks base method.
+func (m *MockIIdempotencyRepo) DeleteExpiredIdempotencyRecord(arg0 string, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdempotencyRecord", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredIdempotencyRecord indicates an expected call of DeleteExpiredIdempotencyRecord.
+func (mr *MockIIdempotencyRepoMockRecorder) DeleteExpiredIdempotencyRecord(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdempotencyRecord", reflect.TypeOf((*MockIIdempotencyRepo)(nil).DeleteExpiredIdempotencyRecord), arg0, arg1)
+}
+
+// DeleteIdempotencyRecord mocks base method.
+func (m *MockIIdempotencyRepo) DeleteIdempotencyRecord(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdempotencyRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteIdempotencyRecord indicates an expected call of DeleteIdempotencyRecord.
+func (mr *MockIIdempotencyRepoMockRecorder) DeleteIdempotencyRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdempotencyRecord", reflect.TypeOf((*MockIIdempotencyRepo)(nil).DeleteIdempotencyRecord), arg0)
+}
+
+// GetIdempotencyRecord mocks base method.
+func (m *MockIIdempotencyRepo) GetIdempotencyRecord(arg0 string) (entity.IdempotencyRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyRecord", arg0)
+	ret0, _ := ret[0].(entity.IdempotencyRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyRecord indicates an expected call of GetIdempotencyRecord.
+func (mr *MockIIdempotencyRepoMockRecorder) GetIdempotencyRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyRecord", reflect.TypeOf((*MockIIdempotencyRepo)(nil).GetIdempotencyRecord), arg0)
+}
+
+// PurgeIdempotencyRecords mocks base method.
+func (m *MockIIdempotencyRepo) PurgeIdempotencyRecords(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeIdempotencyRecords", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeIdempotencyRecords indicates an expected call of PurgeIdempotencyRecords.
+func (mr *MockIIdempotencyRepoMockRecorder) PurgeIdempotencyRecords(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeIdempotencyRecords", reflect.TypeOf((*MockIIdempotencyRepo)(nil).PurgeIdempotencyRecords), arg0)
+}
+
+// UpdateIdempotencyRecord mocks base method.
+func (m *MockIIdempotencyRepo) UpdateIdempotencyRecord(arg0 *entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIdempotencyRecord", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIdempotencyRecord indicates an expected call of UpdateIdempotencyRecord.
+func (mr *MockIIdempotencyRepoMockRecorder) UpdateIdempotencyRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIdempotencyRecord", reflect.TypeOf((*MockIIdempotencyRepo)(nil).UpdateIdempotencyRecord), arg0)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/services (interfaces: IIdempotencyService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdempotencyService is a mock of IIdempotencyService interface.
+type MockIIdempotencyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyServiceMockRecorder
+}
+
+// MockIIdempotencyServiceMockRecorder is the mock recorder for MockIIdempotencyService.
+type MockIIdempotencyServiceMockRecorder struct {
+	mock *MockIIdempotencyService
+}
+
+// NewMockIIdempotencyService creates a new mock instance.
+func NewMockIIdempotencyService(ctrl *gomock.Controller) *MockIIdempotencyService {
+	mock := &MockIIdempotencyService{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyService) EXPECT() *MockIIdempotencyServiceMockRecorder {
+	return m.recorder
+}
+
+// Begin mocks base method.
+func (m *MockIIdempotencyService) Begin(arg0, arg1 string) (entity.IdempotencyRecord, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", arg0, arg1)
+	ret0, _ := ret[0].(entity.IdempotencyRecord)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockIIdempotencyServiceMockRecorder) Begin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockIIdempotencyService)(nil).Begin), arg0, arg1)
+}
+
+// Complete mocks base method.
+func (m *MockIIdempotencyService) Complete(arg0 entity.IdempotencyRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockIIdempotencyServiceMockRecorder) Complete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockIIdempotencyService)(nil).Complete), arg0)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockIIdempotencyService) PurgeExpired() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockIIdempotencyServiceMockRecorder) PurgeExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockIIdempotencyService)(nil).PurgeExpired))
+}
+
+// Release mocks base method.
+func (m *MockIIdempotencyService) Release(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIIdempotencyServiceMockRecorder) Release(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIIdempotencyService)(nil).Release), arg0)
+}
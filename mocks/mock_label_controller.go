@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/controllers (interfaces: ILabelController)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILabelController is a mock of ILabelController interface.
+type MockILabelController struct {
+	ctrl     *gomock.Controller
+	recorder *MockILabelControllerMockRecorder
+}
+
+// MockILabelControllerMockRecorder is the mock recorder for MockILabelController.
+type MockILabelControllerMockRecorder struct {
+	mock *MockILabelController
+}
+
+// NewMockILabelController creates a new mock instance.
+func NewMockILabelController(ctrl *gomock.Controller) *MockILabelController {
+	mock := &MockILabelController{ctrl: ctrl}
+	mock.recorder = &MockILabelControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILabelController) EXPECT() *MockILabelControllerMockRecorder {
+	return m.recorder
+}
+
+// AttachLabel mocks base method.
+func (m *MockILabelController) AttachLabel(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AttachLabel", arg0)
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockILabelControllerMockRecorder) AttachLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockILabelController)(nil).AttachLabel), arg0)
+}
+
+// CreateLabel mocks base method.
+func (m *MockILabelController) CreateLabel(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateLabel", arg0)
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockILabelControllerMockRecorder) CreateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockILabelController)(nil).CreateLabel), arg0)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockILabelController) DeleteLabel(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLabel", arg0)
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockILabelControllerMockRecorder) DeleteLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockILabelController)(nil).DeleteLabel), arg0)
+}
+
+// DetachLabel mocks base method.
+func (m *MockILabelController) DetachLabel(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DetachLabel", arg0)
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockILabelControllerMockRecorder) DetachLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockILabelController)(nil).DetachLabel), arg0)
+}
+
+// GetLabelById mocks base method.
+func (m *MockILabelController) GetLabelById(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLabelById", arg0)
+}
+
+// GetLabelById indicates an expected call of GetLabelById.
+func (mr *MockILabelControllerMockRecorder) GetLabelById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelById", reflect.TypeOf((*MockILabelController)(nil).GetLabelById), arg0)
+}
+
+// GetLabels mocks base method.
+func (m *MockILabelController) GetLabels(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLabels", arg0)
+}
+
+// GetLabels indicates an expected call of GetLabels.
+func (mr *MockILabelControllerMockRecorder) GetLabels(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabels", reflect.TypeOf((*MockILabelController)(nil).GetLabels), arg0)
+}
+
+// GetTaskLabels mocks base method.
+func (m *MockILabelController) GetTaskLabels(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetTaskLabels", arg0)
+}
+
+// GetTaskLabels indicates an expected call of GetTaskLabels.
+func (mr *MockILabelControllerMockRecorder) GetTaskLabels(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskLabels", reflect.TypeOf((*MockILabelController)(nil).GetTaskLabels), arg0)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockILabelController) UpdateLabel(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateLabel", arg0)
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockILabelControllerMockRecorder) UpdateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockILabelController)(nil).UpdateLabel), arg0)
+}
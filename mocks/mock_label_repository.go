@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/repositories (interfaces: ILabelRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILabelRepo is a mock of ILabelRepo interface.
+type MockILabelRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockILabelRepoMockRecorder
+}
+
+// MockILabelRepoMockRecorder is the mock recorder for MockILabelRepo.
+type MockILabelRepoMockRecorder struct {
+	mock *MockILabelRepo
+}
+
+// NewMockILabelRepo creates a new mock instance.
+func NewMockILabelRepo(ctrl *gomock.Controller) *MockILabelRepo {
+	mock := &MockILabelRepo{ctrl: ctrl}
+	mock.recorder = &MockILabelRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILabelRepo) EXPECT() *MockILabelRepoMockRecorder {
+	return m.recorder
+}
+
+// AttachLabel mocks base method.
+func (m *MockILabelRepo) AttachLabel(arg0, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockILabelRepoMockRecorder) AttachLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockILabelRepo)(nil).AttachLabel), arg0, arg1)
+}
+
+// CreateLabel mocks base method.
+func (m *MockILabelRepo) CreateLabel(arg0 *entity.Label) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockILabelRepoMockRecorder) CreateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockILabelRepo)(nil).CreateLabel), arg0)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockILabelRepo) DeleteLabel(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockILabelRepoMockRecorder) DeleteLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockILabelRepo)(nil).DeleteLabel), arg0)
+}
+
+// DetachLabel mocks base method.
+func (m *MockILabelRepo) DetachLabel(arg0, arg1 uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockILabelRepoMockRecorder) DetachLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockILabelRepo)(nil).DetachLabel), arg0, arg1)
+}
+
+// GetAllLabels mocks base method.
+func (m *MockILabelRepo) GetAllLabels() ([]entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllLabels")
+	ret0, _ := ret[0].([]entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllLabels indicates an expected call of GetAllLabels.
+func (mr *MockILabelRepoMockRecorder) GetAllLabels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLabels", reflect.TypeOf((*MockILabelRepo)(nil).GetAllLabels))
+}
+
+// GetLabelById mocks base method.
+func (m *MockILabelRepo) GetLabelById(arg0 int) (entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelById", arg0)
+	ret0, _ := ret[0].(entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelById indicates an expected call of GetLabelById.
+func (mr *MockILabelRepoMockRecorder) GetLabelById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelById", reflect.TypeOf((*MockILabelRepo)(nil).GetLabelById), arg0)
+}
+
+// GetLabelByName mocks base method.
+func (m *MockILabelRepo) GetLabelByName(arg0 string) (entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelByName", arg0)
+	ret0, _ := ret[0].(entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelByName indicates an expected call of GetLabelByName.
+func (mr *MockILabelRepoMockRecorder) GetLabelByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelByName", reflect.TypeOf((*MockILabelRepo)(nil).GetLabelByName), arg0)
+}
+
+// GetLabelsOfTasks mocks base method.
+func (m *MockILabelRepo) GetLabelsOfTasks(arg0 []uint) (map[uint][]entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelsOfTasks", arg0)
+	ret0, _ := ret[0].(map[uint][]entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelsOfTasks indicates an expected call of GetLabelsOfTasks.
+func (mr *MockILabelRepoMockRecorder) GetLabelsOfTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelsOfTasks", reflect.TypeOf((*MockILabelRepo)(nil).GetLabelsOfTasks), arg0)
+}
+
+// GetTaskLabels mocks base method.
+func (m *MockILabelRepo) GetTaskLabels(arg0 uint) ([]entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskLabels", arg0)
+	ret0, _ := ret[0].([]entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskLabels indicates an expected call of GetTaskLabels.
+func (mr *MockILabelRepoMockRecorder) GetTaskLabels(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskLabels", reflect.TypeOf((*MockILabelRepo)(nil).GetTaskLabels), arg0)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockILabelRepo) UpdateLabel(arg0 *entity.Label) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockILabelRepoMockRecorder) UpdateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockILabelRepo)(nil).UpdateLabel), arg0)
+}
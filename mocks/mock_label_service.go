@@ -0,0 +1,150 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/services (interfaces: ILabelService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockILabelService is a mock of ILabelService interface.
+type MockILabelService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILabelServiceMockRecorder
+}
+
+// MockILabelServiceMockRecorder is the mock recorder for MockILabelService.
+type MockILabelServiceMockRecorder struct {
+	mock *MockILabelService
+}
+
+// NewMockILabelService creates a new mock instance.
+func NewMockILabelService(ctrl *gomock.Controller) *MockILabelService {
+	mock := &MockILabelService{ctrl: ctrl}
+	mock.recorder = &MockILabelServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILabelService) EXPECT() *MockILabelServiceMockRecorder {
+	return m.recorder
+}
+
+// AttachLabel mocks base method.
+func (m *MockILabelService) AttachLabel(arg0, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockILabelServiceMockRecorder) AttachLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockILabelService)(nil).AttachLabel), arg0, arg1)
+}
+
+// CreateLabel mocks base method.
+func (m *MockILabelService) CreateLabel(arg0 *entity.Label) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockILabelServiceMockRecorder) CreateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockILabelService)(nil).CreateLabel), arg0)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockILabelService) DeleteLabel(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockILabelServiceMockRecorder) DeleteLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockILabelService)(nil).DeleteLabel), arg0)
+}
+
+// DetachLabel mocks base method.
+func (m *MockILabelService) DetachLabel(arg0, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLabel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockILabelServiceMockRecorder) DetachLabel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockILabelService)(nil).DetachLabel), arg0, arg1)
+}
+
+// GetAllLabels mocks base method.
+func (m *MockILabelService) GetAllLabels() ([]entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllLabels")
+	ret0, _ := ret[0].([]entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllLabels indicates an expected call of GetAllLabels.
+func (mr *MockILabelServiceMockRecorder) GetAllLabels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLabels", reflect.TypeOf((*MockILabelService)(nil).GetAllLabels))
+}
+
+// GetLabelById mocks base method.
+func (m *MockILabelService) GetLabelById(arg0 int) (entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabelById", arg0)
+	ret0, _ := ret[0].(entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabelById indicates an expected call of GetLabelById.
+func (mr *MockILabelServiceMockRecorder) GetLabelById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabelById", reflect.TypeOf((*MockILabelService)(nil).GetLabelById), arg0)
+}
+
+// GetTaskLabels mocks base method.
+func (m *MockILabelService) GetTaskLabels(arg0 int) ([]entity.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskLabels", arg0)
+	ret0, _ := ret[0].([]entity.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskLabels indicates an expected call of GetTaskLabels.
+func (mr *MockILabelServiceMockRecorder) GetTaskLabels(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskLabels", reflect.TypeOf((*MockILabelService)(nil).GetTaskLabels), arg0)
+}
+
+// UpdateLabel mocks base method.
+func (m *MockILabelService) UpdateLabel(arg0 *entity.Label) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLabel", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLabel indicates an expected call of UpdateLabel.
+func (mr *MockILabelServiceMockRecorder) UpdateLabel(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLabel", reflect.TypeOf((*MockILabelService)(nil).UpdateLabel), arg0)
+}
@@ -0,0 +1,119 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/controllers (interfaces: IListController)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIListController is a mock of IListController interface.
+type MockIListController struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListControllerMockRecorder
+}
+
+// MockIListControllerMockRecorder is the mock recorder for MockIListController.
+type MockIListControllerMockRecorder struct {
+	mock *MockIListController
+}
+
+// NewMockIListController creates a new mock instance.
+func NewMockIListController(ctrl *gomock.Controller) *MockIListController {
+	mock := &MockIListController{ctrl: ctrl}
+	mock.recorder = &MockIListControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIListController) EXPECT() *MockIListControllerMockRecorder {
+	return m.recorder
+}
+
+// CreateList mocks base method.
+func (m *MockIListController) CreateList(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateList", arg0)
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockIListControllerMockRecorder) CreateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockIListController)(nil).CreateList), arg0)
+}
+
+// CreateListFeed mocks base method.
+func (m *MockIListController) CreateListFeed(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateListFeed", arg0)
+}
+
+// CreateListFeed indicates an expected call of CreateListFeed.
+func (mr *MockIListControllerMockRecorder) CreateListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListFeed", reflect.TypeOf((*MockIListController)(nil).CreateListFeed), arg0)
+}
+
+// DeleteList mocks base method.
+func (m *MockIListController) DeleteList(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteList", arg0)
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockIListControllerMockRecorder) DeleteList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockIListController)(nil).DeleteList), arg0)
+}
+
+// DeleteListFeed mocks base method.
+func (m *MockIListController) DeleteListFeed(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteListFeed", arg0)
+}
+
+// DeleteListFeed indicates an expected call of DeleteListFeed.
+func (mr *MockIListControllerMockRecorder) DeleteListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListFeed", reflect.TypeOf((*MockIListController)(nil).DeleteListFeed), arg0)
+}
+
+// GetListById mocks base method.
+func (m *MockIListController) GetListById(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetListById", arg0)
+}
+
+// GetListById indicates an expected call of GetListById.
+func (mr *MockIListControllerMockRecorder) GetListById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListById", reflect.TypeOf((*MockIListController)(nil).GetListById), arg0)
+}
+
+// GetLists mocks base method.
+func (m *MockIListController) GetLists(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLists", arg0)
+}
+
+// GetLists indicates an expected call of GetLists.
+func (mr *MockIListControllerMockRecorder) GetLists(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLists", reflect.TypeOf((*MockIListController)(nil).GetLists), arg0)
+}
+
+// UpdateList mocks base method.
+func (m *MockIListController) UpdateList(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateList", arg0)
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockIListControllerMockRecorder) UpdateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockIListController)(nil).UpdateList), arg0)
+}
@@ -0,0 +1,165 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/repositories (interfaces: IListRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIListRepo is a mock of IListRepo interface.
+type MockIListRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListRepoMockRecorder
+}
+
+// MockIListRepoMockRecorder is the mock recorder for MockIListRepo.
+type MockIListRepoMockRecorder struct {
+	mock *MockIListRepo
+}
+
+// NewMockIListRepo creates a new mock instance.
+func NewMockIListRepo(ctrl *gomock.Controller) *MockIListRepo {
+	mock := &MockIListRepo{ctrl: ctrl}
+	mock.recorder = &MockIListRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIListRepo) EXPECT() *MockIListRepoMockRecorder {
+	return m.recorder
+}
+
+// CountTasks mocks base method.
+func (m *MockIListRepo) CountTasks(arg0 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasks", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasks indicates an expected call of CountTasks.
+func (mr *MockIListRepoMockRecorder) CountTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasks", reflect.TypeOf((*MockIListRepo)(nil).CountTasks), arg0)
+}
+
+// CreateList mocks base method.
+func (m *MockIListRepo) CreateList(arg0 *entity.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockIListRepoMockRecorder) CreateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockIListRepo)(nil).CreateList), arg0)
+}
+
+// DeleteList mocks base method.
+func (m *MockIListRepo) DeleteList(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockIListRepoMockRecorder) DeleteList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockIListRepo)(nil).DeleteList), arg0)
+}
+
+// DeleteListFeed mocks base method.
+func (m *MockIListRepo) DeleteListFeed(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteListFeed", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteListFeed indicates an expected call of DeleteListFeed.
+func (mr *MockIListRepoMockRecorder) DeleteListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListFeed", reflect.TypeOf((*MockIListRepo)(nil).DeleteListFeed), arg0)
+}
+
+// GetAllLists mocks base method.
+func (m *MockIListRepo) GetAllLists() ([]entity.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllLists")
+	ret0, _ := ret[0].([]entity.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllLists indicates an expected call of GetAllLists.
+func (mr *MockIListRepoMockRecorder) GetAllLists() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLists", reflect.TypeOf((*MockIListRepo)(nil).GetAllLists))
+}
+
+// GetListById mocks base method.
+func (m *MockIListRepo) GetListById(arg0 int) (entity.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListById", arg0)
+	ret0, _ := ret[0].(entity.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListById indicates an expected call of GetListById.
+func (mr *MockIListRepoMockRecorder) GetListById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListById", reflect.TypeOf((*MockIListRepo)(nil).GetListById), arg0)
+}
+
+// GetListFeed mocks base method.
+func (m *MockIListRepo) GetListFeed(arg0 string) (entity.ListFeed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListFeed", arg0)
+	ret0, _ := ret[0].(entity.ListFeed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListFeed indicates an expected call of GetListFeed.
+func (mr *MockIListRepoMockRecorder) GetListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListFeed", reflect.TypeOf((*MockIListRepo)(nil).GetListFeed), arg0)
+}
+
+// SaveListFeed mocks base method.
+func (m *MockIListRepo) SaveListFeed(arg0 entity.ListFeed) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveListFeed", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveListFeed indicates an expected call of SaveListFeed.
+func (mr *MockIListRepoMockRecorder) SaveListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveListFeed", reflect.TypeOf((*MockIListRepo)(nil).SaveListFeed), arg0)
+}
+
+// UpdateList mocks base method.
+func (m *MockIListRepo) UpdateList(arg0 *entity.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockIListRepoMockRecorder) UpdateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockIListRepo)(nil).UpdateList), arg0)
+}
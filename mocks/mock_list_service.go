@@ -0,0 +1,136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/services (interfaces: IListService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIListService is a mock of IListService interface.
+type MockIListService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListServiceMockRecorder
+}
+
+// MockIListServiceMockRecorder is the mock recorder for MockIListService.
+type MockIListServiceMockRecorder struct {
+	mock *MockIListService
+}
+
+// NewMockIListService creates a new mock instance.
+func NewMockIListService(ctrl *gomock.Controller) *MockIListService {
+	mock := &MockIListService{ctrl: ctrl}
+	mock.recorder = &MockIListServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIListService) EXPECT() *MockIListServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateList mocks base method.
+func (m *MockIListService) CreateList(arg0 *entity.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockIListServiceMockRecorder) CreateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockIListService)(nil).CreateList), arg0)
+}
+
+// CreateListFeed mocks base method.
+func (m *MockIListService) CreateListFeed(arg0 int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListFeed", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListFeed indicates an expected call of CreateListFeed.
+func (mr *MockIListServiceMockRecorder) CreateListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListFeed", reflect.TypeOf((*MockIListService)(nil).CreateListFeed), arg0)
+}
+
+// DeleteList mocks base method.
+func (m *MockIListService) DeleteList(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteList indicates an expected call of DeleteList.
+func (mr *MockIListServiceMockRecorder) DeleteList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteList", reflect.TypeOf((*MockIListService)(nil).DeleteList), arg0)
+}
+
+// DeleteListFeed mocks base method.
+func (m *MockIListService) DeleteListFeed(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteListFeed", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteListFeed indicates an expected call of DeleteListFeed.
+func (mr *MockIListServiceMockRecorder) DeleteListFeed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListFeed", reflect.TypeOf((*MockIListService)(nil).DeleteListFeed), arg0)
+}
+
+// GetAllLists mocks base method.
+func (m *MockIListService) GetAllLists() ([]entity.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllLists")
+	ret0, _ := ret[0].([]entity.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllLists indicates an expected call of GetAllLists.
+func (mr *MockIListServiceMockRecorder) GetAllLists() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllLists", reflect.TypeOf((*MockIListService)(nil).GetAllLists))
+}
+
+// GetListById mocks base method.
+func (m *MockIListService) GetListById(arg0 int) (entity.List, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListById", arg0)
+	ret0, _ := ret[0].(entity.List)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListById indicates an expected call of GetListById.
+func (mr *MockIListServiceMockRecorder) GetListById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListById", reflect.TypeOf((*MockIListService)(nil).GetListById), arg0)
+}
+
+// UpdateList mocks base method.
+func (m *MockIListService) UpdateList(arg0 *entity.List) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateList", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateList indicates an expected call of UpdateList.
+func (mr *MockIListServiceMockRecorder) UpdateList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateList", reflect.TypeOf((*MockIListService)(nil).UpdateList), arg0)
+}
@@ -1,13 +1,15 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: todo-lists/core (interfaces: IRepo)
+// Source: todo-lists/repositories (interfaces: IRepo)
 
-// Package core is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
 	reflect "reflect"
 	time "time"
 	entity "todo-lists/entity"
+	query "todo-lists/query"
+	repositories "todo-lists/repositories"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,35 @@ func (m *MockIRepo) EXPECT() *MockIRepoMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockIRepo) AddDependency(arg0 entity.Dependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockIRepoMockRecorder) AddDependency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockIRepo)(nil).AddDependency), arg0)
+}
+
+// CountTasksByStatus mocks base method.
+func (m *MockIRepo) CountTasksByStatus(arg0 query.Expr) (map[string]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTasksByStatus", arg0)
+	ret0, _ := ret[0].(map[string]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTasksByStatus indicates an expected call of CountTasksByStatus.
+func (mr *MockIRepoMockRecorder) CountTasksByStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTasksByStatus", reflect.TypeOf((*MockIRepo)(nil).CountTasksByStatus), arg0)
+}
+
 // CreateTask mocks base method.
 func (m *MockIRepo) CreateTask(arg0 *entity.Task) error {
 	m.ctrl.T.Helper()
@@ -50,47 +81,77 @@ func (mr *MockIRepoMockRecorder) CreateTask(arg0 interface{}) *gomock.Call {
 }
 
 // DeleteTask mocks base method.
-func (m *MockIRepo) DeleteTask(arg0 int) error {
+func (m *MockIRepo) DeleteTask(arg0 int, arg1 uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", arg0)
+	ret := m.ctrl.Call(m, "DeleteTask", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockIRepoMockRecorder) DeleteTask(arg0 interface{}) *gomock.Call {
+func (mr *MockIRepoMockRecorder) DeleteTask(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockIRepo)(nil).DeleteTask), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockIRepo)(nil).DeleteTask), arg0, arg1)
 }
 
-// FilterTasksByDeadline mocks base method.
-func (m *MockIRepo) FilterTasksByDeadline(arg0, arg1 time.Time) ([]entity.Task, error) {
+// GetAllTasks mocks base method.
+func (m *MockIRepo) GetAllTasks(arg0 string, arg1 entity.PageRequest) (entity.TaskPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterTasksByDeadline", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllTasks", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTasks indicates an expected call of GetAllTasks.
+func (mr *MockIRepoMockRecorder) GetAllTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockIRepo)(nil).GetAllTasks), arg0, arg1)
+}
+
+// GetDependencies mocks base method.
+func (m *MockIRepo) GetDependencies(arg0 []uint) ([]entity.Dependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDependencies", arg0)
+	ret0, _ := ret[0].([]entity.Dependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDependencies indicates an expected call of GetDependencies.
+func (mr *MockIRepoMockRecorder) GetDependencies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDependencies", reflect.TypeOf((*MockIRepo)(nil).GetDependencies), arg0)
+}
+
+// GetRecurringTasks mocks base method.
+func (m *MockIRepo) GetRecurringTasks(arg0 time.Time) ([]entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringTasks", arg0)
 	ret0, _ := ret[0].([]entity.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FilterTasksByDeadline indicates an expected call of FilterTasksByDeadline.
-func (mr *MockIRepoMockRecorder) FilterTasksByDeadline(arg0, arg1 interface{}) *gomock.Call {
+// GetRecurringTasks indicates an expected call of GetRecurringTasks.
+func (mr *MockIRepoMockRecorder) GetRecurringTasks(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterTasksByDeadline", reflect.TypeOf((*MockIRepo)(nil).FilterTasksByDeadline), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringTasks", reflect.TypeOf((*MockIRepo)(nil).GetRecurringTasks), arg0)
 }
 
-// GetAllTasks mocks base method.
-func (m *MockIRepo) GetAllTasks() ([]entity.Task, error) {
+// GetSubtasks mocks base method.
+func (m *MockIRepo) GetSubtasks(arg0 []uint) ([]entity.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTasks")
+	ret := m.ctrl.Call(m, "GetSubtasks", arg0)
 	ret0, _ := ret[0].([]entity.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllTasks indicates an expected call of GetAllTasks.
-func (mr *MockIRepoMockRecorder) GetAllTasks() *gomock.Call {
+// GetSubtasks indicates an expected call of GetSubtasks.
+func (mr *MockIRepoMockRecorder) GetSubtasks(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockIRepo)(nil).GetAllTasks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubtasks", reflect.TypeOf((*MockIRepo)(nil).GetSubtasks), arg0)
 }
 
 // GetTaskById mocks base method.
@@ -108,34 +169,180 @@ func (mr *MockIRepoMockRecorder) GetTaskById(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskById", reflect.TypeOf((*MockIRepo)(nil).GetTaskById), arg0)
 }
 
-// GetTasksByTag mocks base method.
-func (m *MockIRepo) GetTasksByTag(arg0 string) ([]entity.Task, error) {
+// GetTaskByUID mocks base method.
+func (m *MockIRepo) GetTaskByUID(arg0 string) (entity.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasksByTag", arg0)
-	ret0, _ := ret[0].([]entity.Task)
+	ret := m.ctrl.Call(m, "GetTaskByUID", arg0)
+	ret0, _ := ret[0].(entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskByUID indicates an expected call of GetTaskByUID.
+func (mr *MockIRepoMockRecorder) GetTaskByUID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskByUID", reflect.TypeOf((*MockIRepo)(nil).GetTaskByUID), arg0)
+}
+
+// GetTasksByListId mocks base method.
+func (m *MockIRepo) GetTasksByListId(arg0 int, arg1 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByListId", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByListId indicates an expected call of GetTasksByListId.
+func (mr *MockIRepoMockRecorder) GetTasksByListId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByListId", reflect.TypeOf((*MockIRepo)(nil).GetTasksByListId), arg0, arg1)
+}
+
+// GetTrash mocks base method.
+func (m *MockIRepo) GetTrash(arg0 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockIRepoMockRecorder) GetTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockIRepo)(nil).GetTrash), arg0)
+}
+
+// PatchTask mocks base method.
+func (m *MockIRepo) PatchTask(arg0 int, arg1 uint, arg2 map[string]interface{}) (entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTask", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTasksByTag indicates an expected call of GetTasksByTag.
-func (mr *MockIRepoMockRecorder) GetTasksByTag(arg0 interface{}) *gomock.Call {
+// PatchTask indicates an expected call of PatchTask.
+func (mr *MockIRepoMockRecorder) PatchTask(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByTag", reflect.TypeOf((*MockIRepo)(nil).GetTasksByTag), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTask", reflect.TypeOf((*MockIRepo)(nil).PatchTask), arg0, arg1, arg2)
+}
+
+// PurgeTask mocks base method.
+func (m *MockIRepo) PurgeTask(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockIRepoMockRecorder) PurgeTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockIRepo)(nil).PurgeTask), arg0)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockIRepo) PurgeTrash(arg0 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockIRepoMockRecorder) PurgeTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockIRepo)(nil).PurgeTrash), arg0)
+}
+
+// QueryTasks mocks base method.
+func (m *MockIRepo) QueryTasks(arg0 query.Expr, arg1 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTasks", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTasks indicates an expected call of QueryTasks.
+func (mr *MockIRepoMockRecorder) QueryTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTasks", reflect.TypeOf((*MockIRepo)(nil).QueryTasks), arg0, arg1)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockIRepo) RemoveDependency(arg0 entity.Dependency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockIRepoMockRecorder) RemoveDependency(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockIRepo)(nil).RemoveDependency), arg0)
+}
+
+// RestoreTask mocks base method.
+func (m *MockIRepo) RestoreTask(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockIRepoMockRecorder) RestoreTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockIRepo)(nil).RestoreTask), arg0)
+}
+
+// SearchListTasksByName mocks base method.
+func (m *MockIRepo) SearchListTasksByName(arg0 int, arg1 string, arg2 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchListTasksByName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchListTasksByName indicates an expected call of SearchListTasksByName.
+func (mr *MockIRepoMockRecorder) SearchListTasksByName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchListTasksByName", reflect.TypeOf((*MockIRepo)(nil).SearchListTasksByName), arg0, arg1, arg2)
 }
 
 // SearchTasksByName mocks base method.
-func (m *MockIRepo) SearchTasksByName(arg0 string) ([]entity.Task, error) {
+func (m *MockIRepo) SearchTasksByName(arg0 string, arg1 entity.PageRequest) (entity.TaskPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchTasksByName", arg0)
-	ret0, _ := ret[0].([]entity.Task)
+	ret := m.ctrl.Call(m, "SearchTasksByName", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchTasksByName indicates an expected call of SearchTasksByName.
-func (mr *MockIRepoMockRecorder) SearchTasksByName(arg0 interface{}) *gomock.Call {
+func (mr *MockIRepoMockRecorder) SearchTasksByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasksByName", reflect.TypeOf((*MockIRepo)(nil).SearchTasksByName), arg0, arg1)
+}
+
+// Transaction mocks base method.
+func (m *MockIRepo) Transaction(arg0 func(repositories.IRepo, repositories.IListRepo, repositories.ILabelRepo) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockIRepoMockRecorder) Transaction(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasksByName", reflect.TypeOf((*MockIRepo)(nil).SearchTasksByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockIRepo)(nil).Transaction), arg0)
 }
 
 // UpdateTask mocks base method.
@@ -151,3 +358,18 @@ func (mr *MockIRepoMockRecorder) UpdateTask(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockIRepo)(nil).UpdateTask), arg0)
 }
+
+// UpdateTaskStatus mocks base method.
+func (m *MockIRepo) UpdateTaskStatus(arg0 int, arg1 uint, arg2 string, arg3 *time.Time) (entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskStatus indicates an expected call of UpdateTaskStatus.
+func (mr *MockIRepoMockRecorder) UpdateTaskStatus(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskStatus", reflect.TypeOf((*MockIRepo)(nil).UpdateTaskStatus), arg0, arg1, arg2, arg3)
+}
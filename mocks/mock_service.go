@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
 // Source: todo-lists/services (interfaces: IService)
 
-// Package services is a generated GoMock package.
+// Package mocks is a generated GoMock package.
 package mocks
 
 import (
+	io "io"
 	reflect "reflect"
 	time "time"
 	entity "todo-lists/entity"
@@ -35,6 +36,35 @@ func (m *MockIService) EXPECT() *MockIServiceMockRecorder {
 	return m.recorder
 }
 
+// AddBlocker mocks base method.
+func (m *MockIService) AddBlocker(arg0, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBlocker", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBlocker indicates an expected call of AddBlocker.
+func (mr *MockIServiceMockRecorder) AddBlocker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlocker", reflect.TypeOf((*MockIService)(nil).AddBlocker), arg0, arg1)
+}
+
+// BulkTasks mocks base method.
+func (m *MockIService) BulkTasks(arg0 entity.BulkRequest, arg1 *time.Location) ([]entity.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkTasks", arg0, arg1)
+	ret0, _ := ret[0].([]entity.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkTasks indicates an expected call of BulkTasks.
+func (mr *MockIServiceMockRecorder) BulkTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkTasks", reflect.TypeOf((*MockIService)(nil).BulkTasks), arg0, arg1)
+}
+
 // CreateTask mocks base method.
 func (m *MockIService) CreateTask(arg0 *entity.Task) error {
 	m.ctrl.T.Helper()
@@ -50,47 +80,184 @@ func (mr *MockIServiceMockRecorder) CreateTask(arg0 interface{}) *gomock.Call {
 }
 
 // DeleteTask mocks base method.
-func (m *MockIService) DeleteTask(arg0 int) error {
+func (m *MockIService) DeleteTask(arg0 int, arg1 uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteTask", arg0)
+	ret := m.ctrl.Call(m, "DeleteTask", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteTask indicates an expected call of DeleteTask.
-func (mr *MockIServiceMockRecorder) DeleteTask(arg0 interface{}) *gomock.Call {
+func (mr *MockIServiceMockRecorder) DeleteTask(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockIService)(nil).DeleteTask), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockIService)(nil).DeleteTask), arg0, arg1)
 }
 
-// FilterTasksByDeadline mocks base method.
-func (m *MockIService) FilterTasksByDeadline(arg0, arg1 time.Time) ([]entity.Task, error) {
+// ExportLabeledTasks mocks base method.
+func (m *MockIService) ExportLabeledTasks(arg0 entity.TaskFilter, arg1 *time.Location) ([]entity.LabeledTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportLabeledTasks", arg0, arg1)
+	ret0, _ := ret[0].([]entity.LabeledTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportLabeledTasks indicates an expected call of ExportLabeledTasks.
+func (mr *MockIServiceMockRecorder) ExportLabeledTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportLabeledTasks", reflect.TypeOf((*MockIService)(nil).ExportLabeledTasks), arg0, arg1)
+}
+
+// ExportListTasks mocks base method.
+func (m *MockIService) ExportListTasks(arg0 int, arg1 entity.TaskFilter, arg2 *time.Location) (entity.List, []entity.LabeledTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportListTasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.List)
+	ret1, _ := ret[1].([]entity.LabeledTask)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportListTasks indicates an expected call of ExportListTasks.
+func (mr *MockIServiceMockRecorder) ExportListTasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportListTasks", reflect.TypeOf((*MockIService)(nil).ExportListTasks), arg0, arg1, arg2)
+}
+
+// ExportTasks mocks base method.
+func (m *MockIService) ExportTasks(arg0 entity.TaskFilter, arg1 *time.Location) ([]entity.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FilterTasksByDeadline", arg0, arg1)
+	ret := m.ctrl.Call(m, "ExportTasks", arg0, arg1)
 	ret0, _ := ret[0].([]entity.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ExportTasks indicates an expected call of ExportTasks.
+func (mr *MockIServiceMockRecorder) ExportTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportTasks", reflect.TypeOf((*MockIService)(nil).ExportTasks), arg0, arg1)
+}
+
+// FilterListTasksByDeadline mocks base method.
+func (m *MockIService) FilterListTasksByDeadline(arg0 int, arg1, arg2 time.Time, arg3 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterListTasksByDeadline", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterListTasksByDeadline indicates an expected call of FilterListTasksByDeadline.
+func (mr *MockIServiceMockRecorder) FilterListTasksByDeadline(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterListTasksByDeadline", reflect.TypeOf((*MockIService)(nil).FilterListTasksByDeadline), arg0, arg1, arg2, arg3)
+}
+
+// FilterTasksByDeadline mocks base method.
+func (m *MockIService) FilterTasksByDeadline(arg0, arg1 time.Time, arg2 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterTasksByDeadline", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // FilterTasksByDeadline indicates an expected call of FilterTasksByDeadline.
-func (mr *MockIServiceMockRecorder) FilterTasksByDeadline(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockIServiceMockRecorder) FilterTasksByDeadline(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterTasksByDeadline", reflect.TypeOf((*MockIService)(nil).FilterTasksByDeadline), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterTasksByDeadline", reflect.TypeOf((*MockIService)(nil).FilterTasksByDeadline), arg0, arg1, arg2)
 }
 
 // GetAllTasks mocks base method.
-func (m *MockIService) GetAllTasks() ([]entity.Task, error) {
+func (m *MockIService) GetAllTasks(arg0 string, arg1 entity.PageRequest) (entity.TaskPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTasks")
-	ret0, _ := ret[0].([]entity.Task)
+	ret := m.ctrl.Call(m, "GetAllTasks", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllTasks indicates an expected call of GetAllTasks.
-func (mr *MockIServiceMockRecorder) GetAllTasks() *gomock.Call {
+func (mr *MockIServiceMockRecorder) GetAllTasks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockIService)(nil).GetAllTasks), arg0, arg1)
+}
+
+// GetBlockers mocks base method.
+func (m *MockIService) GetBlockers(arg0 int) ([]entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockers", arg0)
+	ret0, _ := ret[0].([]entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockers indicates an expected call of GetBlockers.
+func (mr *MockIServiceMockRecorder) GetBlockers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockers", reflect.TypeOf((*MockIService)(nil).GetBlockers), arg0)
+}
+
+// GetFeedTasks mocks base method.
+func (m *MockIService) GetFeedTasks(arg0 string) (entity.List, []entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeedTasks", arg0)
+	ret0, _ := ret[0].(entity.List)
+	ret1, _ := ret[1].([]entity.Task)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFeedTasks indicates an expected call of GetFeedTasks.
+func (mr *MockIServiceMockRecorder) GetFeedTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeedTasks", reflect.TypeOf((*MockIService)(nil).GetFeedTasks), arg0)
+}
+
+// GetListTasksByTag mocks base method.
+func (m *MockIService) GetListTasksByTag(arg0 int, arg1 []string, arg2 bool, arg3 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListTasksByTag", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListTasksByTag indicates an expected call of GetListTasksByTag.
+func (mr *MockIServiceMockRecorder) GetListTasksByTag(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTasksByTag", reflect.TypeOf((*MockIService)(nil).GetListTasksByTag), arg0, arg1, arg2, arg3)
+}
+
+// GetOccurrences mocks base method.
+func (m *MockIService) GetOccurrences(arg0, arg1 time.Time) ([]entity.Occurrence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOccurrences", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Occurrence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOccurrences indicates an expected call of GetOccurrences.
+func (mr *MockIServiceMockRecorder) GetOccurrences(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTasks", reflect.TypeOf((*MockIService)(nil).GetAllTasks))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOccurrences", reflect.TypeOf((*MockIService)(nil).GetOccurrences), arg0, arg1)
+}
+
+// GetPlan mocks base method.
+func (m *MockIService) GetPlan(arg0 int) ([]entity.PlanItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlan", arg0)
+	ret0, _ := ret[0].([]entity.PlanItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlan indicates an expected call of GetPlan.
+func (mr *MockIServiceMockRecorder) GetPlan(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlan", reflect.TypeOf((*MockIService)(nil).GetPlan), arg0)
 }
 
 // GetTaskById mocks base method.
@@ -108,34 +275,261 @@ func (mr *MockIServiceMockRecorder) GetTaskById(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskById", reflect.TypeOf((*MockIService)(nil).GetTaskById), arg0)
 }
 
+// GetTaskTree mocks base method.
+func (m *MockIService) GetTaskTree(arg0 int) (entity.TaskTree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskTree", arg0)
+	ret0, _ := ret[0].(entity.TaskTree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskTree indicates an expected call of GetTaskTree.
+func (mr *MockIServiceMockRecorder) GetTaskTree(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskTree", reflect.TypeOf((*MockIService)(nil).GetTaskTree), arg0)
+}
+
+// GetTasksByListId mocks base method.
+func (m *MockIService) GetTasksByListId(arg0 int, arg1 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTasksByListId", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTasksByListId indicates an expected call of GetTasksByListId.
+func (mr *MockIServiceMockRecorder) GetTasksByListId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByListId", reflect.TypeOf((*MockIService)(nil).GetTasksByListId), arg0, arg1)
+}
+
 // GetTasksByTag mocks base method.
-func (m *MockIService) GetTasksByTag(arg0 string) ([]entity.Task, error) {
+func (m *MockIService) GetTasksByTag(arg0 []string, arg1 bool, arg2 entity.PageRequest) (entity.TaskPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTasksByTag", arg0)
-	ret0, _ := ret[0].([]entity.Task)
+	ret := m.ctrl.Call(m, "GetTasksByTag", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.TaskPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTasksByTag indicates an expected call of GetTasksByTag.
-func (mr *MockIServiceMockRecorder) GetTasksByTag(arg0 interface{}) *gomock.Call {
+func (mr *MockIServiceMockRecorder) GetTasksByTag(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByTag", reflect.TypeOf((*MockIService)(nil).GetTasksByTag), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTasksByTag", reflect.TypeOf((*MockIService)(nil).GetTasksByTag), arg0, arg1, arg2)
 }
 
-// SearchTasksByName mocks base method.
-func (m *MockIService) SearchTasksByName(arg0 string) ([]entity.Task, error) {
+// GetTrash mocks base method.
+func (m *MockIService) GetTrash(arg0 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrash", arg0)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockIServiceMockRecorder) GetTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockIService)(nil).GetTrash), arg0)
+}
+
+// ImportTasksCSV mocks base method.
+func (m *MockIService) ImportTasksCSV(arg0 io.Reader, arg1 entity.CSVImport) ([]entity.Task, []entity.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTasksCSV", arg0, arg1)
+	ret0, _ := ret[0].([]entity.Task)
+	ret1, _ := ret[1].([]entity.ImportRowError)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ImportTasksCSV indicates an expected call of ImportTasksCSV.
+func (mr *MockIServiceMockRecorder) ImportTasksCSV(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksCSV", reflect.TypeOf((*MockIService)(nil).ImportTasksCSV), arg0, arg1)
+}
+
+// ImportTasksICal mocks base method.
+func (m *MockIService) ImportTasksICal(arg0 io.Reader, arg1 entity.ICalImport) (entity.ImportResult, []entity.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTasksICal", arg0, arg1)
+	ret0, _ := ret[0].(entity.ImportResult)
+	ret1, _ := ret[1].([]entity.ImportRowError)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ImportTasksICal indicates an expected call of ImportTasksICal.
+func (mr *MockIServiceMockRecorder) ImportTasksICal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksICal", reflect.TypeOf((*MockIService)(nil).ImportTasksICal), arg0, arg1)
+}
+
+// ImportTasksMarkdown mocks base method.
+func (m *MockIService) ImportTasksMarkdown(arg0 io.Reader, arg1 entity.MarkdownImport) (entity.ImportResult, []entity.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTasksMarkdown", arg0, arg1)
+	ret0, _ := ret[0].(entity.ImportResult)
+	ret1, _ := ret[1].([]entity.ImportRowError)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ImportTasksMarkdown indicates an expected call of ImportTasksMarkdown.
+func (mr *MockIServiceMockRecorder) ImportTasksMarkdown(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksMarkdown", reflect.TypeOf((*MockIService)(nil).ImportTasksMarkdown), arg0, arg1)
+}
+
+// ImportTasksTodoTxt mocks base method.
+func (m *MockIService) ImportTasksTodoTxt(arg0 io.Reader, arg1 entity.TodoTxtImport) ([]entity.Task, []entity.ImportRowError, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchTasksByName", arg0)
+	ret := m.ctrl.Call(m, "ImportTasksTodoTxt", arg0, arg1)
 	ret0, _ := ret[0].([]entity.Task)
+	ret1, _ := ret[1].([]entity.ImportRowError)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ImportTasksTodoTxt indicates an expected call of ImportTasksTodoTxt.
+func (mr *MockIServiceMockRecorder) ImportTasksTodoTxt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTasksTodoTxt", reflect.TypeOf((*MockIService)(nil).ImportTasksTodoTxt), arg0, arg1)
+}
+
+// PatchTask mocks base method.
+func (m *MockIService) PatchTask(arg0 int, arg1 uint, arg2 string, arg3 []byte) (entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTask", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTask indicates an expected call of PatchTask.
+func (mr *MockIServiceMockRecorder) PatchTask(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTask", reflect.TypeOf((*MockIService)(nil).PatchTask), arg0, arg1, arg2, arg3)
+}
+
+// PurgeTask mocks base method.
+func (m *MockIService) PurgeTask(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTask", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PurgeTask indicates an expected call of PurgeTask.
+func (mr *MockIServiceMockRecorder) PurgeTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTask", reflect.TypeOf((*MockIService)(nil).PurgeTask), arg0)
+}
+
+// PurgeTrash mocks base method.
+func (m *MockIService) PurgeTrash(arg0 time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeTrash", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeTrash indicates an expected call of PurgeTrash.
+func (mr *MockIServiceMockRecorder) PurgeTrash(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeTrash", reflect.TypeOf((*MockIService)(nil).PurgeTrash), arg0)
+}
+
+// QueryTasks mocks base method.
+func (m *MockIService) QueryTasks(arg0 string, arg1 *time.Location, arg2 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTasks indicates an expected call of QueryTasks.
+func (mr *MockIServiceMockRecorder) QueryTasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTasks", reflect.TypeOf((*MockIService)(nil).QueryTasks), arg0, arg1, arg2)
+}
+
+// RemoveBlocker mocks base method.
+func (m *MockIService) RemoveBlocker(arg0, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBlocker", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBlocker indicates an expected call of RemoveBlocker.
+func (mr *MockIServiceMockRecorder) RemoveBlocker(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBlocker", reflect.TypeOf((*MockIService)(nil).RemoveBlocker), arg0, arg1)
+}
+
+// RestoreTask mocks base method.
+func (m *MockIService) RestoreTask(arg0 int) (entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTask", arg0)
+	ret0, _ := ret[0].(entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTask indicates an expected call of RestoreTask.
+func (mr *MockIServiceMockRecorder) RestoreTask(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTask", reflect.TypeOf((*MockIService)(nil).RestoreTask), arg0)
+}
+
+// SearchListTasksByName mocks base method.
+func (m *MockIService) SearchListTasksByName(arg0 int, arg1 string, arg2 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchListTasksByName", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.TaskPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchListTasksByName indicates an expected call of SearchListTasksByName.
+func (mr *MockIServiceMockRecorder) SearchListTasksByName(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchListTasksByName", reflect.TypeOf((*MockIService)(nil).SearchListTasksByName), arg0, arg1, arg2)
+}
+
+// SearchTasksByName mocks base method.
+func (m *MockIService) SearchTasksByName(arg0 string, arg1 entity.PageRequest) (entity.TaskPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTasksByName", arg0, arg1)
+	ret0, _ := ret[0].(entity.TaskPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchTasksByName indicates an expected call of SearchTasksByName.
-func (mr *MockIServiceMockRecorder) SearchTasksByName(arg0 interface{}) *gomock.Call {
+func (mr *MockIServiceMockRecorder) SearchTasksByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasksByName", reflect.TypeOf((*MockIService)(nil).SearchTasksByName), arg0, arg1)
+}
+
+// TransitionTask mocks base method.
+func (m *MockIService) TransitionTask(arg0 int, arg1 string) (entity.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionTask", arg0, arg1)
+	ret0, _ := ret[0].(entity.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionTask indicates an expected call of TransitionTask.
+func (mr *MockIServiceMockRecorder) TransitionTask(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTasksByName", reflect.TypeOf((*MockIService)(nil).SearchTasksByName), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionTask", reflect.TypeOf((*MockIService)(nil).TransitionTask), arg0, arg1)
 }
 
 // UpdateTask mocks base method.
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/controllers (interfaces: IViewController)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIViewController is a mock of IViewController interface.
+type MockIViewController struct {
+	ctrl     *gomock.Controller
+	recorder *MockIViewControllerMockRecorder
+}
+
+// MockIViewControllerMockRecorder is the mock recorder for MockIViewController.
+type MockIViewControllerMockRecorder struct {
+	mock *MockIViewController
+}
+
+// NewMockIViewController creates a new mock instance.
+func NewMockIViewController(ctrl *gomock.Controller) *MockIViewController {
+	mock := &MockIViewController{ctrl: ctrl}
+	mock.recorder = &MockIViewControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIViewController) EXPECT() *MockIViewControllerMockRecorder {
+	return m.recorder
+}
+
+// CreateView mocks base method.
+func (m *MockIViewController) CreateView(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CreateView", arg0)
+}
+
+// CreateView indicates an expected call of CreateView.
+func (mr *MockIViewControllerMockRecorder) CreateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateView", reflect.TypeOf((*MockIViewController)(nil).CreateView), arg0)
+}
+
+// DeleteView mocks base method.
+func (m *MockIViewController) DeleteView(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteView", arg0)
+}
+
+// DeleteView indicates an expected call of DeleteView.
+func (mr *MockIViewControllerMockRecorder) DeleteView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteView", reflect.TypeOf((*MockIViewController)(nil).DeleteView), arg0)
+}
+
+// GetViewById mocks base method.
+func (m *MockIViewController) GetViewById(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetViewById", arg0)
+}
+
+// GetViewById indicates an expected call of GetViewById.
+func (mr *MockIViewControllerMockRecorder) GetViewById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewById", reflect.TypeOf((*MockIViewController)(nil).GetViewById), arg0)
+}
+
+// GetViewTasks mocks base method.
+func (m *MockIViewController) GetViewTasks(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetViewTasks", arg0)
+}
+
+// GetViewTasks indicates an expected call of GetViewTasks.
+func (mr *MockIViewControllerMockRecorder) GetViewTasks(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewTasks", reflect.TypeOf((*MockIViewController)(nil).GetViewTasks), arg0)
+}
+
+// GetViews mocks base method.
+func (m *MockIViewController) GetViews(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetViews", arg0)
+}
+
+// GetViews indicates an expected call of GetViews.
+func (mr *MockIViewControllerMockRecorder) GetViews(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViews", reflect.TypeOf((*MockIViewController)(nil).GetViews), arg0)
+}
+
+// UpdateView mocks base method.
+func (m *MockIViewController) UpdateView(arg0 *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateView", arg0)
+}
+
+// UpdateView indicates an expected call of UpdateView.
+func (mr *MockIViewControllerMockRecorder) UpdateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateView", reflect.TypeOf((*MockIViewController)(nil).UpdateView), arg0)
+}
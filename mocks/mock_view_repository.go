@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/repositories (interfaces: IViewRepo)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIViewRepo is a mock of IViewRepo interface.
+type MockIViewRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockIViewRepoMockRecorder
+}
+
+// MockIViewRepoMockRecorder is the mock recorder for MockIViewRepo.
+type MockIViewRepoMockRecorder struct {
+	mock *MockIViewRepo
+}
+
+// NewMockIViewRepo creates a new mock instance.
+func NewMockIViewRepo(ctrl *gomock.Controller) *MockIViewRepo {
+	mock := &MockIViewRepo{ctrl: ctrl}
+	mock.recorder = &MockIViewRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIViewRepo) EXPECT() *MockIViewRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateView mocks base method.
+func (m *MockIViewRepo) CreateView(arg0 *entity.View) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateView indicates an expected call of CreateView.
+func (mr *MockIViewRepoMockRecorder) CreateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateView", reflect.TypeOf((*MockIViewRepo)(nil).CreateView), arg0)
+}
+
+// DeleteView mocks base method.
+func (m *MockIViewRepo) DeleteView(arg0 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteView indicates an expected call of DeleteView.
+func (mr *MockIViewRepoMockRecorder) DeleteView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteView", reflect.TypeOf((*MockIViewRepo)(nil).DeleteView), arg0)
+}
+
+// GetAllViews mocks base method.
+func (m *MockIViewRepo) GetAllViews() ([]entity.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllViews")
+	ret0, _ := ret[0].([]entity.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllViews indicates an expected call of GetAllViews.
+func (mr *MockIViewRepoMockRecorder) GetAllViews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllViews", reflect.TypeOf((*MockIViewRepo)(nil).GetAllViews))
+}
+
+// GetViewById mocks base method.
+func (m *MockIViewRepo) GetViewById(arg0 int) (entity.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewById", arg0)
+	ret0, _ := ret[0].(entity.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewById indicates an expected call of GetViewById.
+func (mr *MockIViewRepoMockRecorder) GetViewById(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewById", reflect.TypeOf((*MockIViewRepo)(nil).GetViewById), arg0)
+}
+
+// GetViewByName mocks base method.
+func (m *MockIViewRepo) GetViewByName(arg0 string) (entity.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewByName", arg0)
+	ret0, _ := ret[0].(entity.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewByName indicates an expected call of GetViewByName.
+func (mr *MockIViewRepoMockRecorder) GetViewByName(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewByName", reflect.TypeOf((*MockIViewRepo)(nil).GetViewByName), arg0)
+}
+
+// UpdateView mocks base method.
+func (m *MockIViewRepo) UpdateView(arg0 *entity.View) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateView indicates an expected call of UpdateView.
+func (mr *MockIViewRepoMockRecorder) UpdateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateView", reflect.TypeOf((*MockIViewRepo)(nil).UpdateView), arg0)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: todo-lists/services (interfaces: IViewService)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+	entity "todo-lists/entity"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIViewService is a mock of IViewService interface.
+type MockIViewService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIViewServiceMockRecorder
+}
+
+// MockIViewServiceMockRecorder is the mock recorder for MockIViewService.
+type MockIViewServiceMockRecorder struct {
+	mock *MockIViewService
+}
+
+// NewMockIViewService creates a new mock instance.
+func NewMockIViewService(ctrl *gomock.Controller) *MockIViewService {
+	mock := &MockIViewService{ctrl: ctrl}
+	mock.recorder = &MockIViewServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIViewService) EXPECT() *MockIViewServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateView mocks base method.
+func (m *MockIViewService) CreateView(arg0 *entity.View) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateView indicates an expected call of CreateView.
+func (mr *MockIViewServiceMockRecorder) CreateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateView", reflect.TypeOf((*MockIViewService)(nil).CreateView), arg0)
+}
+
+// DeleteView mocks base method.
+func (m *MockIViewService) DeleteView(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteView indicates an expected call of DeleteView.
+func (mr *MockIViewServiceMockRecorder) DeleteView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteView", reflect.TypeOf((*MockIViewService)(nil).DeleteView), arg0)
+}
+
+// GetAllViews mocks base method.
+func (m *MockIViewService) GetAllViews() ([]entity.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllViews")
+	ret0, _ := ret[0].([]entity.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllViews indicates an expected call of GetAllViews.
+func (mr *MockIViewServiceMockRecorder) GetAllViews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllViews", reflect.TypeOf((*MockIViewService)(nil).GetAllViews))
+}
+
+// GetView mocks base method.
+func (m *MockIViewService) GetView(arg0 string) (entity.View, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetView", arg0)
+	ret0, _ := ret[0].(entity.View)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetView indicates an expected call of GetView.
+func (mr *MockIViewServiceMockRecorder) GetView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetView", reflect.TypeOf((*MockIViewService)(nil).GetView), arg0)
+}
+
+// GetViewTasks mocks base method.
+func (m *MockIViewService) GetViewTasks(arg0 string, arg1 *time.Location, arg2 entity.PageRequest) (entity.ViewTasks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewTasks", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.ViewTasks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewTasks indicates an expected call of GetViewTasks.
+func (mr *MockIViewServiceMockRecorder) GetViewTasks(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewTasks", reflect.TypeOf((*MockIViewService)(nil).GetViewTasks), arg0, arg1, arg2)
+}
+
+// UpdateView mocks base method.
+func (m *MockIViewService) UpdateView(arg0 *entity.View) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateView", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateView indicates an expected call of UpdateView.
+func (mr *MockIViewServiceMockRecorder) UpdateView(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateView", reflect.TypeOf((*MockIViewService)(nil).UpdateView), arg0)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the response to a request sent with an Idempotency-Key header, so retries of the
+// request can replay it. Status stays 0 while the first request is being handled.
+type IdempotencyKey struct {
+	Key         string    `gorm:"column:idempotency_key;primaryKey;size:255" json:"key"`
+	RequestHash string    `gorm:"size:64;not null" json:"request_hash"`
+	Status      int       `gorm:"not null;default:0" json:"status"`
+	Headers     string    `gorm:"not null" json:"headers"`
+	Body        []byte    `json:"body"`
+	ExpiresAt   time.Time `gorm:"not null;index" json:"expires_at"`
+}
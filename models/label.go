@@ -0,0 +1,14 @@
+package models
+
+// Label is a user defined label that tasks can carry
+type Label struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"size:64;not null;uniqueIndex:uni_labels_name" json:"name"`
+	Color string `gorm:"size:7;not null;default:''" json:"color"`
+}
+
+// TaskLabel attaches a label to a task
+type TaskLabel struct {
+	TaskID  uint `gorm:"primaryKey;autoIncrement:false" json:"task_id"`
+	LabelID uint `gorm:"primaryKey;autoIncrement:false;index" json:"label_id"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// List represents a named todo list that groups tasks
+type List struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"not null" json:"name"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// ListFeed stores the hashed token of the calendar feed of a list. A list has at most one feed.
+type ListFeed struct {
+	ListID    uint   `gorm:"primaryKey;autoIncrement:false" json:"list_id"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex:uni_list_feeds_token_hash" json:"token_hash"`
+}
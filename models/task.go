@@ -2,12 +2,24 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Task represents the task model
 type Task struct {
-	ID       uint      `gorm:"primaryKey" json:"id"`
-	Name     string    `gorm:"not null" json:"name"`
-	Deadline time.Time `gorm:"not null" json:"deadline"`
-	Tag      string    `gorm:"type:enum('less', 'medium', 'high');not null" json:"tag"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	ListID      uint           `gorm:"index" json:"list_id"`
+	ParentID    uint           `gorm:"not null;default:0;index" json:"parent_id"`
+	Name        string         `gorm:"not null" json:"name"`
+	Deadline    *time.Time     `json:"deadline"`
+	Priority    string         `gorm:"size:16;not null;default:'medium';check:chk_tasks_priority,priority IN ('less', 'medium', 'high')" json:"priority"`
+	Status      string         `gorm:"size:16;not null;default:'todo';index;check:chk_tasks_status,status IN ('todo', 'in_progress', 'blocked', 'done', 'cancelled')" json:"status"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	Version     uint           `gorm:"not null;default:1" json:"version"`
+	Recurrence  string         `gorm:"size:255;not null;default:''" json:"recurrence"`
+	UID         string         `gorm:"column:uid;size:255;not null;default:'';index" json:"uid"`
+	CreatedAt   time.Time      `gorm:"not null" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"not null" json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
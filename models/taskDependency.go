@@ -0,0 +1,7 @@
+package models
+
+// TaskDependency records that a task cannot start until its blocker is done
+type TaskDependency struct {
+	TaskID    uint `gorm:"primaryKey;autoIncrement:false" json:"task_id"`
+	BlockerID uint `gorm:"primaryKey;autoIncrement:false;index" json:"blocker_id"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// View is a saved task query
+type View struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"size:64;not null;uniqueIndex:uni_views_name" json:"name"`
+	Query string `gorm:"size:2000;not null" json:"query"`
+}
@@ -0,0 +1,144 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenOpen
+	tokenClose
+	tokenTerm
+)
+
+// token is a keyword, a parenthesis or a whole term at a 1-based position of the query
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+	term Term
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits a query into tokens. Terms are validated as they are read, so their errors point at the field,
+// operator or value at fault. Relative dates are resolved against now.
+func lex(s string, now time.Time) ([]token, error) {
+	runes := []rune(s)
+	var tokens []token
+	i := 0
+	for {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			i++
+		}
+		if i == len(runes) {
+			return append(tokens, token{kind: tokenEnd, pos: i + 1}), nil
+		}
+
+		start := i
+		switch runes[i] {
+		case '(':
+			tokens = append(tokens, token{kind: tokenOpen, pos: start + 1, text: "("})
+			i++
+			continue
+		case ')':
+			tokens = append(tokens, token{kind: tokenClose, pos: start + 1, text: ")"})
+			i++
+			continue
+		}
+
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		word := string(runes[start:i])
+		if word == "" {
+			return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("unexpected %q", runes[i])}
+		}
+
+		if i == len(runes) || !isOperatorRune(runes[i]) {
+			switch strings.ToUpper(word) {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, pos: start + 1, text: word})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, pos: start + 1, text: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, pos: start + 1, text: word})
+			default:
+				return nil, &Error{Pos: start + 1, Msg: fmt.Sprintf("expected an operator after %q, such as %s:value", word, word)}
+			}
+			continue
+		}
+
+		opStart := i
+		op := string(runes[i])
+		i++
+		if (op == Less || op == Greater) && i < len(runes) && runes[i] == '=' {
+			op += "="
+			i++
+		}
+
+		valueStart := i
+		var value string
+		if i < len(runes) && runes[i] == '"' {
+			var err error
+			value, i, err = readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			value = string(runes[valueStart:i])
+		}
+
+		term, err := newTerm(word, start+1, op, opStart+1, value, valueStart+1, now)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token{kind: tokenTerm, pos: start + 1, text: string(runes[start:i]), term: term})
+	}
+}
+
+// readQuoted reads the double quoted string starting at runes[i] and returns its value and the index after it
+func readQuoted(runes []rune, i int) (string, int, error) {
+	open := i
+	var value strings.Builder
+	for i++; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+				i++
+				value.WriteRune(runes[i])
+				continue
+			}
+			return "", 0, &Error{Pos: i + 1, Msg: `only \" and \\ can be escaped`}
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+	return "", 0, &Error{Pos: open + 1, Msg: "unterminated quoted value"}
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isOperatorRune(r rune) bool {
+	return r == ':' || r == '~' || r == '<' || r == '>'
+}
@@ -0,0 +1,345 @@
+// Package query implements the filter expression language of GET /tasks?q=, for example
+//
+//	label:work AND deadline<2024-12-01 AND name~"report" AND NOT status:done
+//
+// A query is a boolean combination of terms joined with AND, OR and NOT and grouped with parentheses. Terms
+// next to each other without an operator are joined with AND. AND binds tighter than OR.
+//
+// Each term is a field, an operator and a value. The value is a bare word or a double quoted string in which
+// \" and \\ are escapes:
+//
+//	name:<text>       name equals text, ignoring case
+//	name~<text>       name contains text, ignoring case
+//	label:<name>      the task carries the label, tag:<name> is an alias
+//	priority:<p>      less, medium or high
+//	status:<s>        todo, in_progress, blocked, done or cancelled
+//	list:<id>         the task belongs to the list, 0 for tasks without a list
+//	parent:<id>       the task is a subtask of the task, 0 for top level tasks
+//	deadline:<date>   due on that day, or at that instant for a timestamp
+//	deadline<date     also <=, > and >=
+//	deadline:none     the task has no deadline
+//
+// Dates are YYYY-MM-DD or RFC 3339 timestamps. Dates can also be relative: today, tomorrow, yesterday,
+// today+3d or today-2w count days from the current one, and now is the current instant. A day starts at
+// midnight in the time zone of the clock the query is parsed with, UTC for Parse.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"todo-lists/entity"
+)
+
+// None is the deadline value that matches the tasks without a deadline
+const None = "none"
+
+// MaxLength bounds the length of a query, and with it the nesting depth the parser recurses into
+const MaxLength = 2000
+
+// Operators a term can compare with
+const (
+	Equal        = ":"
+	Contains     = "~"
+	Less         = "<"
+	LessEqual    = "<="
+	Greater      = ">"
+	GreaterEqual = ">="
+)
+
+// Fields a term can filter on
+const (
+	FieldName     = "name"
+	FieldLabel    = "label"
+	FieldPriority = "priority"
+	FieldStatus   = "status"
+	FieldList     = "list"
+	FieldParent   = "parent"
+	FieldDeadline = "deadline"
+)
+
+// ErrInvalidQuery is returned, wrapped in an *Error, for a query that does not parse or validate
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Error is a syntax or validation error at a position of the query
+type Error struct {
+	// Pos is the 1-based character position the error was found at
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrInvalidQuery, e.Pos, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalidQuery
+}
+
+// Expr is a node of a parsed query: an And, Or, Not or Term
+type Expr interface {
+	expr()
+}
+
+// And matches the tasks both sides match
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches the tasks either side matches
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches the tasks its expression does not match
+type Not struct {
+	Expr Expr
+}
+
+// Term compares one field of a task with a value. Value holds the text of the value; ID is set for the list
+// and parent fields and Time for the deadline field. A deadline compared with a date covers the whole day,
+// from Time up to but excluding Until: Equal matches the day, LessEqual and Greater compare with Until, and
+// Less and GreaterEqual with Time. A deadline term with the value None has neither.
+type Term struct {
+	Field string
+	Op    string
+	Value string
+	ID    uint
+	Time  time.Time
+	Until time.Time
+}
+
+func (And) expr()  {}
+func (Or) expr()   {}
+func (Not) expr()  {}
+func (Term) expr() {}
+
+// operators lists the operators every field accepts
+var operators = map[string][]string{
+	FieldName:     {Equal, Contains},
+	FieldLabel:    {Equal},
+	FieldPriority: {Equal},
+	FieldStatus:   {Equal},
+	FieldList:     {Equal},
+	FieldParent:   {Equal},
+	FieldDeadline: {Equal, Less, LessEqual, Greater, GreaterEqual},
+}
+
+// aliases maps alternative field names to their field
+var aliases = map[string]string{"tag": FieldLabel}
+
+// Parse reads a query into an expression tree, with dates in UTC
+func Parse(s string) (Expr, error) {
+	return ParseAt(s, time.Now().UTC())
+}
+
+// ParseAt reads a query into an expression tree. Relative dates count from now, and dates start at midnight
+// in the location of now.
+func ParseAt(s string, now time.Time) (Expr, error) {
+	if len([]rune(s)) > MaxLength {
+		return nil, &Error{Pos: MaxLength + 1, Msg: fmt.Sprintf("query is longer than %d characters", MaxLength)}
+	}
+	tokens, err := lex(s, now)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEnd {
+		return nil, &Error{Pos: p.peek().pos, Msg: "query is empty"}
+	}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEnd {
+		return nil, &Error{Pos: next.pos, Msg: fmt.Sprintf("unexpected %s", next)}
+	}
+	return e, nil
+}
+
+// Quote returns value as a quoted query string, so a label name or keyword can be put into a query
+func Quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEnd {
+		p.next++
+	}
+	return t
+}
+
+// or parses and-expressions joined with OR
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// and parses unary expressions joined with AND or just written next to each other
+func (p *parser) and() (Expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.advance()
+		case tokenNot, tokenOpen, tokenTerm:
+		default:
+			return left, nil
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+}
+
+// unary parses a term or a parenthesized expression, optionally negated
+func (p *parser) unary() (Expr, error) {
+	t := p.advance()
+	switch t.kind {
+	case tokenNot:
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	case tokenOpen:
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokenClose {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected ) to close ( at position %d, found %s", t.pos, closing)}
+		}
+		return e, nil
+	case tokenTerm:
+		return t.term, nil
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a term, NOT or (, found %s", t)}
+}
+
+// newTerm validates a term and converts its value to the type of the field
+func newTerm(field string, fieldPos int, op string, opPos int, value string, valuePos int, now time.Time) (Term, error) {
+	name := strings.ToLower(field)
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	allowed, ok := operators[name]
+	if !ok {
+		return Term{}, &Error{Pos: fieldPos, Msg: fmt.Sprintf("unknown field %q", field)}
+	}
+	if !contains(allowed, op) {
+		return Term{}, &Error{Pos: opPos, Msg: fmt.Sprintf("field %s does not support %s", name, op)}
+	}
+	if value == "" {
+		return Term{}, &Error{Pos: valuePos, Msg: fmt.Sprintf("missing value for %s", name)}
+	}
+
+	term := Term{Field: name, Op: op, Value: value}
+	switch name {
+	case FieldPriority:
+		if !entity.ValidPriority(value) {
+			return Term{}, &Error{Pos: valuePos, Msg: "priority must be one of less, medium, high"}
+		}
+	case FieldStatus:
+		if !entity.ValidStatus(value) {
+			return Term{}, &Error{Pos: valuePos, Msg: fmt.Sprintf("unknown status %q", value)}
+		}
+	case FieldList, FieldParent:
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return Term{}, &Error{Pos: valuePos, Msg: fmt.Sprintf("%s must be a task or list ID", name)}
+		}
+		term.ID = uint(id)
+	case FieldDeadline:
+		if strings.EqualFold(value, None) {
+			if op != Equal {
+				return Term{}, &Error{Pos: opPos, Msg: "deadline none only supports :"}
+			}
+			term.Value = None
+			break
+		}
+		if day, ok := dateValue(value, now); ok {
+			term.Time = day
+			term.Until = day.AddDate(0, 0, 1)
+		} else if strings.EqualFold(value, "now") {
+			term.Time = now
+		} else if at, err := time.Parse(time.RFC3339, value); err == nil {
+			term.Time = at
+		} else {
+			return Term{}, &Error{Pos: valuePos, Msg: "deadline must be a YYYY-MM-DD date, an RFC 3339 timestamp, a relative date such as today+7d, or none"}
+		}
+	}
+	return term, nil
+}
+
+// relativeDay matches the relative dates that count days or weeks from today
+var relativeDay = regexp.MustCompile(`^(?i)today([+-])(\d{1,4})([dw])$`)
+
+// dateValue reads an absolute or relative date as midnight in the location of now
+func dateValue(value string, now time.Time) (time.Time, bool) {
+	if day, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return day, true
+	}
+
+	year, month, date := now.Date()
+	today := time.Date(year, month, date, 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(value) {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	match := relativeDay.FindStringSubmatch(value)
+	if match == nil {
+		return time.Time{}, false
+	}
+	days, _ := strconv.Atoi(match[2])
+	if strings.EqualFold(match[3], "w") {
+		days *= 7
+	}
+	if match[1] == "-" {
+		days = -days
+	}
+	return today.AddDate(0, 0, days), true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
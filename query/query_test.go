@@ -0,0 +1,140 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func label(name string) Term {
+	return Term{Field: FieldLabel, Op: Equal, Value: name}
+}
+
+func TestParse(t *testing.T) {
+	e, err := Parse(`tag:high AND deadline<2024-12-01 AND name~"weekly \"report\"" AND NOT status:done`)
+	assert.NoError(t, err)
+	assert.Equal(t, And{
+		Left: And{
+			Left: And{
+				Left:  label("high"),
+				Right: Term{Field: FieldDeadline, Op: Less, Value: "2024-12-01", Time: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC)},
+			},
+			Right: Term{Field: FieldName, Op: Contains, Value: `weekly "report"`},
+		},
+		Right: Not{Expr: Term{Field: FieldStatus, Op: Equal, Value: "done"}},
+	}, e)
+
+	// AND binds tighter than OR, terms next to each other are joined with AND, keywords ignore case
+	e, err = Parse("label:a label:b or label:c")
+	assert.NoError(t, err)
+	assert.Equal(t, Or{Left: And{Left: label("a"), Right: label("b")}, Right: label("c")}, e)
+
+	e, err = Parse("label:a AND (label:b OR NOT (label:c))")
+	assert.NoError(t, err)
+	assert.Equal(t, And{Left: label("a"), Right: Or{Left: label("b"), Right: Not{Expr: label("c")}}}, e)
+
+	// Typed values
+	e, err = Parse("list:3")
+	assert.NoError(t, err)
+	assert.Equal(t, Term{Field: FieldList, Op: Equal, Value: "3", ID: 3}, e)
+	e, err = Parse("deadline:2026-11-02")
+	assert.NoError(t, err)
+	day := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, Term{Field: FieldDeadline, Op: Equal, Value: "2026-11-02", Time: day, Until: day.AddDate(0, 0, 1)}, e)
+	e, err = Parse("deadline<=2026-11-02")
+	assert.NoError(t, err)
+	assert.Equal(t, Term{Field: FieldDeadline, Op: LessEqual, Value: "2026-11-02", Time: day, Until: day.AddDate(0, 0, 1)}, e)
+	e, err = Parse("Deadline>=2026-11-02T09:30:00+01:00")
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2026, 11, 2, 8, 30, 0, 0, time.UTC).Equal(e.(Term).Time))
+	assert.True(t, e.(Term).Until.IsZero())
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"", 1, "query is empty"},
+		{"  ", 3, "query is empty"},
+		{"color:red", 1, `unknown field "color"`},
+		{"label<work", 6, "field label does not support <"},
+		{"name:", 6, "missing value for name"},
+		{"status:done AND priority:urgent", 26, "priority must be one of less, medium, high"},
+		{"status:paused", 8, `unknown status "paused"`},
+		{"list:abc", 6, "list must be a task or list ID"},
+		{"deadline<someday", 10, "deadline must be a YYYY-MM-DD date, an RFC 3339 timestamp, a relative date such as today+7d, or none"},
+		{"deadline<today+1m", 10, "deadline must be a YYYY-MM-DD date, an RFC 3339 timestamp, a relative date such as today+7d, or none"},
+		{"deadline>=none", 9, "deadline none only supports :"},
+		{`name~"report`, 6, "unterminated quoted value"},
+		{`name~"a\nb"`, 8, `only \" and \\ can be escaped`},
+		{"report", 1, `expected an operator after "report", such as report:value`},
+		{"label:a AND", 12, "expected a term, NOT or (, found end of query"},
+		{"label:a OR OR label:b", 12, `expected a term, NOT or (, found "OR"`},
+		{"(label:a", 9, "expected ) to close ( at position 1, found end of query"},
+		{"label:a)", 8, `unexpected ")"`},
+		{"label:a & label:b", 9, `unexpected '&'`},
+	} {
+		_, err := Parse(tc.query)
+		var queryErr *Error
+		if assert.ErrorAs(t, err, &queryErr, tc.query) {
+			assert.Equal(t, tc.pos, queryErr.Pos, tc.query)
+			assert.Equal(t, tc.msg, queryErr.Msg, tc.query)
+		}
+		assert.True(t, errors.Is(err, ErrInvalidQuery), tc.query)
+	}
+
+	_, err := Parse("status:done")
+	assert.NoError(t, err)
+	_, err = Parse(string(make([]byte, MaxLength+1)))
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+func TestParseAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+	// Half an hour before midnight in Berlin, on the night the clocks go forward
+	now := time.Date(2026, 3, 28, 23, 30, 0, 0, berlin)
+	saturday := time.Date(2026, 3, 28, 0, 0, 0, 0, berlin)
+
+	for _, tc := range []struct {
+		value string
+		time  time.Time
+	}{
+		{"today", saturday},
+		{"Tomorrow", saturday.AddDate(0, 0, 1)},
+		{"yesterday", saturday.AddDate(0, 0, -1)},
+		{"today+7d", saturday.AddDate(0, 0, 7)},
+		{"today-2w", saturday.AddDate(0, 0, -14)},
+		{"2026-03-29", time.Date(2026, 3, 29, 0, 0, 0, 0, berlin)},
+	} {
+		e, err := ParseAt("deadline:"+tc.value, now)
+		if assert.NoError(t, err, tc.value) {
+			term := e.(Term)
+			assert.True(t, tc.time.Equal(term.Time), tc.value)
+			assert.True(t, tc.time.AddDate(0, 0, 1).Equal(term.Until), tc.value)
+		}
+	}
+
+	// The day the clocks change is 23 hours long
+	e, err := ParseAt("deadline:tomorrow", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 23*time.Hour, e.(Term).Until.Sub(e.(Term).Time))
+
+	e, err = ParseAt("deadline<now", now)
+	assert.NoError(t, err)
+	assert.Equal(t, Term{Field: FieldDeadline, Op: Less, Value: "now", Time: now}, e)
+
+	e, err = ParseAt("NOT deadline:NONE", now)
+	assert.NoError(t, err)
+	assert.Equal(t, Not{Expr: Term{Field: FieldDeadline, Op: Equal, Value: None}}, e)
+}
+
+func TestQuote(t *testing.T) {
+	e, err := Parse("label:" + Quote(`say "hi" \ bye`))
+	assert.NoError(t, err)
+	assert.Equal(t, label(`say "hi" \ bye`), e)
+}
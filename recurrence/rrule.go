@@ -0,0 +1,342 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules tasks can repeat by: FREQ DAILY,
+// WEEKLY or MONTHLY with INTERVAL, BYDAY, and either COUNT or UNTIL.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+// maxPeriods bounds how many periods are searched for the next occurrence of a rule
+const maxPeriods = 10000
+
+// ErrInvalidRule is returned for a rule outside the supported subset
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// weekdays maps the RFC 5545 day codes to weekdays
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Day is a BYDAY entry. Ordinal is only used by monthly rules: 1 is the first such weekday of the month, -1
+// the last, and 0 every one of them.
+type Day struct {
+	Weekday time.Weekday
+	Ordinal int
+}
+
+// Rule is a parsed recurrence rule. The first occurrence of a series is its start, the deadline of the task.
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []Day
+	Count    int
+	Until    *time.Time
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10". An "RRULE:" prefix is accepted.
+func Parse(s string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !ok || value == "" || seen[name] {
+			return Rule{}, fmt.Errorf("%w: %q", ErrInvalidRule, part)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			if value != Daily && value != Weekly && value != Monthly {
+				return Rule{}, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, value)
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			rule.Interval, err = positive(value)
+		case "COUNT":
+			rule.Count, err = positive(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "WKST":
+			if value != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %s", name)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	if rule.Freq == "" {
+		return Rule{}, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return Rule{}, fmt.Errorf("%w: COUNT and UNTIL cannot be combined", ErrInvalidRule)
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != Monthly {
+			return Rule{}, fmt.Errorf("%w: BYDAY ordinals need FREQ=MONTHLY", ErrInvalidRule)
+		}
+	}
+	return rule, nil
+}
+
+func positive(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s is not a positive number", value)
+	}
+	return n, nil
+}
+
+// parseUntil accepts the UTC date-time and date forms of UNTIL
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+	until, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("UNTIL %s is not a date", value)
+	}
+	// A date includes the whole day
+	return until.Add(24*time.Hour - time.Second), nil
+}
+
+func parseByDay(value string) ([]Day, error) {
+	var days []Day
+	for _, entry := range strings.Split(value, ",") {
+		if len(entry) < 2 {
+			return nil, fmt.Errorf("BYDAY %s is not a day", entry)
+		}
+		weekday, ok := weekdays[entry[len(entry)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("BYDAY %s is not a day", entry)
+		}
+		day := Day{Weekday: weekday}
+		if prefix := entry[:len(entry)-2]; prefix != "" {
+			ordinal, err := strconv.Atoi(prefix)
+			if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+				return nil, fmt.Errorf("BYDAY %s has an invalid ordinal", entry)
+			}
+			day.Ordinal = ordinal
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// String formats the rule in its canonical RRULE form, without the "RRULE:" prefix
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := strings.ToUpper(day.Weekday.String()[:2])
+			if day.Ordinal != 0 {
+				code = strconv.Itoa(day.Ordinal) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns the occurrences of a series starting at start that fall within [from, to], in order.
+// The start itself is always the first occurrence and counts towards COUNT.
+func (r Rule) Occurrences(start, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.each(start, func(occurrence time.Time) bool {
+		if occurrence.After(to) {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// Next returns the occurrence that follows start, the current occurrence of a series, and the rule the
+// rest of the series continues with: COUNT is reduced by the occurrence that was used up. It reports false
+// when the series ends with start.
+func (r Rule) Next(start time.Time) (time.Time, Rule, bool) {
+	var next time.Time
+	found := false
+	r.each(start, func(occurrence time.Time) bool {
+		if occurrence.After(start) {
+			next, found = occurrence, true
+			return false
+		}
+		return true
+	})
+	if !found {
+		return time.Time{}, Rule{}, false
+	}
+
+	rest := r
+	if rest.Count > 0 {
+		rest.Count--
+	}
+	return next, rest, true
+}
+
+// each calls yield with the occurrences of a series starting at start in order, until yield returns false
+// or the series ends
+func (r Rule) each(start time.Time, yield func(time.Time) bool) {
+	emitted := 0
+	emit := func(occurrence time.Time) bool {
+		if r.Until != nil && occurrence.After(*r.Until) {
+			return false
+		}
+		if r.Count > 0 && emitted >= r.Count {
+			return false
+		}
+		emitted++
+		return yield(occurrence)
+	}
+
+	if !emit(start) {
+		return
+	}
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.candidates(start, period) {
+			if !candidate.After(start) {
+				continue
+			}
+			if !emit(candidate) {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the dates the rule selects in the given period after the start of a series, sorted,
+// at the time of day of the start
+func (r Rule) candidates(start time.Time, period int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	var dates []time.Time
+	switch r.Freq {
+	case Daily:
+		day := at(start.Year(), start.Month(), start.Day()+period*interval)
+		if r.matchesWeekday(day.Weekday()) {
+			dates = append(dates, day)
+		}
+	case Weekly:
+		// Weeks start on Monday
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset+period*7*interval)
+		for i := 0; i < 7; i++ {
+			day := monday.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() == start.Weekday() || len(r.ByDay) > 0 && r.matchesWeekday(day.Weekday()) {
+				dates = append(dates, day)
+			}
+		}
+	case Monthly:
+		first := at(start.Year(), start.Month()+time.Month(period*interval), 1)
+		if len(r.ByDay) == 0 {
+			// Months without the day of the start are skipped
+			day := at(first.Year(), first.Month(), start.Day())
+			if day.Month() == first.Month() {
+				dates = append(dates, day)
+			}
+			break
+		}
+		dates = r.monthDays(first)
+	}
+	return dates
+}
+
+// monthDays returns the BYDAY dates within the month that starts at first
+func (r Rule) monthDays(first time.Time) []time.Time {
+	var month []time.Time
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		month = append(month, day)
+	}
+
+	selected := map[int]bool{}
+	for _, byDay := range r.ByDay {
+		var matches []int
+		for i, day := range month {
+			if day.Weekday() == byDay.Weekday {
+				matches = append(matches, i)
+			}
+		}
+		switch {
+		case byDay.Ordinal == 0:
+			for _, i := range matches {
+				selected[i] = true
+			}
+		case byDay.Ordinal > 0 && byDay.Ordinal <= len(matches):
+			selected[matches[byDay.Ordinal-1]] = true
+		case byDay.Ordinal < 0 && -byDay.Ordinal <= len(matches):
+			selected[matches[len(matches)+byDay.Ordinal]] = true
+		}
+	}
+
+	indexes := make([]int, 0, len(selected))
+	for i := range selected {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	dates := make([]time.Time, 0, len(indexes))
+	for _, i := range indexes {
+		dates = append(dates, month[i])
+	}
+	return dates
+}
+
+func (r Rule) matchesWeekday(weekday time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, day := range r.ByDay {
+		if day.Weekday == weekday {
+			return true
+		}
+	}
+	return false
+}
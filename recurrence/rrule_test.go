@@ -0,0 +1,112 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=weekly;INTERVAL=2;BYDAY=MO,WE;COUNT=10")
+	assert.NoError(t, err)
+	assert.Equal(t, Weekly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []Day{{Weekday: time.Monday}, {Weekday: time.Wednesday}}, rule.ByDay)
+	assert.Equal(t, 10, rule.Count)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10", rule.String())
+
+	rule, err = Parse("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231")
+	assert.NoError(t, err)
+	assert.Equal(t, []Day{{Weekday: time.Friday, Ordinal: -1}}, rule.ByDay)
+	assert.Equal(t, time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), *rule.Until)
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231T235959Z", rule.String())
+
+	for _, invalid := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261231",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ",
+	} {
+		_, err := Parse(invalid)
+		assert.ErrorIs(t, err, ErrInvalidRule, invalid)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	// Monday 2 November 2026
+	start := date(2026, 11, 2)
+
+	tests := []struct {
+		rule string
+		to   time.Time
+		want []time.Time
+	}{
+		{"FREQ=DAILY;INTERVAL=2", date(2026, 11, 8),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 4), date(2026, 11, 6), date(2026, 11, 8)}},
+		{"FREQ=DAILY;BYDAY=SA,SU", date(2026, 11, 15),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 7), date(2026, 11, 8), date(2026, 11, 14), date(2026, 11, 15)}},
+		{"FREQ=WEEKLY", date(2026, 11, 20),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 9), date(2026, 11, 16)}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", date(2026, 11, 30),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 5), date(2026, 11, 16), date(2026, 11, 19), date(2026, 11, 30)}},
+		{"FREQ=WEEKLY;COUNT=3", date(2027, 1, 1),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 9), date(2026, 11, 16)}},
+		{"FREQ=WEEKLY;UNTIL=20261116T093000Z", date(2027, 1, 1),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 9), date(2026, 11, 16)}},
+		{"FREQ=MONTHLY", date(2027, 2, 1),
+			[]time.Time{date(2026, 11, 2), date(2026, 12, 2), date(2027, 1, 2)}},
+		{"FREQ=MONTHLY;BYDAY=1MO", date(2027, 2, 28),
+			[]time.Time{date(2026, 11, 2), date(2026, 12, 7), date(2027, 1, 4), date(2027, 2, 1)}},
+		{"FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR", date(2027, 3, 31),
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 27), date(2027, 1, 29), date(2027, 3, 26)}},
+	}
+	for _, test := range tests {
+		rule, err := Parse(test.rule)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, rule.Occurrences(start, start, test.to), test.rule)
+	}
+
+	// Months without the day of the start are skipped
+	rule, _ := Parse("FREQ=MONTHLY")
+	assert.Equal(t,
+		[]time.Time{date(2026, 12, 31), date(2027, 1, 31), date(2027, 3, 31), date(2027, 5, 31)},
+		rule.Occurrences(date(2026, 10, 31), date(2026, 12, 1), date(2027, 6, 30)))
+}
+
+func TestNext(t *testing.T) {
+	start := date(2026, 11, 2)
+
+	rule, _ := Parse("FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3")
+	next, rest, ok := rule.Next(start)
+	assert.True(t, ok)
+	assert.Equal(t, date(2026, 11, 6), next)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,FR;COUNT=2", rest.String())
+
+	next, rest, ok = rest.Next(next)
+	assert.True(t, ok)
+	assert.Equal(t, date(2026, 11, 9), next)
+
+	_, _, ok = rest.Next(next)
+	assert.False(t, ok, "the series ends after three occurrences")
+
+	rule, _ = Parse("FREQ=DAILY;UNTIL=20261103")
+	next, _, ok = rule.Next(start)
+	assert.True(t, ok)
+	assert.Equal(t, date(2026, 11, 3), next)
+	_, _, ok = rule.Next(next)
+	assert.False(t, ok)
+}
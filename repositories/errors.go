@@ -0,0 +1,6 @@
+package repositories
+
+import "errors"
+
+// ErrVersionConflict is returned when a conditional write finds the task at a different version
+var ErrVersionConflict = errors.New("task version conflict")
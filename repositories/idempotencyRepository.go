@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"log"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	DB *gorm.DB
+}
+
+// CreateIdempotencyRecord claims a key, returning gorm.ErrDuplicatedKey when it is stored already
+func (r *IdempotencyRepository) CreateIdempotencyRecord(record *entity.IdempotencyRecord) error {
+	key, err := toModelIdempotencyKey(*record)
+	if err != nil {
+		return err
+	}
+
+	result := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&key)
+	if result.Error != nil {
+		log.Println("Error storing idempotency key:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+	return nil
+}
+
+// GetIdempotencyRecord method retrieves the record stored for a key
+func (r *IdempotencyRepository) GetIdempotencyRecord(key string) (entity.IdempotencyRecord, error) {
+	var stored models.IdempotencyKey
+	if err := r.DB.Where("idempotency_key = ?", key).First(&stored).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching idempotency key:", err)
+		}
+		return entity.IdempotencyRecord{}, err
+	}
+	return toEntityIdempotencyRecord(stored)
+}
+
+// UpdateIdempotencyRecord method stores the response for a claimed key, returning gorm.ErrRecordNotFound if
+// the key is not stored
+func (r *IdempotencyRepository) UpdateIdempotencyRecord(record *entity.IdempotencyRecord) error {
+	key, err := toModelIdempotencyKey(*record)
+	if err != nil {
+		return err
+	}
+
+	result := r.DB.Model(&models.IdempotencyKey{}).Where("idempotency_key = ?", key.Key).Updates(map[string]interface{}{
+		"status":     key.Status,
+		"headers":    key.Headers,
+		"body":       key.Body,
+		"expires_at": key.ExpiresAt,
+	})
+	if result.Error != nil {
+		log.Println("Error updating idempotency key:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteIdempotencyRecord method removes the record of a key, returning gorm.ErrRecordNotFound if there is none
+func (r *IdempotencyRepository) DeleteIdempotencyRecord(key string) error {
+	result := r.DB.Where("idempotency_key = ?", key).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyRecord method removes the record of a key if it expired before the given time,
+// returning gorm.ErrRecordNotFound if there is none or it is still valid, as another request claimed it again
+func (r *IdempotencyRepository) DeleteExpiredIdempotencyRecord(key string, before time.Time) error {
+	result := r.DB.Where("idempotency_key = ? AND expires_at < ?", key, before).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		log.Println("Error deleting expired idempotency key:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeIdempotencyRecords method deletes the records that expired before the given time and returns how many
+// were removed
+func (r *IdempotencyRepository) PurgeIdempotencyRecords(before time.Time) (int64, error) {
+	result := r.DB.Where("expires_at < ?", before).Delete(&models.IdempotencyKey{})
+	if result.Error != nil {
+		log.Println("Error purging idempotency keys:", result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
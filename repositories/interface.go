@@ -3,16 +3,79 @@ package repositories
 import (
 	"time"
 	"todo-lists/entity"
+	"todo-lists/query"
 )
 
 // TaskRepositoryInterface defines the methods that a task repository must implement.
 type IRepo interface {
 	CreateTask(task *entity.Task) error
-	GetAllTasks() ([]entity.Task, error)
+	GetAllTasks(status string, page entity.PageRequest) (entity.TaskPage, error)
 	GetTaskById(id int) (entity.Task, error)
-	GetTasksByTag(tag string) ([]entity.Task, error)
+	GetTaskByUID(uid string) (entity.Task, error)
 	UpdateTask(task *entity.Task) error
-	SearchTasksByName(keyword string) ([]entity.Task, error)
-	FilterTasksByDeadline(start, end time.Time) ([]entity.Task, error)
-	DeleteTask(id int) error
+	PatchTask(id int, version uint, fields map[string]interface{}) (entity.Task, error)
+	UpdateTaskStatus(id int, version uint, status string, completedAt *time.Time) (entity.Task, error)
+	SearchTasksByName(keyword string, page entity.PageRequest) (entity.TaskPage, error)
+	QueryTasks(filter query.Expr, page entity.PageRequest) (entity.TaskPage, error)
+	CountTasksByStatus(filter query.Expr) (map[string]int64, error)
+	DeleteTask(id int, version uint) error
+	GetTrash(page entity.PageRequest) (entity.TaskPage, error)
+	GetRecurringTasks(before time.Time) ([]entity.Task, error)
+	GetSubtasks(parentIds []uint) ([]entity.Task, error)
+	AddDependency(dependency entity.Dependency) error
+	RemoveDependency(dependency entity.Dependency) error
+	GetDependencies(taskIds []uint) ([]entity.Dependency, error)
+	RestoreTask(id int) error
+	PurgeTask(id int) error
+	PurgeTrash(before time.Time) (int64, error)
+	GetTasksByListId(listId int, page entity.PageRequest) (entity.TaskPage, error)
+	SearchListTasksByName(listId int, keyword string, page entity.PageRequest) (entity.TaskPage, error)
+	Transaction(fn func(tasks IRepo, lists IListRepo, labels ILabelRepo) error) error
+}
+
+// IListRepo defines the methods that a list repository must implement.
+type IListRepo interface {
+	CreateList(list *entity.List) error
+	GetAllLists() ([]entity.List, error)
+	GetListById(id int) (entity.List, error)
+	UpdateList(list *entity.List) error
+	DeleteList(id int) error
+	CountTasks(listId int) (int64, error)
+	SaveListFeed(feed entity.ListFeed) error
+	GetListFeed(tokenHash string) (entity.ListFeed, error)
+	DeleteListFeed(listId int) error
+}
+
+// ILabelRepo defines the methods that a label repository must implement.
+type ILabelRepo interface {
+	CreateLabel(label *entity.Label) error
+	GetAllLabels() ([]entity.Label, error)
+	GetLabelById(id int) (entity.Label, error)
+	GetLabelByName(name string) (entity.Label, error)
+	UpdateLabel(label *entity.Label) error
+	DeleteLabel(id int) error
+	AttachLabel(taskId, labelId uint) error
+	DetachLabel(taskId, labelId uint) error
+	GetTaskLabels(taskId uint) ([]entity.Label, error)
+	GetLabelsOfTasks(taskIds []uint) (map[uint][]entity.Label, error)
+}
+
+// IViewRepo defines the methods that a saved view repository must implement.
+type IViewRepo interface {
+	CreateView(view *entity.View) error
+	GetAllViews() ([]entity.View, error)
+	GetViewById(id int) (entity.View, error)
+	GetViewByName(name string) (entity.View, error)
+	UpdateView(view *entity.View) error
+	DeleteView(id int) error
+}
+
+// IIdempotencyRepo defines the methods that an idempotency key repository must implement.
+type IIdempotencyRepo interface {
+	CreateIdempotencyRecord(record *entity.IdempotencyRecord) error
+	GetIdempotencyRecord(key string) (entity.IdempotencyRecord, error)
+	UpdateIdempotencyRecord(record *entity.IdempotencyRecord) error
+	DeleteIdempotencyRecord(key string) error
+	DeleteExpiredIdempotencyRecord(key string, before time.Time) error
+	PurgeIdempotencyRecords(before time.Time) (int64, error)
 }
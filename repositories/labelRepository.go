@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"log"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LabelRepository struct {
+	DB *gorm.DB
+}
+
+// CreateLabel saves a new label in the database and sets its ID
+func (r *LabelRepository) CreateLabel(label *entity.Label) error {
+	newLabel := toModelLabel(*label)
+	newLabel.ID = 0
+	if err := r.DB.Create(&newLabel).Error; err != nil {
+		return err
+	}
+	label.ID = newLabel.ID
+	return nil
+}
+
+// GetAllLabels fetches all labels from the database, ordered by name
+func (r *LabelRepository) GetAllLabels() ([]entity.Label, error) {
+	var mLabels []models.Label
+	if err := r.DB.Order("name").Find(&mLabels).Error; err != nil {
+		log.Println("Error fetching labels:", err)
+		return nil, err
+	}
+	return toEntityLabels(mLabels), nil
+}
+
+// GetLabelById method retrieves a label by ID from the database
+func (r *LabelRepository) GetLabelById(id int) (entity.Label, error) {
+	var label models.Label
+	if err := r.DB.First(&label, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching label:", err)
+		}
+		return entity.Label{}, err
+	}
+	return toEntityLabel(label), nil
+}
+
+// GetLabelByName method retrieves a label by its name, returning gorm.ErrRecordNotFound if there is none
+func (r *LabelRepository) GetLabelByName(name string) (entity.Label, error) {
+	var label models.Label
+	if err := r.DB.Where("name = ?", name).First(&label).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching label:", err)
+		}
+		return entity.Label{}, err
+	}
+	return toEntityLabel(label), nil
+}
+
+// UpdateLabel method renames or recolors a label, returning gorm.ErrRecordNotFound if it does not exist
+func (r *LabelRepository) UpdateLabel(label *entity.Label) error {
+	mLabel := toModelLabel(*label)
+	result := r.DB.Model(&models.Label{}).Where("id = ?", mLabel.ID).
+		Updates(map[string]interface{}{"name": mLabel.Name, "color": mLabel.Color})
+	if result.Error != nil {
+		log.Println("Error updating label:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Nothing changed is not an error for a label that exists
+		_, err := r.GetLabelById(int(label.ID))
+		return err
+	}
+	return nil
+}
+
+// DeleteLabel method deletes a label by its ID and detaches it from all tasks
+func (r *LabelRepository) DeleteLabel(id int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Label{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("label_id = ?", id).Delete(&models.TaskLabel{}).Error
+	})
+}
+
+// AttachLabel method puts a label on a task. Attaching a label the task already carries is a no-op.
+func (r *LabelRepository) AttachLabel(taskId, labelId uint) error {
+	row := models.TaskLabel{TaskID: taskId, LabelID: labelId}
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		log.Println("Error attaching label:", err)
+		return err
+	}
+	return nil
+}
+
+// DetachLabel method takes a label off a task, returning gorm.ErrRecordNotFound if the task does not carry it
+func (r *LabelRepository) DetachLabel(taskId, labelId uint) error {
+	result := r.DB.Where("task_id = ? AND label_id = ?", taskId, labelId).Delete(&models.TaskLabel{})
+	if result.Error != nil {
+		log.Println("Error detaching label:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTaskLabels method retrieves the labels of a task, ordered by name
+func (r *LabelRepository) GetTaskLabels(taskId uint) ([]entity.Label, error) {
+	var mLabels []models.Label
+	err := r.DB.Joins("JOIN task_labels ON task_labels.label_id = labels.id").
+		Where("task_labels.task_id = ?", taskId).Order("labels.name").Find(&mLabels).Error
+	if err != nil {
+		log.Println("Error fetching task labels:", err)
+		return nil, err
+	}
+	return toEntityLabels(mLabels), nil
+}
+
+// GetLabelsOfTasks method retrieves the labels of several tasks, by task and ordered by name. Tasks without
+// labels are left out.
+func (r *LabelRepository) GetLabelsOfTasks(taskIds []uint) (map[uint][]entity.Label, error) {
+	var rows []struct {
+		models.Label
+		TaskID uint
+	}
+	err := r.DB.Model(&models.Label{}).Select("labels.*, task_labels.task_id").
+		Joins("JOIN task_labels ON task_labels.label_id = labels.id").
+		Where("task_labels.task_id IN ?", taskIds).Order("labels.name").Scan(&rows).Error
+	if err != nil {
+		log.Println("Error fetching task labels:", err)
+		return nil, err
+	}
+
+	labels := map[uint][]entity.Label{}
+	for _, row := range rows {
+		labels[row.TaskID] = append(labels[row.TaskID], toEntityLabel(row.Label))
+	}
+	return labels, nil
+}
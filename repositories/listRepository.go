@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"log"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ListRepository struct {
+	DB *gorm.DB
+}
+
+// CreateList saves a new list in the database
+func (r *ListRepository) CreateList(list *entity.List) error {
+	newList := toModelList(*list)
+	newList.ID = 0
+
+	if err := r.DB.Create(&newList).Error; err != nil {
+		return err
+	}
+	list.ID = newList.ID
+	return nil
+}
+
+// GetAllLists fetches all lists from the database
+func (r *ListRepository) GetAllLists() ([]entity.List, error) {
+	var allLists []models.List
+	if err := r.DB.Find(&allLists).Error; err != nil {
+		log.Println("Error fetching lists:", err)
+		return nil, err
+	}
+
+	return toEntityLists(allLists), nil
+}
+
+// GetListById method retrieves a list by ID from the database
+func (r *ListRepository) GetListById(id int) (entity.List, error) {
+	var list models.List
+	if err := r.DB.First(&list, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching list:", err)
+		}
+		return entity.List{}, err
+	}
+
+	return toEntityList(list), nil
+}
+
+// UpdateList method updates an existing list, returning gorm.ErrRecordNotFound if it does not exist
+func (r *ListRepository) UpdateList(list *entity.List) error {
+	var existing models.List
+	if err := r.DB.First(&existing, list.ID).Error; err != nil {
+		return err
+	}
+
+	updated := toModelList(*list)
+	updated.ID = existing.ID
+	return r.DB.Save(&updated).Error
+}
+
+// DeleteList method deletes a list by its ID, together with its calendar feed
+func (r *ListRepository) DeleteList(id int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.List{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("list_id = ?", id).Delete(&models.ListFeed{}).Error
+	})
+}
+
+// CountTasks method returns the number of tasks that belong to a list
+func (r *ListRepository) CountTasks(listId int) (int64, error) {
+	var count int64
+	if err := r.DB.Model(&models.Task{}).Where("list_id = ?", listId).Count(&count).Error; err != nil {
+		log.Println("Error counting list tasks:", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// SaveListFeed method stores the calendar feed of a list, replacing the token of the feed it had before
+func (r *ListRepository) SaveListFeed(feed entity.ListFeed) error {
+	row := toModelListFeed(feed)
+	err := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "list_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash"}),
+	}).Create(&row).Error
+	if err != nil {
+		log.Println("Error saving list feed:", err)
+	}
+	return err
+}
+
+// GetListFeed method retrieves the calendar feed with the given token hash
+func (r *ListRepository) GetListFeed(tokenHash string) (entity.ListFeed, error) {
+	var row models.ListFeed
+	if err := r.DB.Where("token_hash = ?", tokenHash).First(&row).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching list feed:", err)
+		}
+		return entity.ListFeed{}, err
+	}
+	return toEntityListFeed(row), nil
+}
+
+// DeleteListFeed method deletes the calendar feed of a list, returning gorm.ErrRecordNotFound if it has none
+func (r *ListRepository) DeleteListFeed(listId int) error {
+	result := r.DB.Where("list_id = ?", listId).Delete(&models.ListFeed{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
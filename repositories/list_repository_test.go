@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"todo-lists/entity"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCreateList(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `lists`").WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	repo := &ListRepository{DB: gormDB}
+	list := &entity.List{Name: "Backend", Description: "Backend team backlog"}
+
+	err := repo.CreateList(list)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), list.ID) // The generated ID is copied back
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetListById(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &ListRepository{DB: gormDB}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `lists` WHERE `lists`.`id` = ? ORDER BY `lists`.`id` LIMIT ?")).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}).AddRow(1, "Backend", ""))
+
+	list, err := repo.GetListById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend", list.Name)
+
+	// List not found
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `lists` WHERE `lists`.`id` = ? ORDER BY `lists`.`id` LIMIT ?")).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{}))
+
+	list, err = repo.GetListById(2)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.Equal(t, entity.List{}, list)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllLists(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &ListRepository{DB: gormDB}
+
+	mock.ExpectQuery("SELECT \\* FROM `lists`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description"}).
+			AddRow(1, "Backend", "").
+			AddRow(2, "Frontend", ""))
+
+	lists, err := repo.GetAllLists()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(lists))
+	assert.Equal(t, "Frontend", lists[1].Name)
+
+	mock.ExpectQuery("SELECT \\* FROM `lists`").WillReturnError(errors.New("db error"))
+
+	lists, err = repo.GetAllLists()
+	assert.Error(t, err)
+	assert.Nil(t, lists)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteList(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &ListRepository{DB: gormDB}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `lists` WHERE `lists`.`id` = ?")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `list_feeds` WHERE list_id = ?")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.DeleteList(1))
+
+	// Deleting a list that does not exist
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `lists` WHERE `lists`.`id` = ?")).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteList(2))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountTasks(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &ListRepository{DB: gormDB}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `tasks` WHERE list_id = ?")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(4))
+
+	count, err := repo.CountTasks(3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
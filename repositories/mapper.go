@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"encoding/json"
+	"strconv"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+)
+
+// The database repositories read and write the model types only, and convert to and from the entities here.
+// Every field of an entity is mapped to its model and back; mapper_test.go fails for a field that is added to
+// one side without being mapped.
+
+// toModelTask converts a task entity into the task model
+func toModelTask(task entity.Task) models.Task {
+	mTask := models.Task{
+		ID:          task.ID,
+		ListID:      task.ListID,
+		ParentID:    task.ParentID,
+		Name:        task.Name,
+		Deadline:    task.Deadline,
+		Priority:    task.Priority,
+		Status:      task.Status,
+		CompletedAt: task.CompletedAt,
+		Version:     task.Version,
+		Recurrence:  task.Recurrence,
+		UID:         task.UID,
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+	if task.DeletedAt != nil {
+		mTask.DeletedAt = gorm.DeletedAt{Time: *task.DeletedAt, Valid: true}
+	}
+	return mTask
+}
+
+// toEntityTask converts a task model into the task entity
+func toEntityTask(mTask models.Task) entity.Task {
+	task := entity.Task{
+		ID:          mTask.ID,
+		ListID:      mTask.ListID,
+		ParentID:    mTask.ParentID,
+		Name:        mTask.Name,
+		Deadline:    mTask.Deadline,
+		Priority:    mTask.Priority,
+		Status:      mTask.Status,
+		CompletedAt: mTask.CompletedAt,
+		Version:     mTask.Version,
+		Recurrence:  mTask.Recurrence,
+		UID:         mTask.UID,
+		CreatedAt:   mTask.CreatedAt,
+		UpdatedAt:   mTask.UpdatedAt,
+	}
+	if mTask.DeletedAt.Valid {
+		deletedAt := mTask.DeletedAt.Time
+		task.DeletedAt = &deletedAt
+	}
+	return task
+}
+
+// toEntityTasks converts task models into task entities
+func toEntityTasks(mTasks []models.Task) []entity.Task {
+	tasks := make([]entity.Task, 0, len(mTasks))
+	for _, mTask := range mTasks {
+		tasks = append(tasks, toEntityTask(mTask))
+	}
+	return tasks
+}
+
+// taskColumns returns the columns of a task that UpdateTask replaces. The status and completion time only
+// change through UpdateTaskStatus, and the other columns are assigned by the repository.
+func taskColumns(task entity.Task) map[string]interface{} {
+	mTask := toModelTask(task)
+	return map[string]interface{}{
+		"list_id":    mTask.ListID,
+		"parent_id":  mTask.ParentID,
+		"name":       mTask.Name,
+		"deadline":   mTask.Deadline,
+		"priority":   mTask.Priority,
+		"recurrence": mTask.Recurrence,
+	}
+}
+
+// toModelList converts a list entity into the list model
+func toModelList(list entity.List) models.List {
+	return models.List{ID: list.ID, Name: list.Name, Description: list.Description}
+}
+
+// toEntityList converts a list model into the list entity
+func toEntityList(mList models.List) entity.List {
+	return entity.List{ID: mList.ID, Name: mList.Name, Description: mList.Description}
+}
+
+// toEntityLists converts list models into list entities, nil when there are none
+func toEntityLists(mLists []models.List) []entity.List {
+	var lists []entity.List
+	for _, mList := range mLists {
+		lists = append(lists, toEntityList(mList))
+	}
+	return lists
+}
+
+// toModelListFeed converts a list feed entity into the list feed model
+func toModelListFeed(feed entity.ListFeed) models.ListFeed {
+	return models.ListFeed{ListID: feed.ListID, TokenHash: feed.TokenHash}
+}
+
+// toEntityListFeed converts a list feed model into the list feed entity
+func toEntityListFeed(row models.ListFeed) entity.ListFeed {
+	return entity.ListFeed{ListID: row.ListID, TokenHash: row.TokenHash}
+}
+
+// toModelLabel converts a label entity into the label model
+func toModelLabel(label entity.Label) models.Label {
+	return models.Label{ID: label.ID, Name: label.Name, Color: label.Color}
+}
+
+// toEntityLabel converts a label model into the label entity
+func toEntityLabel(mLabel models.Label) entity.Label {
+	return entity.Label{ID: mLabel.ID, Name: mLabel.Name, Color: mLabel.Color}
+}
+
+// toEntityLabels converts label models into label entities
+func toEntityLabels(mLabels []models.Label) []entity.Label {
+	labels := make([]entity.Label, 0, len(mLabels))
+	for _, mLabel := range mLabels {
+		labels = append(labels, toEntityLabel(mLabel))
+	}
+	return labels
+}
+
+// toModelView converts a saved view entity into the view model. Built-in views are never stored, so their key
+// maps to no ID.
+func toModelView(view entity.View) models.View {
+	id, _ := viewId(view.ID)
+	return models.View{ID: id, Name: view.Name, Query: view.Query}
+}
+
+// toEntityView converts a view model into the view entity
+func toEntityView(mView models.View) entity.View {
+	return entity.View{ID: strconv.FormatUint(uint64(mView.ID), 10), Name: mView.Name, Query: mView.Query}
+}
+
+// toEntityViews converts view models into view entities
+func toEntityViews(mViews []models.View) []entity.View {
+	views := make([]entity.View, 0, len(mViews))
+	for _, mView := range mViews {
+		views = append(views, toEntityView(mView))
+	}
+	return views
+}
+
+// toModelDependency converts a dependency entity into the task dependency model
+func toModelDependency(dependency entity.Dependency) models.TaskDependency {
+	return models.TaskDependency{TaskID: dependency.TaskID, BlockerID: dependency.BlockerID}
+}
+
+// toEntityDependency converts a task dependency model into the dependency entity
+func toEntityDependency(row models.TaskDependency) entity.Dependency {
+	return entity.Dependency{TaskID: row.TaskID, BlockerID: row.BlockerID}
+}
+
+// toEntityDependencies converts task dependency models into dependency entities
+func toEntityDependencies(rows []models.TaskDependency) []entity.Dependency {
+	dependencies := make([]entity.Dependency, 0, len(rows))
+	for _, row := range rows {
+		dependencies = append(dependencies, toEntityDependency(row))
+	}
+	return dependencies
+}
+
+// toModelIdempotencyKey converts an idempotency record into the idempotency key model, with the response
+// headers stored as JSON
+func toModelIdempotencyKey(record entity.IdempotencyRecord) (models.IdempotencyKey, error) {
+	headers, err := json.Marshal(record.Header)
+	if err != nil {
+		return models.IdempotencyKey{}, err
+	}
+	return models.IdempotencyKey{
+		Key:         record.Key,
+		RequestHash: record.RequestHash,
+		Status:      record.Status,
+		Headers:     string(headers),
+		Body:        record.Body,
+		ExpiresAt:   record.ExpiresAt,
+	}, nil
+}
+
+// toEntityIdempotencyRecord converts an idempotency key model into the idempotency record
+func toEntityIdempotencyRecord(key models.IdempotencyKey) (entity.IdempotencyRecord, error) {
+	var header map[string]string
+	if key.Headers != "" {
+		if err := json.Unmarshal([]byte(key.Headers), &header); err != nil {
+			return entity.IdempotencyRecord{}, err
+		}
+	}
+	return entity.IdempotencyRecord{
+		Key:         key.Key,
+		RequestHash: key.RequestHash,
+		Status:      key.Status,
+		Header:      header,
+		Body:        key.Body,
+		ExpiresAt:   key.ExpiresAt,
+	}, nil
+}
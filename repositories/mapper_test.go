@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fill sets every field of the struct ptr points to, at any depth, to a distinct non-zero value. A mapping
+// that drops a field then reads back the zero value and the round trip fails. Fields of a type fill does not
+// know fail the test, so a new kind of field is never silently skipped.
+func fill(t *testing.T, ptr interface{}) {
+	seed := 0
+	fillValue(t, reflect.ValueOf(ptr).Elem(), &seed)
+}
+
+func fillValue(t *testing.T, v reflect.Value, seed *int) {
+	*seed++
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		v.Set(reflect.ValueOf(contractDay.Add(time.Duration(*seed) * time.Minute)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillValue(t, v.Field(i), seed)
+			}
+		}
+	case reflect.Ptr:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillValue(t, v.Elem(), seed)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value%d", *seed))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*seed))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(*seed))
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillValue(t, v.Index(0), seed)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		value := reflect.New(v.Type().Elem()).Elem()
+		fillValue(t, key, seed)
+		fillValue(t, value, seed)
+		v.Set(reflect.MakeMap(v.Type()))
+		v.SetMapIndex(key, value)
+	default:
+		t.Fatalf("fill does not know how to set a %s", v.Type())
+	}
+}
+
+func TestTaskMapping(t *testing.T) {
+	var task entity.Task
+	fill(t, &task)
+	assert.Equal(t, task, toEntityTask(toModelTask(task)), "every entity field is mapped to the model and back")
+
+	var mTask models.Task
+	fill(t, &mTask)
+	assert.Equal(t, mTask, toModelTask(toEntityTask(mTask)), "every model field is mapped to the entity and back")
+
+	// A task that is not trashed has no deletion time on either side
+	task.DeletedAt = nil
+	assert.False(t, toModelTask(task).DeletedAt.Valid)
+	assert.Nil(t, toEntityTask(toModelTask(task)).DeletedAt)
+}
+
+func TestTaskColumns(t *testing.T) {
+	var task entity.Task
+	fill(t, &task)
+	mTask := toModelTask(task)
+
+	columns := taskColumns(task)
+	assert.Equal(t, map[string]interface{}{
+		"list_id":    mTask.ListID,
+		"parent_id":  mTask.ParentID,
+		"name":       mTask.Name,
+		"deadline":   mTask.Deadline,
+		"priority":   mTask.Priority,
+		"recurrence": mTask.Recurrence,
+	}, columns)
+
+	// The memory repository accepts every column the database repository writes
+	for column, value := range columns {
+		assert.NoError(t, setColumn(&entity.Task{}, column, value), column)
+	}
+}
+
+func TestListMapping(t *testing.T) {
+	var list entity.List
+	fill(t, &list)
+	assert.Equal(t, list, toEntityList(toModelList(list)))
+
+	var mList models.List
+	fill(t, &mList)
+	assert.Equal(t, mList, toModelList(toEntityList(mList)))
+	assert.Nil(t, toEntityLists(nil))
+}
+
+func TestListFeedMapping(t *testing.T) {
+	var feed entity.ListFeed
+	fill(t, &feed)
+	assert.Equal(t, feed, toEntityListFeed(toModelListFeed(feed)))
+
+	var row models.ListFeed
+	fill(t, &row)
+	assert.Equal(t, row, toModelListFeed(toEntityListFeed(row)))
+}
+
+func TestLabelMapping(t *testing.T) {
+	var label entity.Label
+	fill(t, &label)
+	assert.Equal(t, label, toEntityLabel(toModelLabel(label)))
+
+	var mLabel models.Label
+	fill(t, &mLabel)
+	assert.Equal(t, mLabel, toModelLabel(toEntityLabel(mLabel)))
+	assert.Equal(t, []entity.Label{}, toEntityLabels(nil))
+}
+
+func TestViewMapping(t *testing.T) {
+	var view entity.View
+	fill(t, &view)
+	// Saved views have numeric IDs, and only the built-in views, which are never stored, are marked built in
+	view.ID = "12"
+	view.BuiltIn = false
+	assert.Equal(t, view, toEntityView(toModelView(view)))
+
+	var mView models.View
+	fill(t, &mView)
+	assert.Equal(t, mView, toModelView(toEntityView(mView)))
+
+	// A built-in key maps to no stored view
+	assert.Zero(t, toModelView(entity.View{ID: "today"}).ID)
+}
+
+func TestDependencyMapping(t *testing.T) {
+	var dependency entity.Dependency
+	fill(t, &dependency)
+	assert.Equal(t, dependency, toEntityDependency(toModelDependency(dependency)))
+
+	var row models.TaskDependency
+	fill(t, &row)
+	assert.Equal(t, row, toModelDependency(toEntityDependency(row)))
+}
+
+func TestIdempotencyMapping(t *testing.T) {
+	var record entity.IdempotencyRecord
+	fill(t, &record)
+	key, err := toModelIdempotencyKey(record)
+	require.NoError(t, err)
+	mapped, err := toEntityIdempotencyRecord(key)
+	require.NoError(t, err)
+	assert.Equal(t, record, mapped)
+
+	var mKey models.IdempotencyKey
+	fill(t, &mKey)
+	// The response headers are stored as JSON
+	mKey.Headers = `{"Location":"/tasks/1"}`
+	record, err = toEntityIdempotencyRecord(mKey)
+	require.NoError(t, err)
+	key, err = toModelIdempotencyKey(record)
+	require.NoError(t, err)
+	assert.Equal(t, mKey, key)
+
+	mKey.Headers = "not json"
+	_, err = toEntityIdempotencyRecord(mKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"time"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// CreateIdempotencyRecord claims a key, returning gorm.ErrDuplicatedKey when it is stored already
+func (r *MemoryRepository) CreateIdempotencyRecord(record *entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.idempotency[record.Key]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	r.idempotency[record.Key] = copyIdempotencyRecord(*record)
+	return nil
+}
+
+// GetIdempotencyRecord method retrieves the record stored for a key
+func (r *MemoryRepository) GetIdempotencyRecord(key string) (entity.IdempotencyRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.idempotency[key]
+	if !ok {
+		return entity.IdempotencyRecord{}, gorm.ErrRecordNotFound
+	}
+	return copyIdempotencyRecord(record), nil
+}
+
+// UpdateIdempotencyRecord method stores the response for a claimed key, returning gorm.ErrRecordNotFound if
+// the key is not stored
+func (r *MemoryRepository) UpdateIdempotencyRecord(record *entity.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.idempotency[record.Key]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	updated := copyIdempotencyRecord(*record)
+	updated.RequestHash = stored.RequestHash
+	r.idempotency[record.Key] = updated
+	return nil
+}
+
+// DeleteIdempotencyRecord method removes the record of a key, returning gorm.ErrRecordNotFound if there is none
+func (r *MemoryRepository) DeleteIdempotencyRecord(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.idempotency[key]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.idempotency, key)
+	return nil
+}
+
+// DeleteExpiredIdempotencyRecord method removes the record of a key if it expired before the given time,
+// returning gorm.ErrRecordNotFound if there is none or it is still valid, as another request claimed it again
+func (r *MemoryRepository) DeleteExpiredIdempotencyRecord(key string, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.idempotency[key]
+	if !ok || !record.ExpiresAt.Before(before) {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.idempotency, key)
+	return nil
+}
+
+// PurgeIdempotencyRecords method deletes the records that expired before the given time and returns how many
+// were removed
+func (r *MemoryRepository) PurgeIdempotencyRecords(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for key, record := range r.idempotency {
+		if record.ExpiresAt.Before(before) {
+			delete(r.idempotency, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// copyIdempotencyRecord copies a record with its header and body, so callers cannot change stored records
+func copyIdempotencyRecord(record entity.IdempotencyRecord) entity.IdempotencyRecord {
+	copied := record
+	if record.Header != nil {
+		copied.Header = make(map[string]string, len(record.Header))
+		for name, value := range record.Header {
+			copied.Header[name] = value
+		}
+	}
+	if record.Body != nil {
+		copied.Body = append([]byte(nil), record.Body...)
+	}
+	return copied
+}
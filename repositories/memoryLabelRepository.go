@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"sort"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// CreateLabel stores a new label and sets its ID. Label names are unique, like the uni_labels_name index.
+func (r *MemoryRepository) CreateLabel(label *entity.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.labelByName(label.Name); ok {
+		return gorm.ErrDuplicatedKey
+	}
+	label.ID = r.nextLabelID
+	r.nextLabelID++
+	r.labels[label.ID] = *label
+	return nil
+}
+
+// GetAllLabels fetches all labels, ordered by name
+func (r *MemoryRepository) GetAllLabels() ([]entity.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := make([]entity.Label, 0, len(r.labels))
+	for _, label := range r.labels {
+		labels = append(labels, label)
+	}
+	sortLabels(labels)
+	return labels, nil
+}
+
+// GetLabelById method retrieves a label by ID
+func (r *MemoryRepository) GetLabelById(id int) (entity.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	label, ok := r.labels[uint(id)]
+	if !ok {
+		return entity.Label{}, gorm.ErrRecordNotFound
+	}
+	return label, nil
+}
+
+// GetLabelByName method retrieves a label by its name, returning gorm.ErrRecordNotFound if there is none
+func (r *MemoryRepository) GetLabelByName(name string) (entity.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	label, ok := r.labelByName(name)
+	if !ok {
+		return entity.Label{}, gorm.ErrRecordNotFound
+	}
+	return label, nil
+}
+
+// UpdateLabel method renames or recolors a label, returning gorm.ErrRecordNotFound if it does not exist
+func (r *MemoryRepository) UpdateLabel(label *entity.Label) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.labels[label.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if other, ok := r.labelByName(label.Name); ok && other.ID != label.ID {
+		return gorm.ErrDuplicatedKey
+	}
+	r.labels[label.ID] = *label
+	return nil
+}
+
+// DeleteLabel method deletes a label by its ID and detaches it from all tasks
+func (r *MemoryRepository) DeleteLabel(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.labels[uint(id)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.labels, uint(id))
+	for link := range r.taskLabels {
+		if link.LabelID == uint(id) {
+			delete(r.taskLabels, link)
+		}
+	}
+	return nil
+}
+
+// AttachLabel method puts a label on a task. Attaching a label the task already carries is a no-op.
+func (r *MemoryRepository) AttachLabel(taskId, labelId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.taskLabels[taskLabel{TaskID: taskId, LabelID: labelId}] = true
+	return nil
+}
+
+// DetachLabel method takes a label off a task, returning gorm.ErrRecordNotFound if the task does not carry it
+func (r *MemoryRepository) DetachLabel(taskId, labelId uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	link := taskLabel{TaskID: taskId, LabelID: labelId}
+	if !r.taskLabels[link] {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.taskLabels, link)
+	return nil
+}
+
+// GetTaskLabels method retrieves the labels of a task, ordered by name
+func (r *MemoryRepository) GetTaskLabels(taskId uint) ([]entity.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	labels := []entity.Label{}
+	for link := range r.taskLabels {
+		if link.TaskID == taskId {
+			labels = append(labels, r.labels[link.LabelID])
+		}
+	}
+	sortLabels(labels)
+	return labels, nil
+}
+
+// GetLabelsOfTasks method retrieves the labels of several tasks, by task and ordered by name. Tasks without
+// labels are left out.
+func (r *MemoryRepository) GetLabelsOfTasks(taskIds []uint) (map[uint][]entity.Label, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := map[uint]bool{}
+	for _, id := range taskIds {
+		wanted[id] = true
+	}
+	labels := map[uint][]entity.Label{}
+	for link := range r.taskLabels {
+		if wanted[link.TaskID] {
+			labels[link.TaskID] = append(labels[link.TaskID], r.labels[link.LabelID])
+		}
+	}
+	for _, carried := range labels {
+		sortLabels(carried)
+	}
+	return labels, nil
+}
+
+// labelByName finds a label by its name. The caller must hold the lock.
+func (r *MemoryRepository) labelByName(name string) (entity.Label, bool) {
+	for _, label := range r.labels {
+		if label.Name == name {
+			return label, true
+		}
+	}
+	return entity.Label{}, false
+}
+
+// sortLabels orders labels by name
+func sortLabels(labels []entity.Label) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+}
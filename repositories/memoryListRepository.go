@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"sort"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// CreateList stores a new list and sets its ID
+func (r *MemoryRepository) CreateList(list *entity.List) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	list.ID = r.nextListID
+	r.nextListID++
+	r.lists[list.ID] = *list
+	return nil
+}
+
+// GetAllLists fetches all lists, ordered by ID
+func (r *MemoryRepository) GetAllLists() ([]entity.List, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var lists []entity.List
+	for _, list := range r.lists {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID < lists[j].ID })
+	return lists, nil
+}
+
+// GetListById method retrieves a list by ID
+func (r *MemoryRepository) GetListById(id int) (entity.List, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[uint(id)]
+	if !ok {
+		return entity.List{}, gorm.ErrRecordNotFound
+	}
+	return list, nil
+}
+
+// UpdateList method updates an existing list, returning gorm.ErrRecordNotFound if it does not exist
+func (r *MemoryRepository) UpdateList(list *entity.List) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[list.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.lists[list.ID] = *list
+	return nil
+}
+
+// DeleteList method deletes a list by its ID, together with its calendar feed
+func (r *MemoryRepository) DeleteList(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[uint(id)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.lists, uint(id))
+	delete(r.feeds, uint(id))
+	return nil
+}
+
+// CountTasks method returns the number of tasks outside the trash that belong to a list
+func (r *MemoryRepository) CountTasks(listId int) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && task.ListID == uint(listId) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SaveListFeed method stores the calendar feed of a list, replacing the token of the feed it had before
+func (r *MemoryRepository) SaveListFeed(feed entity.ListFeed) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for listId, existing := range r.feeds {
+		if listId != feed.ListID && existing.TokenHash == feed.TokenHash {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	r.feeds[feed.ListID] = feed
+	return nil
+}
+
+// GetListFeed method retrieves the calendar feed with the given token hash
+func (r *MemoryRepository) GetListFeed(tokenHash string) (entity.ListFeed, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, feed := range r.feeds {
+		if feed.TokenHash == tokenHash {
+			return feed, nil
+		}
+	}
+	return entity.ListFeed{}, gorm.ErrRecordNotFound
+}
+
+// DeleteListFeed method deletes the calendar feed of a list, returning gorm.ErrRecordNotFound if it has none
+func (r *MemoryRepository) DeleteListFeed(listId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.feeds[uint(listId)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.feeds, uint(listId))
+	return nil
+}
@@ -0,0 +1,695 @@
+package repositories
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+
+	"gorm.io/gorm"
+)
+
+// MemoryRepository is an IRepo, IListRepo, ILabelRepo, IViewRepo and IIdempotencyRepo that keeps tasks,
+// lists and their feeds, labels, views and idempotency keys in memory. It follows the semantics of the database repositories, so it can stand in for a database in tests
+// and demos. It is safe for concurrent use.
+type MemoryRepository struct {
+	mu locker
+	*memoryState
+}
+
+// locker guards the state of a MemoryRepository. The view a transaction runs on holds no lock of its own, as
+// the transaction holds the lock of the repository for all of it.
+type locker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// held is the locker of a view within a transaction, whose lock is already held
+type held struct{}
+
+func (held) Lock()    {}
+func (held) Unlock()  {}
+func (held) RLock()   {}
+func (held) RUnlock() {}
+
+// memoryState holds everything a MemoryRepository stores, so a transaction can restore it
+type memoryState struct {
+	tasks        map[uint]entity.Task
+	dependencies map[entity.Dependency]bool
+	nextID       uint
+	lists        map[uint]entity.List
+	nextListID   uint
+	labels       map[uint]entity.Label
+	taskLabels   map[taskLabel]bool
+	nextLabelID  uint
+	views        map[uint]entity.View
+	nextViewID   uint
+	idempotency  map[string]entity.IdempotencyRecord
+	feeds        map[uint]entity.ListFeed
+}
+
+// taskLabel links a task to one of its labels
+type taskLabel struct {
+	TaskID  uint
+	LabelID uint
+}
+
+// NewMemoryRepository returns an empty in-memory task repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{mu: &sync.RWMutex{}, memoryState: &memoryState{
+		tasks:        map[uint]entity.Task{},
+		dependencies: map[entity.Dependency]bool{},
+		nextID:       1,
+		lists:        map[uint]entity.List{},
+		nextListID:   1,
+		labels:       map[uint]entity.Label{},
+		taskLabels:   map[taskLabel]bool{},
+		nextLabelID:  1,
+		views:        map[uint]entity.View{},
+		nextViewID:   1,
+		idempotency:  map[string]entity.IdempotencyRecord{},
+		feeds:        map[uint]entity.ListFeed{},
+	}}
+}
+
+// Transaction runs fn with a view of the repository as task, list and label repository, and restores
+// everything it stored before when fn returns an error or panics. The repository stays locked until fn
+// returns, so like a database transaction fn sees no concurrent changes and a rollback loses none. fn must
+// only use the view it is given. Transactions on the view nest, restoring only what the inner one stored.
+func (r *MemoryRepository) Transaction(fn func(tasks IRepo, lists IListRepo, labels ILabelRepo) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := r.memoryState.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			*r.memoryState = saved
+		}
+	}()
+
+	view := &MemoryRepository{mu: held{}, memoryState: r.memoryState}
+	if err := fn(view, view, view); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// CreateTask stores a new task and sets its ID, version and timestamps
+func (r *MemoryRepository) CreateTask(task *entity.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task.ID = r.nextID
+	task.Version = 1
+	task.CreatedAt = timestamp()
+	task.UpdatedAt = task.CreatedAt
+	task.DeletedAt = nil
+	if task.Status == "" {
+		task.Status = entity.StatusTodo
+	}
+	r.nextID++
+	r.tasks[task.ID] = *task
+	return nil
+}
+
+// GetAllTasks fetches one page of tasks, optionally only those in the given status
+func (r *MemoryRepository) GetAllTasks(status string, page entity.PageRequest) (entity.TaskPage, error) {
+	return r.findPage(func(task entity.Task) bool {
+		return status == "" || task.Status == status
+	}, page)
+}
+
+// GetTaskById method retrieves a task by ID
+func (r *MemoryRepository) GetTaskById(id int) (entity.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.live(id)
+	if !ok {
+		return entity.Task{}, gorm.ErrRecordNotFound
+	}
+	return task, nil
+}
+
+// GetTaskByUID method retrieves the task outside the trash with the given calendar UID, the first one by ID
+// when several carry it
+func (r *MemoryRepository) GetTaskByUID(uid string) (entity.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found entity.Task
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && task.UID == uid && (found.ID == 0 || task.ID < found.ID) {
+			found = task
+		}
+	}
+	if found.ID == 0 {
+		return entity.Task{}, gorm.ErrRecordNotFound
+	}
+	return found, nil
+}
+
+// UpdateTask method replaces the editable fields of an existing task, see TaskRepository.UpdateTask
+func (r *MemoryRepository) UpdateTask(task *entity.Task) error {
+	updated, err := r.PatchTask(int(task.ID), task.Version, taskColumns(*task))
+	if err != nil {
+		return err
+	}
+	*task = updated
+	return nil
+}
+
+// PatchTask method updates only the given columns of an existing task and returns the task as stored
+// afterwards. When version is not zero the update only applies if the task is still at that version.
+func (r *MemoryRepository) PatchTask(id int, version uint, fields map[string]interface{}) (entity.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.live(id)
+	if !ok {
+		return entity.Task{}, gorm.ErrRecordNotFound
+	}
+	if version != 0 && version != task.Version {
+		return entity.Task{}, ErrVersionConflict
+	}
+
+	for column, value := range fields {
+		if err := setColumn(&task, column, value); err != nil {
+			return entity.Task{}, err
+		}
+	}
+	task.Version++
+	task.UpdatedAt = timestamp()
+	r.tasks[task.ID] = task
+	return task, nil
+}
+
+// UpdateTaskStatus method stores a new lifecycle state for a task and returns the task as stored afterwards
+func (r *MemoryRepository) UpdateTaskStatus(id int, version uint, status string, completedAt *time.Time) (entity.Task, error) {
+	return r.PatchTask(id, version, map[string]interface{}{
+		"status":       status,
+		"completed_at": completedAt,
+	})
+}
+
+// QueryTasks method retrieves a page of the tasks that match a query expression
+func (r *MemoryRepository) QueryTasks(filter query.Expr, page entity.PageRequest) (entity.TaskPage, error) {
+	return r.findPage(func(task entity.Task) bool {
+		return r.matches(filter, task)
+	}, page)
+}
+
+// CountTasksByStatus method counts the tasks that match a query expression, by status
+func (r *MemoryRepository) CountTasksByStatus(filter query.Expr) (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[string]int64{}
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && r.matches(filter, task) {
+			counts[task.Status]++
+		}
+	}
+	return counts, nil
+}
+
+// SearchTasksByName method searches for a page of tasks by keyword in their name, like a LIKE '%keyword%' query
+func (r *MemoryRepository) SearchTasksByName(keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	match := likeMatcher("%" + keyword + "%")
+	return r.findPage(func(task entity.Task) bool {
+		return match.MatchString(task.Name)
+	}, page)
+}
+
+// DeleteTask method moves a task to the trash by its ID. When version is not zero the task is only deleted
+// if it is still at that version.
+func (r *MemoryRepository) DeleteTask(id int, version uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.live(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if version != 0 && version != task.Version {
+		return ErrVersionConflict
+	}
+
+	deletedAt := time.Now()
+	task.DeletedAt = &deletedAt
+	r.tasks[task.ID] = task
+	return nil
+}
+
+// GetTrash method retrieves a page of the deleted tasks
+func (r *MemoryRepository) GetTrash(page entity.PageRequest) (entity.TaskPage, error) {
+	return r.find(func(task entity.Task) bool {
+		return task.DeletedAt != nil
+	}, page)
+}
+
+// RestoreTask method moves a task out of the trash, returning gorm.ErrRecordNotFound if it is not trashed
+func (r *MemoryRepository) RestoreTask(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[uint(id)]
+	if !ok || task.DeletedAt == nil {
+		return gorm.ErrRecordNotFound
+	}
+	task.DeletedAt = nil
+	task.Version++
+	task.UpdatedAt = timestamp()
+	r.tasks[task.ID] = task
+	return nil
+}
+
+// PurgeTask method permanently deletes a trashed task and its dependencies, returning gorm.ErrRecordNotFound
+// if it is not trashed
+func (r *MemoryRepository) PurgeTask(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[uint(id)]
+	if !ok || task.DeletedAt == nil {
+		return gorm.ErrRecordNotFound
+	}
+	r.purge(task.ID)
+	return nil
+}
+
+// PurgeTrash method permanently deletes the tasks trashed before the given time and their dependencies, and
+// returns how many tasks were removed
+func (r *MemoryRepository) PurgeTrash(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, task := range r.tasks {
+		if task.DeletedAt != nil && task.DeletedAt.Before(before) {
+			r.purge(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// GetRecurringTasks method retrieves the open tasks with a recurrence rule that are due no later than before
+func (r *MemoryRepository) GetRecurringTasks(before time.Time) ([]entity.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := []entity.Task{}
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && task.Recurrence != "" && task.Deadline != nil && !task.Deadline.After(before) &&
+			task.Status != entity.StatusDone && task.Status != entity.StatusCancelled {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// GetSubtasks method retrieves the direct subtasks of the given tasks, ordered by ID
+func (r *MemoryRepository) GetSubtasks(parentIds []uint) ([]entity.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	parents := make(map[uint]bool, len(parentIds))
+	for _, id := range parentIds {
+		parents[id] = true
+	}
+	tasks := []entity.Task{}
+	for _, task := range r.tasks {
+		if task.DeletedAt == nil && parents[task.ParentID] {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks, nil
+}
+
+// AddDependency method stores that a task waits for a blocker. Adding a dependency that exists is a no-op.
+func (r *MemoryRepository) AddDependency(dependency entity.Dependency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dependencies[dependency] = true
+	return nil
+}
+
+// RemoveDependency method deletes a dependency, returning gorm.ErrRecordNotFound if it does not exist
+func (r *MemoryRepository) RemoveDependency(dependency entity.Dependency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dependencies[dependency] {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.dependencies, dependency)
+	return nil
+}
+
+// GetDependencies method retrieves the dependencies of the given tasks, ordered by task and blocker
+func (r *MemoryRepository) GetDependencies(taskIds []uint) ([]entity.Dependency, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tasks := make(map[uint]bool, len(taskIds))
+	for _, id := range taskIds {
+		tasks[id] = true
+	}
+	dependencies := []entity.Dependency{}
+	for dependency := range r.dependencies {
+		if tasks[dependency.TaskID] {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		a, b := dependencies[i], dependencies[j]
+		if a.TaskID != b.TaskID {
+			return a.TaskID < b.TaskID
+		}
+		return a.BlockerID < b.BlockerID
+	})
+	return dependencies, nil
+}
+
+// GetTasksByListId method retrieves a page of the tasks that belong to a list
+func (r *MemoryRepository) GetTasksByListId(listId int, page entity.PageRequest) (entity.TaskPage, error) {
+	return r.findPage(func(task entity.Task) bool {
+		return task.ListID == uint(listId)
+	}, page)
+}
+
+// SearchListTasksByName method searches a page of the tasks of a list by keyword in their name
+func (r *MemoryRepository) SearchListTasksByName(listId int, keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	match := likeMatcher("%" + keyword + "%")
+	return r.findPage(func(task entity.Task) bool {
+		return task.ListID == uint(listId) && match.MatchString(task.Name)
+	}, page)
+}
+
+// purge removes a task with its dependencies and labels. The caller must hold the lock.
+func (r *MemoryRepository) purge(id uint) {
+	delete(r.tasks, id)
+	for dependency := range r.dependencies {
+		if dependency.TaskID == id || dependency.BlockerID == id {
+			delete(r.dependencies, dependency)
+		}
+	}
+	for link := range r.taskLabels {
+		if link.TaskID == id {
+			delete(r.taskLabels, link)
+		}
+	}
+}
+
+// matches evaluates a query expression against a task with the semantics of the SQL translation of
+// TaskRepository.QueryTasks. The caller must hold the lock.
+func (r *MemoryRepository) matches(filter query.Expr, task entity.Task) bool {
+	switch e := filter.(type) {
+	case query.And:
+		return r.matches(e.Left, task) && r.matches(e.Right, task)
+	case query.Or:
+		return r.matches(e.Left, task) || r.matches(e.Right, task)
+	case query.Not:
+		return !r.matches(e.Expr, task)
+	case query.Term:
+		return r.matchesTerm(e, task)
+	}
+	panic("unknown query expression")
+}
+
+// matchesTerm evaluates a single term against a task. The caller must hold the lock.
+func (r *MemoryRepository) matchesTerm(term query.Term, task entity.Task) bool {
+	switch term.Field {
+	case query.FieldName:
+		name, value := strings.ToLower(task.Name), strings.ToLower(term.Value)
+		if term.Op == query.Contains {
+			return strings.Contains(name, value)
+		}
+		return name == value
+	case query.FieldLabel:
+		for link := range r.taskLabels {
+			if link.TaskID == task.ID && r.labels[link.LabelID].Name == term.Value {
+				return true
+			}
+		}
+		return false
+	case query.FieldPriority:
+		return task.Priority == term.Value
+	case query.FieldStatus:
+		return task.Status == term.Value
+	case query.FieldList:
+		return task.ListID == term.ID
+	case query.FieldParent:
+		return task.ParentID == term.ID
+	case query.FieldDeadline:
+		if term.Value == query.None {
+			return task.Deadline == nil
+		}
+		if task.Deadline == nil {
+			// Like NULL in SQL, a missing deadline compares with nothing
+			return false
+		}
+		day := !term.Until.IsZero()
+		switch term.Op {
+		case query.Less:
+			return task.Deadline.Before(term.Time)
+		case query.LessEqual:
+			if day {
+				return task.Deadline.Before(term.Until)
+			}
+			return !task.Deadline.After(term.Time)
+		case query.Greater:
+			if day {
+				return !task.Deadline.Before(term.Until)
+			}
+			return task.Deadline.After(term.Time)
+		case query.GreaterEqual:
+			return !task.Deadline.Before(term.Time)
+		}
+		if day {
+			return !task.Deadline.Before(term.Time) && task.Deadline.Before(term.Until)
+		}
+		return task.Deadline.Equal(term.Time)
+	}
+	panic("unknown query field " + term.Field)
+}
+
+// live returns a task that is not in the trash. The caller must hold the lock.
+func (r *MemoryRepository) live(id int) (entity.Task, bool) {
+	task, ok := r.tasks[uint(id)]
+	if !ok || task.DeletedAt != nil {
+		return entity.Task{}, false
+	}
+	return task, true
+}
+
+// findPage returns one page of the tasks outside the trash that satisfy keep
+func (r *MemoryRepository) findPage(keep func(entity.Task) bool, page entity.PageRequest) (entity.TaskPage, error) {
+	return r.find(func(task entity.Task) bool {
+		return task.DeletedAt == nil && keep(task)
+	}, page)
+}
+
+// find returns one page of the tasks that satisfy keep, ordered and continued like the keyset pagination of
+// the database repositories
+func (r *MemoryRepository) find(keep func(entity.Task) bool, page entity.PageRequest) (entity.TaskPage, error) {
+	field := page.Sort
+	if field == entity.SortRank {
+		field = "id"
+	}
+
+	var after *entity.Task
+	if page.After != nil {
+		point, err := cursorTask(field, *page.After)
+		if err != nil {
+			return entity.TaskPage{}, entity.ErrInvalidCursor
+		}
+		after = &point
+	}
+
+	r.mu.RLock()
+	var tasks []entity.Task
+	for _, task := range r.tasks {
+		if !keep(task) {
+			continue
+		}
+		if after != nil && compareTasks(task, *after, field, page.Desc) <= 0 {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return compareTasks(tasks[i], tasks[j], field, page.Desc) < 0
+	})
+
+	result := entity.TaskPage{Tasks: []entity.Task{}}
+	if len(tasks) > page.Limit {
+		tasks = tasks[:page.Limit]
+		last := tasks[len(tasks)-1]
+		result.Next = page.NewCursor(sortValue(last, page.Sort), last.ID).Encode()
+	}
+	result.Tasks = append(result.Tasks, tasks...)
+	return result, nil
+}
+
+// cursorTask builds a task holding the sort value and ID of a cursor, to compare other tasks against
+func cursorTask(field string, cursor entity.Cursor) (entity.Task, error) {
+	task := entity.Task{ID: cursor.ID}
+	switch field {
+	case "deadline":
+		if cursor.Value == "" {
+			break
+		}
+		deadline, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return entity.Task{}, err
+		}
+		task.Deadline = &deadline
+	case "name":
+		task.Name = cursor.Value
+	case "priority":
+		task.Priority = cursor.Value
+	}
+	return task, nil
+}
+
+// compareTasks orders two tasks by the sort field and then by ID, both reversed when desc is set
+func compareTasks(a, b entity.Task, field string, desc bool) int {
+	cmp := 0
+	switch field {
+	case "deadline":
+		cmp = entity.CompareDeadlines(a.Deadline, b.Deadline)
+	case "name":
+		cmp = strings.Compare(a.Name, b.Name)
+	case "priority":
+		cmp = entity.PriorityRank(a.Priority) - entity.PriorityRank(b.Priority)
+	}
+	if cmp == 0 {
+		switch {
+		case a.ID < b.ID:
+			cmp = -1
+		case a.ID > b.ID:
+			cmp = 1
+		}
+	}
+	if desc {
+		return -cmp
+	}
+	return cmp
+}
+
+// setColumn writes the value of a database column to the matching task field
+func setColumn(task *entity.Task, column string, value interface{}) error {
+	var ok bool
+	switch column {
+	case "list_id":
+		task.ListID, ok = idValue(value)
+	case "parent_id":
+		task.ParentID, ok = idValue(value)
+	case "name":
+		task.Name, ok = value.(string)
+	case "deadline":
+		task.Deadline, ok = value.(*time.Time)
+	case "priority":
+		task.Priority, ok = value.(string)
+	case "status":
+		task.Status, ok = value.(string)
+	case "completed_at":
+		task.CompletedAt, ok = value.(*time.Time)
+	case "recurrence":
+		task.Recurrence, ok = value.(string)
+	default:
+		return fmt.Errorf("unknown task column %q", column)
+	}
+	if !ok {
+		return fmt.Errorf("invalid value %v for task column %q", value, column)
+	}
+	return nil
+}
+
+// timestamp returns the current time at the millisecond precision the database repositories store
+func timestamp() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// idValue reads an ID column value, which callers pass as uint or int
+func idValue(value interface{}) (uint, bool) {
+	switch v := value.(type) {
+	case uint:
+		return v, true
+	case int:
+		return uint(v), v >= 0
+	}
+	return 0, false
+}
+
+// likeMatcher compiles a SQL LIKE pattern, where % matches any run of characters and _ a single one. Like
+// the default collations of MySQL and SQLite the match ignores case.
+func likeMatcher(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// clone copies the state, so later changes to the repository leave the copy as it was
+func (s memoryState) clone() memoryState {
+	copied := s
+	copied.tasks = make(map[uint]entity.Task, len(s.tasks))
+	for id, task := range s.tasks {
+		copied.tasks[id] = task
+	}
+	copied.dependencies = make(map[entity.Dependency]bool, len(s.dependencies))
+	for dependency := range s.dependencies {
+		copied.dependencies[dependency] = true
+	}
+	copied.lists = make(map[uint]entity.List, len(s.lists))
+	for id, list := range s.lists {
+		copied.lists[id] = list
+	}
+	copied.labels = make(map[uint]entity.Label, len(s.labels))
+	for id, label := range s.labels {
+		copied.labels[id] = label
+	}
+	copied.taskLabels = make(map[taskLabel]bool, len(s.taskLabels))
+	for link := range s.taskLabels {
+		copied.taskLabels[link] = true
+	}
+	copied.views = make(map[uint]entity.View, len(s.views))
+	for id, view := range s.views {
+		copied.views[id] = view
+	}
+	copied.idempotency = make(map[string]entity.IdempotencyRecord, len(s.idempotency))
+	for key, record := range s.idempotency {
+		copied.idempotency[key] = record
+	}
+	copied.feeds = make(map[uint]entity.ListFeed, len(s.feeds))
+	for listId, feed := range s.feeds {
+		copied.feeds[listId] = feed
+	}
+	return copied
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"sort"
+	"strconv"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// CreateView stores a new saved view and sets its ID. View names are unique, like the uni_views_name index.
+func (r *MemoryRepository) CreateView(view *entity.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.viewByName(view.Name); ok {
+		return gorm.ErrDuplicatedKey
+	}
+	id := r.nextViewID
+	r.nextViewID++
+	view.ID = strconv.FormatUint(uint64(id), 10)
+	r.views[id] = entity.View{ID: view.ID, Name: view.Name, Query: view.Query}
+	return nil
+}
+
+// GetAllViews fetches all saved views, ordered by name
+func (r *MemoryRepository) GetAllViews() ([]entity.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	views := make([]entity.View, 0, len(r.views))
+	for _, view := range r.views {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+// GetViewById method retrieves a saved view by ID
+func (r *MemoryRepository) GetViewById(id int) (entity.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.views[uint(id)]
+	if !ok {
+		return entity.View{}, gorm.ErrRecordNotFound
+	}
+	return view, nil
+}
+
+// GetViewByName method retrieves a saved view by its name, returning gorm.ErrRecordNotFound if there is none
+func (r *MemoryRepository) GetViewByName(name string) (entity.View, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	view, ok := r.viewByName(name)
+	if !ok {
+		return entity.View{}, gorm.ErrRecordNotFound
+	}
+	return view, nil
+}
+
+// UpdateView method renames a saved view or replaces its query, returning gorm.ErrRecordNotFound if it does
+// not exist
+func (r *MemoryRepository) UpdateView(view *entity.View) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, ok := viewId(view.ID)
+	if _, exists := r.views[id]; !ok || !exists {
+		return gorm.ErrRecordNotFound
+	}
+	if other, ok := r.viewByName(view.Name); ok && other.ID != view.ID {
+		return gorm.ErrDuplicatedKey
+	}
+	r.views[id] = entity.View{ID: view.ID, Name: view.Name, Query: view.Query}
+	return nil
+}
+
+// DeleteView method deletes a saved view by its ID
+func (r *MemoryRepository) DeleteView(id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.views[uint(id)]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.views, uint(id))
+	return nil
+}
+
+// viewByName finds a saved view by its name. The caller must hold the lock.
+func (r *MemoryRepository) viewByName(name string) (entity.View, bool) {
+	for _, view := range r.views {
+		if view.Name == name {
+			return view, true
+		}
+	}
+	return entity.View{}, false
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"todo-lists/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRepositoryConcurrentWrites(t *testing.T) {
+	repo := NewMemoryRepository()
+	task := entity.Task{Name: "shared", Deadline: due(time.Now()), Priority: "less"}
+	assert.NoError(t, repo.CreateTask(&task))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, repo.CreateTask(&entity.Task{Name: "new", Deadline: due(time.Now()), Priority: "high"}))
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := repo.PatchTask(int(task.ID), 0, map[string]interface{}{"name": "renamed"})
+			assert.NoError(t, err)
+			_, err = repo.GetAllTasks("", defaultPage)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Every write got its own ID or version
+	stored, err := repo.GetTaskById(int(task.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(21), stored.Version)
+	all, err := repo.GetAllTasks("", entity.PageRequest{Limit: entity.MaxPageLimit, Sort: "id"})
+	assert.NoError(t, err)
+	assert.Len(t, all.Tasks, 21)
+}
+
+func TestMemoryRepositoryTransactionIsolation(t *testing.T) {
+	repo := NewMemoryRepository()
+	failed := errors.New("failed")
+
+	// A write from outside waits for the transaction, so its rollback does not lose the write
+	done := make(chan struct{})
+	err := repo.Transaction(func(tasks IRepo, lists IListRepo, labels ILabelRepo) error {
+		assert.NoError(t, tasks.CreateTask(&entity.Task{Name: "rolled back", Priority: "less"}))
+		go func() {
+			defer close(done)
+			assert.NoError(t, repo.CreateTask(&entity.Task{Name: "concurrent", Priority: "high"}))
+		}()
+		select {
+		case <-done:
+			t.Error("the concurrent write did not wait for the transaction")
+		case <-time.After(20 * time.Millisecond):
+		}
+		return failed
+	})
+	assert.Equal(t, failed, err)
+	<-done
+	all, err := repo.GetAllTasks("", defaultPage)
+	assert.NoError(t, err)
+	if assert.Len(t, all.Tasks, 1) {
+		assert.Equal(t, "concurrent", all.Tasks[0].Name)
+	}
+
+	// A panic rolls back too, and leaves the repository unlocked
+	assert.Panics(t, func() {
+		repo.Transaction(func(tasks IRepo, lists IListRepo, labels ILabelRepo) error {
+			assert.NoError(t, tasks.CreateTask(&entity.Task{Name: "panicked", Priority: "less"}))
+			panic("failed")
+		})
+	})
+	all, err = repo.GetAllTasks("", defaultPage)
+	assert.NoError(t, err)
+	assert.Len(t, all.Tasks, 1)
+}
+
+func TestMemoryRepositoryRejectsUnknownColumns(t *testing.T) {
+	repo := NewMemoryRepository()
+	task := entity.Task{Name: "a", Deadline: due(time.Now()), Priority: "less"}
+	assert.NoError(t, repo.CreateTask(&task))
+
+	_, err := repo.PatchTask(int(task.ID), 0, map[string]interface{}{"priority": 1})
+	assert.Error(t, err)
+	_, err = repo.PatchTask(int(task.ID), 0, map[string]interface{}{"name": 1})
+	assert.Error(t, err)
+
+	// A rejected patch leaves the task untouched
+	stored, err := repo.GetTaskById(int(task.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), stored.Version)
+}
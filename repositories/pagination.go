@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"strconv"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+)
+
+// priorityRank orders priorities like entity.PriorityRank, since their names do not sort in priority order
+const priorityRank = "CASE priority WHEN 'less' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END"
+
+// paginate applies the keyset condition, ordering and limit of a page to a task query. One row more than
+// the page size is requested so findPage can tell whether another page follows.
+func paginate(query *gorm.DB, page entity.PageRequest) (*gorm.DB, error) {
+	column := page.Sort
+	cmp, dir := ">", "ASC"
+	if page.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if column == entity.SortRank {
+		// Only full-text searches on Postgres can score rows, elsewhere relevance falls back to ID order
+		column = "id"
+	}
+	order := column
+	if column == "priority" {
+		order = priorityRank
+	}
+
+	if page.After != nil {
+		value, err := cursorValue(column, page.After.Value)
+		if err != nil {
+			return nil, entity.ErrInvalidCursor
+		}
+		switch {
+		case column == "id":
+			query = query.Where("id "+cmp+" ?", page.After.ID)
+		case column == "deadline" && value == nil:
+			// The cursor is on a task without a deadline, the group that sorts last
+			if page.Desc {
+				query = query.Where("(deadline IS NOT NULL OR id < ?)", page.After.ID)
+			} else {
+				query = query.Where("(deadline IS NULL AND id > ?)", page.After.ID)
+			}
+		case column == "deadline" && !page.Desc:
+			query = query.Where("(deadline > ? OR (deadline = ? AND id > ?) OR deadline IS NULL)", value, value, page.After.ID)
+		default:
+			query = query.Where("("+order+" "+cmp+" ? OR ("+order+" = ? AND id "+cmp+" ?))", value, value, page.After.ID)
+		}
+	}
+
+	if column == "deadline" {
+		// Tasks without a deadline come last on every backend, whatever its own NULL ordering
+		query = query.Order("deadline IS NULL " + dir)
+	}
+	if column != "id" {
+		query = query.Order(order + " " + dir)
+	}
+	return query.Order("id " + dir).Limit(page.Limit + 1), nil
+}
+
+// findPage runs a task query for one page and builds the cursor for the next one
+func (r *TaskRepository) findPage(query *gorm.DB, page entity.PageRequest) (entity.TaskPage, error) {
+	query, err := paginate(query, page)
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	var tasks []models.Task
+	if err := query.Find(&tasks).Error; err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	result := entity.TaskPage{Tasks: []entity.Task{}}
+	if len(tasks) > page.Limit {
+		tasks = tasks[:page.Limit]
+		last := tasks[len(tasks)-1]
+		result.Next = page.NewCursor(sortValue(toEntityTask(last), page.Sort), last.ID).Encode()
+	}
+	for _, mTask := range tasks {
+		result.Tasks = append(result.Tasks, toEntityTask(mTask))
+	}
+	return result, nil
+}
+
+// tsRank scores how well the name of a task matches a Postgres full-text query
+const tsRank = "ts_rank(to_tsvector('english', name), plainto_tsquery('english', ?))"
+
+// rankedTask is a task row with its full-text search score
+type rankedTask struct {
+	models.Task `gorm:"embedded"`
+	SearchRank  float32 `gorm:"column:search_rank"`
+}
+
+// findRankedPage runs a Postgres full-text search for one page ordered by relevance, ties broken by ID
+func (r *TaskRepository) findRankedPage(query *gorm.DB, keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	rank := gorm.Expr(tsRank, keyword)
+	if page.After != nil && page.After.Value != "" {
+		value, err := strconv.ParseFloat(page.After.Value, 32)
+		if err != nil {
+			return entity.TaskPage{}, entity.ErrInvalidCursor
+		}
+		after := float32(value)
+		query = query.Where("(? < ? OR (? = ? AND id > ?))", rank, after, rank, after, page.After.ID)
+	}
+
+	var tasks []rankedTask
+	err := query.Select("tasks.*, ? AS search_rank", rank).
+		Order("search_rank DESC").Order("id ASC").Limit(page.Limit + 1).
+		Find(&tasks).Error
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+
+	result := entity.TaskPage{Tasks: []entity.Task{}}
+	if len(tasks) > page.Limit {
+		tasks = tasks[:page.Limit]
+		last := tasks[len(tasks)-1]
+		result.Next = page.NewCursor(strconv.FormatFloat(float64(last.SearchRank), 'g', -1, 32), last.ID).Encode()
+	}
+	for _, task := range tasks {
+		result.Tasks = append(result.Tasks, toEntityTask(task.Task))
+	}
+	return result, nil
+}
+
+// sortValue returns the cursor representation of the sort field of a task
+func sortValue(task entity.Task, field string) string {
+	switch field {
+	case "deadline":
+		if task.Deadline == nil {
+			return ""
+		}
+		return task.Deadline.UTC().Format(time.RFC3339Nano)
+	case "name":
+		return task.Name
+	case "priority":
+		return task.Priority
+	case entity.SortRank:
+		return ""
+	}
+	return strconv.FormatUint(uint64(task.ID), 10)
+}
+
+// cursorValue converts a cursor value back into the type of its sort column, nil for a missing deadline and
+// the rank of a priority
+func cursorValue(field, value string) (interface{}, error) {
+	switch field {
+	case "deadline":
+		if value == "" {
+			return nil, nil
+		}
+		return time.Parse(time.RFC3339Nano, value)
+	case "priority":
+		return entity.PriorityRank(value), nil
+	}
+	return value, nil
+}
@@ -0,0 +1,964 @@
+package repositories
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/migrations"
+	"todo-lists/query"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The contract tests describe the behaviour every IRepo, IListRepo, ILabelRepo, IViewRepo and
+// IIdempotencyRepo implementation shares, so the in-memory repository can stand in for the database one.
+
+// contractRepo is a task repository together with the list, label, view and idempotency key repositories that
+// share its storage
+type contractRepo interface {
+	IRepo
+	IListRepo
+	ILabelRepo
+	IViewRepo
+	IIdempotencyRepo
+}
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	runRepoContract(t, func(t *testing.T) contractRepo {
+		return NewMemoryRepository()
+	})
+}
+
+func TestTaskRepositoryContract(t *testing.T) {
+	runRepoContract(t, func(t *testing.T) contractRepo {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+			Logger:  logger.Discard,
+			NowFunc: func() time.Time { return time.Now().UTC().Truncate(time.Millisecond) },
+		})
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		migrator, err := migrations.New(db)
+		require.NoError(t, err)
+		_, err = migrator.Up()
+		require.NoError(t, err)
+		return struct {
+			*TaskRepository
+			*ListRepository
+			*LabelRepository
+			*ViewRepository
+			*IdempotencyRepository
+		}{&TaskRepository{DB: db}, &ListRepository{DB: db}, &LabelRepository{DB: db}, &ViewRepository{DB: db}, &IdempotencyRepository{DB: db}}
+	})
+}
+
+var contractDay = time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+// due returns a deadline for a task literal
+func due(deadline time.Time) *time.Time {
+	return &deadline
+}
+
+// seedTasks creates tasks in the repository and returns them with their IDs
+func seedTasks(t *testing.T, repo IRepo, tasks ...entity.Task) []entity.Task {
+	for i := range tasks {
+		if tasks[i].Status == "" {
+			tasks[i].Status = entity.StatusTodo
+		}
+		require.NoError(t, repo.CreateTask(&tasks[i]))
+	}
+	return tasks
+}
+
+func taskIDs(page entity.TaskPage) []uint {
+	ids := []uint{}
+	for _, task := range page.Tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// allPages follows the cursors of a query and returns the IDs of every page in order
+func allPages(t *testing.T, page entity.PageRequest, query func(entity.PageRequest) (entity.TaskPage, error)) [][]uint {
+	var pages [][]uint
+	for {
+		result, err := query(page)
+		require.NoError(t, err)
+		pages = append(pages, taskIDs(result))
+		if result.Next == "" {
+			return pages
+		}
+		after, err := entity.DecodeCursor(result.Next)
+		require.NoError(t, err)
+		page.After = &after
+	}
+}
+
+// seedLabels creates labels in the repository and returns them with their IDs
+func seedLabels(t *testing.T, repo ILabelRepo, names ...string) []entity.Label {
+	var labels []entity.Label
+	for _, name := range names {
+		label := entity.Label{Name: name, Color: entity.DefaultLabelColor}
+		require.NoError(t, repo.CreateLabel(&label))
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// queryIDs runs a filter expression and returns the IDs of the first page of matching tasks
+func queryIDs(t *testing.T, repo IRepo, q string) []uint {
+	filter, err := query.Parse(q)
+	require.NoError(t, err, q)
+	result, err := repo.QueryTasks(filter, entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"})
+	require.NoError(t, err, q)
+	return taskIDs(result)
+}
+
+func runRepoContract(t *testing.T, newRepo func(t *testing.T) contractRepo) {
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		task := entity.Task{ListID: 3, Name: "Write report", Deadline: due(contractDay), Priority: "high", Status: entity.StatusTodo}
+		require.NoError(t, repo.CreateTask(&task))
+		assert.NotZero(t, task.ID)
+		assert.Equal(t, uint(1), task.Version)
+		assert.False(t, task.CreatedAt.IsZero())
+		assert.Equal(t, task.CreatedAt, task.UpdatedAt)
+
+		stored, err := repo.GetTaskById(int(task.ID))
+		require.NoError(t, err)
+		assert.Equal(t, task.ID, stored.ID)
+		assert.Equal(t, uint(3), stored.ListID)
+		assert.Equal(t, "Write report", stored.Name)
+		assert.True(t, contractDay.Equal(*stored.Deadline))
+		assert.Equal(t, "high", stored.Priority)
+		assert.Equal(t, entity.StatusTodo, stored.Status)
+		assert.True(t, task.CreatedAt.Equal(stored.CreatedAt), "the returned timestamps are the stored ones")
+		assert.True(t, task.UpdatedAt.Equal(stored.UpdatedAt))
+		assert.Nil(t, stored.CompletedAt)
+		assert.Nil(t, stored.DeletedAt)
+
+		_, err = repo.GetTaskById(int(task.ID) + 100)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		// The fields the repository assigns are never taken from the caller
+		other := entity.Task{ID: 99, Version: 7, Name: "Write code", Priority: "less", Status: entity.StatusTodo, CreatedAt: contractDay, DeletedAt: due(contractDay)}
+		require.NoError(t, repo.CreateTask(&other))
+		assert.Equal(t, task.ID+1, other.ID)
+		assert.Equal(t, uint(1), other.Version)
+		assert.False(t, other.CreatedAt.Equal(contractDay))
+		assert.Nil(t, other.DeletedAt)
+	})
+
+	t.Run("GetTaskByUID", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "Write report", Priority: "high"},
+			entity.Task{Name: "Review report", Priority: "high", UID: "review@example.com"},
+			entity.Task{Name: "Review again", Priority: "high", UID: "review@example.com"},
+		)
+		assert.Equal(t, "review@example.com", tasks[1].UID)
+
+		found, err := repo.GetTaskByUID("review@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, tasks[1].ID, found.ID)
+		assert.Equal(t, "review@example.com", found.UID)
+
+		// Tasks in the trash are not found
+		require.NoError(t, repo.DeleteTask(int(tasks[1].ID), 0))
+		found, err = repo.GetTaskByUID("review@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, tasks[2].ID, found.ID)
+
+		// The UID is kept by updates
+		tasks[2].Name = "Review once more"
+		require.NoError(t, repo.UpdateTask(&tasks[2]))
+		assert.Equal(t, "review@example.com", tasks[2].UID)
+
+		_, err = repo.GetTaskByUID("missing@example.com")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("GetAllTasksByStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "a", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "b", Deadline: due(contractDay), Priority: "less", Status: entity.StatusDone},
+			entity.Task{Name: "c", Deadline: due(contractDay), Priority: "less"},
+		)
+
+		all, err := repo.GetAllTasks("", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID}, taskIDs(all))
+
+		done, err := repo.GetAllTasks(entity.StatusDone, page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[1].ID}, taskIDs(done))
+
+		none, err := repo.GetAllTasks(entity.StatusBlocked, page)
+		require.NoError(t, err)
+		assert.NotNil(t, none.Tasks)
+		assert.Empty(t, none.Tasks)
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "delta", Deadline: due(contractDay.Add(48 * time.Hour)), Priority: "high"},
+			entity.Task{Name: "alpha", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "charlie", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "medium"},
+			entity.Task{Name: "bravo", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "echo", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "less"},
+		)
+		id := func(i int) uint { return tasks[i].ID }
+		getAll := func(p entity.PageRequest) (entity.TaskPage, error) { return repo.GetAllTasks("", p) }
+
+		assert.Equal(t, [][]uint{{id(0), id(1)}, {id(2), id(3)}, {id(4)}},
+			allPages(t, entity.PageRequest{Limit: 2, Sort: "id"}, getAll))
+		assert.Equal(t, [][]uint{{id(4), id(3)}, {id(2), id(1)}, {id(0)}},
+			allPages(t, entity.PageRequest{Limit: 2, Sort: "id", Desc: true}, getAll))
+		assert.Equal(t, [][]uint{{id(1), id(3)}, {id(2), id(4)}, {id(0)}},
+			allPages(t, entity.PageRequest{Limit: 2, Sort: "deadline"}, getAll))
+		assert.Equal(t, [][]uint{{id(0), id(4), id(2)}, {id(3), id(1)}},
+			allPages(t, entity.PageRequest{Limit: 3, Sort: "deadline", Desc: true}, getAll))
+		assert.Equal(t, [][]uint{{id(1), id(3), id(2)}, {id(0), id(4)}},
+			allPages(t, entity.PageRequest{Limit: 3, Sort: "name"}, getAll))
+		// Priorities sort by rank, not by name
+		assert.Equal(t, [][]uint{{id(1), id(4)}, {id(2), id(0)}, {id(3)}},
+			allPages(t, entity.PageRequest{Limit: 2, Sort: "priority"}, getAll))
+		assert.Equal(t, [][]uint{{id(3), id(0)}, {id(2), id(4)}, {id(1)}},
+			allPages(t, entity.PageRequest{Limit: 2, Sort: "priority", Desc: true}, getAll))
+		// tag is the old name of the priority sort
+		tag, err := entity.ParsePageRequest("2", "-tag", "")
+		require.NoError(t, err)
+		assert.Equal(t, [][]uint{{id(3), id(0)}, {id(2), id(4)}, {id(1)}}, allPages(t, tag, getAll))
+		assert.Equal(t, [][]uint{{id(0), id(1), id(2), id(3), id(4)}},
+			allPages(t, entity.PageRequest{Limit: 5, Sort: entity.SortRank}, getAll))
+	})
+
+	t.Run("OptionalDeadline", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "someday", Priority: "less"},
+			entity.Task{Name: "later", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "less"},
+			entity.Task{Name: "never", Priority: "less"},
+			entity.Task{Name: "soon", Deadline: due(contractDay), Priority: "less"},
+		)
+		id := func(i int) uint { return tasks[i].ID }
+		getAll := func(p entity.PageRequest) (entity.TaskPage, error) { return repo.GetAllTasks("", p) }
+
+		stored, err := repo.GetTaskById(int(id(0)))
+		require.NoError(t, err)
+		assert.Nil(t, stored.Deadline)
+
+		// Tasks without a deadline sort last, and first in descending order
+		assert.Equal(t, [][]uint{{id(3)}, {id(1)}, {id(0)}, {id(2)}},
+			allPages(t, entity.PageRequest{Limit: 1, Sort: "deadline"}, getAll))
+		assert.Equal(t, [][]uint{{id(2), id(0), id(1)}, {id(3)}},
+			allPages(t, entity.PageRequest{Limit: 3, Sort: "deadline", Desc: true}, getAll))
+
+		// A missing deadline only matches deadline:none
+		assert.Equal(t, []uint{id(0), id(2)}, queryIDs(t, repo, "deadline:none"))
+		assert.Equal(t, []uint{id(1), id(3)}, queryIDs(t, repo, "NOT deadline:none"))
+		assert.Equal(t, []uint{id(3)}, queryIDs(t, repo, "deadline<2026-11-03"))
+		assert.Equal(t, []uint{id(0), id(1), id(2)}, queryIDs(t, repo, "NOT deadline<2026-11-03"))
+
+		// A deadline can be taken away again
+		var none *time.Time
+		_, err = repo.PatchTask(int(id(3)), 0, map[string]interface{}{"deadline": none})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{id(0), id(2), id(3)}, queryIDs(t, repo, "deadline:none"))
+	})
+
+	t.Run("QueryTasksByLabel", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "a", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "b", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "c", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "d", Deadline: due(contractDay), Priority: "high"},
+		)
+		labels := seedLabels(t, repo, "work", "urgent", "home")
+		require.NoError(t, repo.AttachLabel(tasks[0].ID, labels[0].ID))
+		require.NoError(t, repo.AttachLabel(tasks[0].ID, labels[1].ID))
+		require.NoError(t, repo.AttachLabel(tasks[1].ID, labels[1].ID))
+		require.NoError(t, repo.AttachLabel(tasks[2].ID, labels[0].ID))
+		require.NoError(t, repo.AttachLabel(tasks[3].ID, labels[0].ID))
+		require.NoError(t, repo.AttachLabel(tasks[3].ID, labels[1].ID))
+		require.NoError(t, repo.DeleteTask(int(tasks[3].ID), 0))
+
+		assert.Equal(t, []uint{tasks[0].ID, tasks[2].ID}, queryIDs(t, repo, "label:work"))
+		assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID}, queryIDs(t, repo, "label:work OR tag:urgent"))
+		assert.Equal(t, []uint{tasks[0].ID}, queryIDs(t, repo, "label:work label:urgent label:work"))
+		assert.Equal(t, []uint{tasks[1].ID}, queryIDs(t, repo, "NOT label:work"))
+		assert.Empty(t, queryIDs(t, repo, "label:work AND label:missing"))
+		assert.Empty(t, queryIDs(t, repo, "label:home"))
+	})
+
+	t.Run("QueryTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{ListID: 1, Name: "Write report", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{ListID: 1, Name: "Review 100% of the REPORT", Deadline: due(contractDay.Add(36 * time.Hour)), Priority: "less", Status: entity.StatusDone},
+			entity.Task{ListID: 2, Name: "report_draft", Deadline: due(contractDay.Add(-time.Hour)), Priority: "medium", ParentID: 1},
+			entity.Task{Name: "Deploy", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "high", Status: entity.StatusInProgress},
+		)
+		id := func(i int) uint { return tasks[i].ID }
+
+		assert.Equal(t, []uint{id(0), id(1), id(2)}, queryIDs(t, repo, `name~report`))
+		assert.Equal(t, []uint{id(1)}, queryIDs(t, repo, `name~"100%"`))
+		assert.Equal(t, []uint{id(1)}, queryIDs(t, repo, `name~"%"`))
+		assert.Equal(t, []uint{id(2)}, queryIDs(t, repo, `name~"_"`))
+		assert.Equal(t, []uint{id(0)}, queryIDs(t, repo, `name:"write REPORT"`))
+		assert.Equal(t, []uint{id(0), id(3)}, queryIDs(t, repo, `priority:high`))
+		assert.Equal(t, []uint{id(3)}, queryIDs(t, repo, `status:in_progress`))
+		assert.Equal(t, []uint{id(0), id(1)}, queryIDs(t, repo, `list:1`))
+		assert.Equal(t, []uint{id(3)}, queryIDs(t, repo, `list:0`))
+		assert.Equal(t, []uint{id(2)}, queryIDs(t, repo, `parent:1`))
+
+		// A date matches the whole day, timestamps and comparisons are exact
+		assert.Equal(t, []uint{id(0), id(2)}, queryIDs(t, repo, `deadline:2026-11-02`))
+		assert.Equal(t, []uint{id(0)}, queryIDs(t, repo, `deadline:2026-11-02T09:00:00Z`))
+		assert.Equal(t, []uint{id(2)}, queryIDs(t, repo, `deadline<2026-11-02T09:00:00Z`))
+		assert.Equal(t, []uint{id(0), id(2)}, queryIDs(t, repo, `deadline<=2026-11-02T09:00:00Z`))
+		assert.Equal(t, []uint{id(1), id(3)}, queryIDs(t, repo, `deadline>2026-11-02T09:00:00Z`))
+		assert.Equal(t, []uint{id(0), id(1), id(2), id(3)}, queryIDs(t, repo, `deadline>=2026-11-02`))
+
+		// Comparisons with a date take in or leave out the whole day
+		assert.Empty(t, queryIDs(t, repo, `deadline<2026-11-02`))
+		assert.Equal(t, []uint{id(0), id(2)}, queryIDs(t, repo, `deadline<=2026-11-02`))
+		assert.Equal(t, []uint{id(1), id(3)}, queryIDs(t, repo, `deadline>2026-11-02`))
+		assert.Equal(t, []uint{id(1), id(3)}, queryIDs(t, repo, `deadline>=2026-11-03`))
+		assert.Equal(t, []uint{id(0), id(1), id(2), id(3)}, queryIDs(t, repo, `deadline<=2026-11-03`))
+
+		// Combinations
+		assert.Equal(t, []uint{id(0), id(2)}, queryIDs(t, repo, `name~report AND deadline<2026-11-03 AND NOT status:done`))
+		assert.Equal(t, []uint{id(0), id(2), id(3)}, queryIDs(t, repo, `(priority:high OR list:2) AND NOT status:done`))
+		assert.Equal(t, []uint{id(0), id(1), id(3)}, queryIDs(t, repo, `priority:high OR list:2 AND status:done OR list:1`))
+	})
+
+	t.Run("Labels", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "a", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "b", Deadline: due(contractDay), Priority: "less"},
+		)
+		labels := seedLabels(t, repo, "work", "home")
+
+		found, err := repo.GetLabelByName("home")
+		require.NoError(t, err)
+		assert.Equal(t, labels[1], found)
+		_, err = repo.GetLabelByName("missing")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		labels[1].Color = "#00ff00"
+		require.NoError(t, repo.UpdateLabel(&labels[1]))
+		found, err = repo.GetLabelById(int(labels[1].ID))
+		require.NoError(t, err)
+		assert.Equal(t, "#00ff00", found.Color)
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateLabel(&entity.Label{ID: 99, Name: "x"}))
+
+		all, err := repo.GetAllLabels()
+		require.NoError(t, err)
+		assert.Equal(t, []entity.Label{labels[1], labels[0]}, all)
+
+		// Attaching twice is a no-op and detaching a missing label is not found
+		require.NoError(t, repo.AttachLabel(tasks[0].ID, labels[0].ID))
+		require.NoError(t, repo.AttachLabel(tasks[0].ID, labels[0].ID))
+		require.NoError(t, repo.AttachLabel(tasks[0].ID, labels[1].ID))
+		require.NoError(t, repo.AttachLabel(tasks[1].ID, labels[0].ID))
+		carried, err := repo.GetTaskLabels(tasks[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []entity.Label{labels[1], labels[0]}, carried)
+
+		byTask, err := repo.GetLabelsOfTasks([]uint{tasks[0].ID, tasks[1].ID, 99})
+		require.NoError(t, err)
+		assert.Equal(t, map[uint][]entity.Label{
+			tasks[0].ID: {labels[1], labels[0]},
+			tasks[1].ID: {labels[0]},
+		}, byTask)
+
+		require.NoError(t, repo.DetachLabel(tasks[0].ID, labels[1].ID))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DetachLabel(tasks[0].ID, labels[1].ID))
+
+		// Deleting a label takes it off every task
+		require.NoError(t, repo.DeleteLabel(int(labels[0].ID)))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteLabel(int(labels[0].ID)))
+		carried, err = repo.GetTaskLabels(tasks[1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, carried)
+
+		// Purging a task removes its labels
+		require.NoError(t, repo.AttachLabel(tasks[1].ID, labels[1].ID))
+		require.NoError(t, repo.DeleteTask(int(tasks[1].ID), 0))
+		require.NoError(t, repo.PurgeTask(int(tasks[1].ID)))
+		carried, err = repo.GetTaskLabels(tasks[1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, carried)
+	})
+
+	t.Run("CountTasksByStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "a", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "b", Deadline: due(contractDay), Priority: "high", Status: entity.StatusDone},
+			entity.Task{Name: "c", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "d", Priority: "high", Status: entity.StatusBlocked},
+			entity.Task{Name: "e", Deadline: due(contractDay), Priority: "less"},
+		)
+		require.NoError(t, repo.DeleteTask(int(tasks[2].ID), 0))
+
+		filter, err := query.Parse("priority:high")
+		require.NoError(t, err)
+		counts, err := repo.CountTasksByStatus(filter)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{entity.StatusTodo: 1, entity.StatusDone: 1, entity.StatusBlocked: 1}, counts)
+
+		filter, err = query.Parse("name:missing")
+		require.NoError(t, err)
+		counts, err = repo.CountTasksByStatus(filter)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("Views", func(t *testing.T) {
+		repo := newRepo(t)
+		overdue := entity.View{Name: "Overdue high priority", Query: "deadline<today priority:high"}
+		week := entity.View{Name: "Due this week", Query: "deadline>=today deadline<today+7d"}
+		require.NoError(t, repo.CreateView(&overdue))
+		require.NoError(t, repo.CreateView(&week))
+		assert.NotEmpty(t, overdue.ID)
+		assert.NotEqual(t, overdue.ID, week.ID)
+
+		id, err := strconv.Atoi(overdue.ID)
+		require.NoError(t, err)
+		found, err := repo.GetViewById(id)
+		require.NoError(t, err)
+		assert.Equal(t, overdue, found)
+		found, err = repo.GetViewByName("Due this week")
+		require.NoError(t, err)
+		assert.Equal(t, week, found)
+		_, err = repo.GetViewByName("missing")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		all, err := repo.GetAllViews()
+		require.NoError(t, err)
+		assert.Equal(t, []entity.View{week, overdue}, all)
+
+		overdue.Query = "deadline<today"
+		require.NoError(t, repo.UpdateView(&overdue))
+		require.NoError(t, repo.UpdateView(&overdue), "an unchanged view still exists")
+		found, err = repo.GetViewById(id)
+		require.NoError(t, err)
+		assert.Equal(t, "deadline<today", found.Query)
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateView(&entity.View{ID: "99", Name: "x", Query: "status:done"}))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateView(&entity.View{ID: "today", Name: "x", Query: "status:done"}))
+
+		require.NoError(t, repo.DeleteView(id))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteView(id))
+		_, err = repo.GetViewById(id)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("SearchTasksByName", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "Write Report", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "report review", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "Deploy", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "rePort_2", Deadline: due(contractDay), Priority: "less"},
+		)
+
+		// Substring matches ignore case
+		result, err := repo.SearchTasksByName("report", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID, tasks[3].ID}, taskIDs(result))
+
+		// _ matches any single character like in LIKE
+		result, err = repo.SearchTasksByName("p_oy", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[2].ID}, taskIDs(result))
+
+		result, err = repo.SearchTasksByName("", page)
+		require.NoError(t, err)
+		assert.Len(t, result.Tasks, 4)
+
+		result, err = repo.SearchTasksByName("missing", page)
+		require.NoError(t, err)
+		assert.Empty(t, result.Tasks)
+	})
+
+	t.Run("QueryTasksByDeadlineRange", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "before", Deadline: due(contractDay.Add(-time.Second)), Priority: "less"},
+			entity.Task{Name: "start", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "middle", Deadline: due(contractDay.Add(12 * time.Hour)), Priority: "less"},
+			entity.Task{Name: "end", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "less"},
+			entity.Task{Name: "after", Deadline: due(contractDay.Add(24*time.Hour + time.Second)), Priority: "less"},
+		)
+
+		// Both bounds are included
+		assert.Equal(t, []uint{tasks[1].ID, tasks[2].ID, tasks[3].ID},
+			queryIDs(t, repo, "deadline>=2026-11-02T09:00:00Z AND deadline<=2026-11-03T09:00:00Z"))
+	})
+
+	t.Run("UpdateTask", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo, entity.Task{Name: "a", Deadline: due(contractDay), Priority: "less", Status: entity.StatusInProgress})
+
+		update := entity.Task{ID: tasks[0].ID, ListID: 4, Name: "b", Deadline: due(contractDay.Add(time.Hour)), Priority: "high", Version: 1}
+		require.NoError(t, repo.UpdateTask(&update))
+		assert.Equal(t, uint(2), update.Version)
+		assert.Equal(t, entity.StatusInProgress, update.Status, "the update returns the stored task")
+		assert.True(t, tasks[0].CreatedAt.Equal(update.CreatedAt))
+		assert.False(t, update.UpdatedAt.Before(tasks[0].UpdatedAt))
+
+		stored, err := repo.GetTaskById(int(tasks[0].ID))
+		require.NoError(t, err)
+		assert.Equal(t, "b", stored.Name)
+		assert.Equal(t, uint(4), stored.ListID)
+		assert.Equal(t, "high", stored.Priority)
+		assert.True(t, contractDay.Add(time.Hour).Equal(*stored.Deadline))
+		assert.Equal(t, entity.StatusInProgress, stored.Status, "the status is not touched")
+		assert.Equal(t, uint(2), stored.Version)
+
+		// A stale version conflicts, a missing task is not inserted
+		update.Version = 1
+		assert.Equal(t, ErrVersionConflict, repo.UpdateTask(&update))
+		missing := entity.Task{ID: tasks[0].ID + 100, Name: "c", Deadline: due(contractDay), Priority: "less"}
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateTask(&missing))
+	})
+
+	t.Run("PatchTask", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo, entity.Task{ListID: 2, Name: "a", Deadline: due(contractDay), Priority: "less"})
+		id := int(tasks[0].ID)
+
+		patched, err := repo.PatchTask(id, 0, map[string]interface{}{"name": "b"})
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), patched.Version)
+		assert.Equal(t, uint(2), patched.ListID)
+
+		patched, err = repo.PatchTask(id, 2, map[string]interface{}{"list_id": 0})
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), patched.Version)
+
+		stored, err := repo.GetTaskById(id)
+		require.NoError(t, err)
+		assert.Equal(t, "b", stored.Name)
+		assert.Equal(t, uint(0), stored.ListID)
+		assert.Equal(t, "less", stored.Priority)
+
+		_, err = repo.PatchTask(id, 2, map[string]interface{}{"name": "c"})
+		assert.Equal(t, ErrVersionConflict, err)
+		_, err = repo.PatchTask(id+100, 0, map[string]interface{}{"name": "c"})
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("UpdateTaskStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo, entity.Task{Name: "a", Deadline: due(contractDay), Priority: "less"})
+		id := int(tasks[0].ID)
+		completedAt := contractDay.Add(time.Hour)
+
+		updated, err := repo.UpdateTaskStatus(id, 1, entity.StatusDone, &completedAt)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), updated.Version)
+		assert.Equal(t, entity.StatusDone, updated.Status)
+
+		stored, err := repo.GetTaskById(id)
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusDone, stored.Status)
+		require.NotNil(t, stored.CompletedAt)
+		assert.True(t, completedAt.Equal(*stored.CompletedAt))
+
+		updated, err = repo.UpdateTaskStatus(id, 2, entity.StatusTodo, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), updated.Version)
+		stored, err = repo.GetTaskById(id)
+		require.NoError(t, err)
+		assert.Nil(t, stored.CompletedAt)
+
+		_, err = repo.UpdateTaskStatus(id, 2, entity.StatusDone, &completedAt)
+		assert.Equal(t, ErrVersionConflict, err)
+	})
+
+	t.Run("DeleteAndTrash", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{ListID: 1, Name: "a", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{ListID: 1, Name: "b", Deadline: due(contractDay), Priority: "less"},
+		)
+		id := int(tasks[0].ID)
+
+		assert.Equal(t, ErrVersionConflict, repo.DeleteTask(id, 5))
+		require.NoError(t, repo.DeleteTask(id, 1))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteTask(id, 0))
+
+		// Trashed tasks are hidden from every other query and cannot be changed
+		_, err := repo.GetTaskById(id)
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		all, err := repo.GetAllTasks("", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[1].ID}, taskIDs(all))
+		listTasks, err := repo.GetTasksByListId(1, page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[1].ID}, taskIDs(listTasks))
+		_, err = repo.PatchTask(id, 0, map[string]interface{}{"name": "c"})
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		trash, err := repo.GetTrash(page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID}, taskIDs(trash))
+		assert.NotNil(t, trash.Tasks[0].DeletedAt)
+
+		// Restoring brings the task back with a new version
+		require.NoError(t, repo.RestoreTask(id))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.RestoreTask(id))
+		stored, err := repo.GetTaskById(id)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), stored.Version)
+		assert.Nil(t, stored.DeletedAt)
+
+		// Only trashed tasks can be purged
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.PurgeTask(id))
+		require.NoError(t, repo.DeleteTask(id, 0))
+		require.NoError(t, repo.PurgeTask(id))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.RestoreTask(id))
+		trash, err = repo.GetTrash(page)
+		require.NoError(t, err)
+		assert.Empty(t, trash.Tasks)
+	})
+
+	t.Run("PurgeTrash", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "a", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "b", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "c", Deadline: due(contractDay), Priority: "less"},
+		)
+		require.NoError(t, repo.DeleteTask(int(tasks[0].ID), 0))
+		require.NoError(t, repo.DeleteTask(int(tasks[1].ID), 0))
+
+		purged, err := repo.PurgeTrash(time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = repo.PurgeTrash(time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		all, err := repo.GetAllTasks("", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[2].ID}, taskIDs(all))
+	})
+
+	t.Run("GetRecurringTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "standup", Deadline: due(contractDay), Priority: "less", Recurrence: "FREQ=DAILY"},
+			entity.Task{Name: "once", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{Name: "later", Deadline: due(contractDay.Add(48 * time.Hour)), Priority: "less", Recurrence: "FREQ=WEEKLY"},
+			entity.Task{Name: "stopped", Deadline: due(contractDay), Priority: "less", Recurrence: "FREQ=DAILY", Status: entity.StatusCancelled},
+			entity.Task{Name: "trashed", Deadline: due(contractDay), Priority: "less", Recurrence: "FREQ=DAILY"},
+		)
+		require.NoError(t, repo.DeleteTask(int(tasks[4].ID), 0))
+
+		recurring, err := repo.GetRecurringTasks(contractDay.Add(24 * time.Hour))
+		require.NoError(t, err)
+		if assert.Len(t, recurring, 1) {
+			assert.Equal(t, tasks[0].ID, recurring[0].ID)
+			assert.Equal(t, "FREQ=DAILY", recurring[0].Recurrence)
+		}
+
+		// The rule is replaced by UpdateTask and can be cleared by PatchTask
+		update := entity.Task{ID: tasks[2].ID, Name: "later", Deadline: due(contractDay), Priority: "less", Recurrence: "FREQ=MONTHLY"}
+		require.NoError(t, repo.UpdateTask(&update))
+		_, err = repo.PatchTask(int(tasks[0].ID), 0, map[string]interface{}{"recurrence": ""})
+		require.NoError(t, err)
+
+		recurring, err = repo.GetRecurringTasks(contractDay.Add(24 * time.Hour))
+		require.NoError(t, err)
+		if assert.Len(t, recurring, 1) {
+			assert.Equal(t, tasks[2].ID, recurring[0].ID)
+			assert.Equal(t, "FREQ=MONTHLY", recurring[0].Recurrence)
+		}
+	})
+
+	t.Run("GetSubtasks", func(t *testing.T) {
+		repo := newRepo(t)
+		roots := seedTasks(t, repo,
+			entity.Task{Name: "release", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "launch", Deadline: due(contractDay), Priority: "high"},
+		)
+		subtasks := seedTasks(t, repo,
+			entity.Task{ParentID: roots[1].ID, Name: "announce", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{ParentID: roots[0].ID, Name: "changelog", Deadline: due(contractDay), Priority: "less"},
+			entity.Task{ParentID: roots[0].ID, Name: "tag", Deadline: due(contractDay), Priority: "less"},
+		)
+		require.NoError(t, repo.DeleteTask(int(subtasks[2].ID), 0))
+
+		found, err := repo.GetSubtasks([]uint{roots[0].ID, roots[1].ID})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{subtasks[0].ID, subtasks[1].ID}, taskIDs(entity.TaskPage{Tasks: found}))
+		assert.Equal(t, roots[1].ID, found[0].ParentID)
+
+		// A subtask is moved to the top level by clearing its parent
+		_, err = repo.PatchTask(int(subtasks[1].ID), 0, map[string]interface{}{"parent_id": 0})
+		require.NoError(t, err)
+		found, err = repo.GetSubtasks([]uint{roots[0].ID})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("Dependencies", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "design", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "build", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "ship", Deadline: due(contractDay), Priority: "high"},
+		)
+		edges := []entity.Dependency{
+			{TaskID: tasks[2].ID, BlockerID: tasks[1].ID},
+			{TaskID: tasks[1].ID, BlockerID: tasks[0].ID},
+			{TaskID: tasks[2].ID, BlockerID: tasks[0].ID},
+		}
+		for _, edge := range edges {
+			require.NoError(t, repo.AddDependency(edge))
+		}
+		// Adding a dependency twice keeps one edge
+		require.NoError(t, repo.AddDependency(edges[0]))
+
+		found, err := repo.GetDependencies([]uint{tasks[1].ID, tasks[2].ID})
+		require.NoError(t, err)
+		assert.Equal(t, []entity.Dependency{edges[1], edges[2], edges[0]}, found)
+
+		require.NoError(t, repo.RemoveDependency(edges[2]))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.RemoveDependency(edges[2]))
+
+		// Purging a task removes the edges on both of its ends
+		require.NoError(t, repo.DeleteTask(int(tasks[1].ID), 0))
+		require.NoError(t, repo.PurgeTask(int(tasks[1].ID)))
+		found, err = repo.GetDependencies([]uint{tasks[0].ID, tasks[1].ID, tasks[2].ID})
+		require.NoError(t, err)
+		assert.Empty(t, found)
+	})
+
+	t.Run("ListTasks", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{ListID: 1, Name: "Write report", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{ListID: 2, Name: "Write code", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{ListID: 1, Name: "Review code", Deadline: due(contractDay.Add(48 * time.Hour)), Priority: "less"},
+			entity.Task{ListID: 1, Name: "Write tests", Deadline: due(contractDay.Add(24 * time.Hour)), Priority: "high"},
+		)
+
+		result, err := repo.GetTasksByListId(1, page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID, tasks[2].ID, tasks[3].ID}, taskIDs(result))
+
+		labels := seedLabels(t, repo, "writing")
+		for _, task := range tasks {
+			if task.Name != "Review code" {
+				require.NoError(t, repo.AttachLabel(task.ID, labels[0].ID))
+			}
+		}
+
+		assert.Equal(t, []uint{tasks[0].ID, tasks[3].ID}, queryIDs(t, repo, "list:1 AND label:writing"))
+
+		result, err = repo.SearchListTasksByName(1, "write", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID, tasks[3].ID}, taskIDs(result))
+
+		assert.Equal(t, []uint{tasks[0].ID, tasks[3].ID},
+			queryIDs(t, repo, "list:1 AND deadline>=2026-11-02 AND deadline<=2026-11-03T09:00:00Z"))
+
+		result, err = repo.GetTasksByListId(3, page)
+		require.NoError(t, err)
+		assert.Empty(t, result.Tasks)
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		repo := newRepo(t)
+		backend := entity.List{Name: "Backend", Description: "Backend team backlog"}
+		require.NoError(t, repo.CreateList(&backend))
+		frontend := entity.List{Name: "Frontend"}
+		require.NoError(t, repo.CreateList(&frontend))
+		assert.NotEqual(t, backend.ID, frontend.ID)
+
+		lists, err := repo.GetAllLists()
+		require.NoError(t, err)
+		assert.Equal(t, []entity.List{backend, frontend}, lists)
+
+		backend.Name = "Platform"
+		require.NoError(t, repo.UpdateList(&backend))
+		found, err := repo.GetListById(int(backend.ID))
+		require.NoError(t, err)
+		assert.Equal(t, backend, found)
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateList(&entity.List{ID: 99, Name: "Missing"}))
+
+		// Tasks in the trash do not count
+		tasks := seedTasks(t, repo,
+			entity.Task{ListID: backend.ID, Name: "Write report", Priority: "high"},
+			entity.Task{ListID: backend.ID, Name: "Write code", Priority: "high"},
+			entity.Task{ListID: frontend.ID, Name: "Review code", Priority: "less"},
+		)
+		require.NoError(t, repo.DeleteTask(int(tasks[1].ID), 0))
+		count, err := repo.CountTasks(int(backend.ID))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		require.NoError(t, repo.DeleteList(int(frontend.ID)))
+		_, err = repo.GetListById(int(frontend.ID))
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteList(int(frontend.ID)))
+	})
+
+	t.Run("ListFeeds", func(t *testing.T) {
+		repo := newRepo(t)
+		backend := entity.List{Name: "Backend"}
+		require.NoError(t, repo.CreateList(&backend))
+		frontend := entity.List{Name: "Frontend"}
+		require.NoError(t, repo.CreateList(&frontend))
+
+		require.NoError(t, repo.SaveListFeed(entity.ListFeed{ListID: backend.ID, TokenHash: "hash1"}))
+		require.NoError(t, repo.SaveListFeed(entity.ListFeed{ListID: frontend.ID, TokenHash: "hash2"}))
+		feed, err := repo.GetListFeed("hash1")
+		require.NoError(t, err)
+		assert.Equal(t, entity.ListFeed{ListID: backend.ID, TokenHash: "hash1"}, feed)
+
+		// A new token replaces the old one
+		require.NoError(t, repo.SaveListFeed(entity.ListFeed{ListID: backend.ID, TokenHash: "hash3"}))
+		_, err = repo.GetListFeed("hash1")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		feed, err = repo.GetListFeed("hash3")
+		require.NoError(t, err)
+		assert.Equal(t, backend.ID, feed.ListID)
+		assert.Error(t, repo.SaveListFeed(entity.ListFeed{ListID: backend.ID, TokenHash: "hash2"}))
+
+		require.NoError(t, repo.DeleteListFeed(int(backend.ID)))
+		_, err = repo.GetListFeed("hash3")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteListFeed(int(backend.ID)))
+
+		// Deleting a list deletes its feed
+		require.NoError(t, repo.DeleteList(int(frontend.ID)))
+		_, err = repo.GetListFeed("hash2")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		repo := newRepo(t)
+		tasks := seedTasks(t, repo,
+			entity.Task{Name: "Write report", Deadline: due(contractDay), Priority: "high"},
+			entity.Task{Name: "Write code", Deadline: due(contractDay), Priority: "high"},
+		)
+		failed := errors.New("failed")
+
+		// A failing transaction leaves nothing behind
+		err := repo.Transaction(func(tasksTx IRepo, listsTx IListRepo, labelsTx ILabelRepo) error {
+			require.NoError(t, tasksTx.CreateTask(&entity.Task{Name: "Write tests", Priority: "less", Status: entity.StatusTodo}))
+			_, err := tasksTx.PatchTask(int(tasks[0].ID), 0, map[string]interface{}{"name": "Write summary"})
+			require.NoError(t, err)
+			require.NoError(t, tasksTx.DeleteTask(int(tasks[1].ID), 0))
+			require.NoError(t, tasksTx.AddDependency(entity.Dependency{TaskID: tasks[0].ID, BlockerID: tasks[1].ID}))
+			require.NoError(t, listsTx.CreateList(&entity.List{Name: "Backend"}))
+			require.NoError(t, labelsTx.CreateLabel(&entity.Label{Name: "backend", Color: entity.DefaultLabelColor}))
+			require.NoError(t, labelsTx.AttachLabel(tasks[0].ID, 1))
+			return failed
+		})
+		assert.Equal(t, failed, err)
+		result, err := repo.GetAllTasks("", page)
+		require.NoError(t, err)
+		assert.Equal(t, tasks, result.Tasks)
+		dependencies, err := repo.GetDependencies([]uint{tasks[0].ID})
+		require.NoError(t, err)
+		assert.Empty(t, dependencies)
+		lists, err := repo.GetAllLists()
+		require.NoError(t, err)
+		assert.Empty(t, lists)
+		allLabels, err := repo.GetAllLabels()
+		require.NoError(t, err)
+		assert.Empty(t, allLabels)
+		carried, err := repo.GetTaskLabels(tasks[0].ID)
+		require.NoError(t, err)
+		assert.Empty(t, carried)
+
+		// A nested transaction that fails only undoes its own changes
+		var created entity.Task
+		err = repo.Transaction(func(tasksTx IRepo, listsTx IListRepo, labelsTx ILabelRepo) error {
+			created = entity.Task{Name: "Write tests", Priority: "less", Status: entity.StatusTodo}
+			require.NoError(t, tasksTx.CreateTask(&created))
+			nested := tasksTx.Transaction(func(tasksTx IRepo, listsTx IListRepo, labelsTx ILabelRepo) error {
+				require.NoError(t, tasksTx.DeleteTask(int(tasks[0].ID), 0))
+				return failed
+			})
+			assert.Equal(t, failed, nested)
+			return nil
+		})
+		require.NoError(t, err)
+		result, err = repo.GetAllTasks("", page)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID, created.ID}, taskIDs(result))
+	})
+
+	t.Run("IdempotencyRecords", func(t *testing.T) {
+		repo := newRepo(t)
+		claim := entity.IdempotencyRecord{Key: "retry-1", RequestHash: "abc", ExpiresAt: contractDay}
+		require.NoError(t, repo.CreateIdempotencyRecord(&claim))
+		assert.Equal(t, gorm.ErrDuplicatedKey, repo.CreateIdempotencyRecord(&entity.IdempotencyRecord{Key: "retry-1", RequestHash: "def", ExpiresAt: contractDay}))
+
+		found, err := repo.GetIdempotencyRecord("retry-1")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", found.RequestHash)
+		assert.Zero(t, found.Status)
+		assert.Empty(t, found.Body)
+		assert.True(t, contractDay.Equal(found.ExpiresAt))
+
+		// The response is stored with the key, the request hash stays
+		response := entity.IdempotencyRecord{
+			Key:         "retry-1",
+			RequestHash: "abc",
+			Status:      201,
+			Header:      map[string]string{"Content-Type": "application/json; charset=utf-8", "Location": "/tasks/7"},
+			Body:        []byte(`{"id":7}`),
+			ExpiresAt:   contractDay.Add(24 * time.Hour),
+		}
+		require.NoError(t, repo.UpdateIdempotencyRecord(&response))
+		found, err = repo.GetIdempotencyRecord("retry-1")
+		require.NoError(t, err)
+		assert.Equal(t, 201, found.Status)
+		assert.Equal(t, response.Header, found.Header)
+		assert.Equal(t, response.Body, found.Body)
+		assert.True(t, response.ExpiresAt.Equal(found.ExpiresAt))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateIdempotencyRecord(&entity.IdempotencyRecord{Key: "retry-2", ExpiresAt: contractDay}))
+
+		require.NoError(t, repo.CreateIdempotencyRecord(&entity.IdempotencyRecord{Key: "retry-2", RequestHash: "def", ExpiresAt: contractDay.Add(time.Hour)}))
+		purged, err := repo.PurgeIdempotencyRecords(contractDay.Add(2 * time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), purged)
+		_, err = repo.GetIdempotencyRecord("retry-2")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+		// Only an expired key is deleted with its expiry
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteExpiredIdempotencyRecord("retry-1", response.ExpiresAt))
+		require.NoError(t, repo.DeleteExpiredIdempotencyRecord("retry-1", response.ExpiresAt.Add(time.Second)))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteExpiredIdempotencyRecord("retry-1", response.ExpiresAt.Add(time.Second)))
+
+		require.NoError(t, repo.CreateIdempotencyRecord(&response))
+		require.NoError(t, repo.DeleteIdempotencyRecord("retry-1"))
+		assert.Equal(t, gorm.ErrRecordNotFound, repo.DeleteIdempotencyRecord("retry-1"))
+		_, err = repo.GetIdempotencyRecord("retry-1")
+		assert.Equal(t, gorm.ErrRecordNotFound, err)
+	})
+}
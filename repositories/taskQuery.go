@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"log"
+	"strings"
+	"todo-lists/entity"
+	"todo-lists/models"
+	"todo-lists/query"
+)
+
+// labelSubquery selects the tasks that carry the label with the given name
+const labelSubquery = "id IN (SELECT task_labels.task_id FROM task_labels JOIN labels ON labels.id = task_labels.label_id WHERE labels.name = ?)"
+
+// likeEscaper escapes the LIKE wildcards of a keyword, with ! as the escape character every backend accepts
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// QueryTasks method retrieves a page of the tasks that match a query expression
+func (r *TaskRepository) QueryTasks(filter query.Expr, page entity.PageRequest) (entity.TaskPage, error) {
+	condition, args := whereClause(filter)
+	result, err := r.findPage(r.DB.Model(&models.Task{}).Where(condition, args...), page)
+	if err != nil {
+		log.Println("Error querying tasks:", err)
+		return entity.TaskPage{}, err
+	}
+	return result, nil
+}
+
+// CountTasksByStatus method counts the tasks that match a query expression, by status
+func (r *TaskRepository) CountTasksByStatus(filter query.Expr) (map[string]int64, error) {
+	condition, args := whereClause(filter)
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.DB.Model(&models.Task{}).Select("status, COUNT(*) AS count").Where(condition, args...).
+		Group("status").Scan(&rows).Error
+	if err != nil {
+		log.Println("Error counting tasks:", err)
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// whereClause translates a query expression into a SQL condition. Values are only ever passed as arguments,
+// and the columns come from the fixed set of fields the query package accepts.
+func whereClause(filter query.Expr) (string, []interface{}) {
+	switch e := filter.(type) {
+	case query.And:
+		return combine(e.Left, "AND", e.Right)
+	case query.Or:
+		return combine(e.Left, "OR", e.Right)
+	case query.Not:
+		// A comparison with a missing deadline is NULL rather than false, which NOT would keep as NULL
+		condition, args := whereClause(e.Expr)
+		return "(" + condition + ") IS NOT TRUE", args
+	case query.Term:
+		return termClause(e)
+	}
+	panic("unknown query expression")
+}
+
+// combine joins the conditions of two expressions with a boolean operator
+func combine(left query.Expr, op string, right query.Expr) (string, []interface{}) {
+	leftCondition, leftArgs := grouped(left)
+	rightCondition, rightArgs := grouped(right)
+	return leftCondition + " " + op + " " + rightCondition, append(leftArgs, rightArgs...)
+}
+
+// grouped translates an operand of AND or OR, in parentheses when it is an AND or OR itself
+func grouped(filter query.Expr) (string, []interface{}) {
+	condition, args := whereClause(filter)
+	switch filter.(type) {
+	case query.And, query.Or:
+		return "(" + condition + ")", args
+	}
+	return condition, args
+}
+
+// termClause translates a single term into a SQL condition
+func termClause(term query.Term) (string, []interface{}) {
+	switch term.Field {
+	case query.FieldName:
+		if term.Op == query.Contains {
+			return "LOWER(name) LIKE ? ESCAPE '!'", []interface{}{"%" + likeEscaper.Replace(strings.ToLower(term.Value)) + "%"}
+		}
+		return "LOWER(name) = ?", []interface{}{strings.ToLower(term.Value)}
+	case query.FieldLabel:
+		return labelSubquery, []interface{}{term.Value}
+	case query.FieldPriority:
+		return "priority = ?", []interface{}{term.Value}
+	case query.FieldStatus:
+		return "status = ?", []interface{}{term.Value}
+	case query.FieldList:
+		return "list_id = ?", []interface{}{term.ID}
+	case query.FieldParent:
+		return "parent_id = ?", []interface{}{term.ID}
+	case query.FieldDeadline:
+		if term.Value == query.None {
+			return "deadline IS NULL", nil
+		}
+		if !term.Until.IsZero() {
+			switch term.Op {
+			case query.Equal:
+				return "(deadline >= ? AND deadline < ?)", []interface{}{term.Time, term.Until}
+			case query.LessEqual:
+				return "deadline < ?", []interface{}{term.Until}
+			case query.Greater:
+				return "deadline >= ?", []interface{}{term.Until}
+			}
+		}
+		op := term.Op
+		if op == query.Equal {
+			op = "="
+		}
+		return "deadline " + op + " ?", []interface{}{term.Time}
+	}
+	panic("unknown query field " + term.Field)
+}
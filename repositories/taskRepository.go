@@ -7,43 +7,44 @@ import (
 	"todo-lists/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
 	DB *gorm.DB
 }
 
-// CreateTask saves a new task in the database
+// CreateTask saves a new task in the database and copies back what the database assigned: its ID,
+// version and timestamps
 func (r *TaskRepository) CreateTask(task *entity.Task) error {
-	newTask := &models.Task{
-		Name:     task.Name,
-		Deadline: task.Deadline,
-		Tag:      task.Tag,
-	}
+	newTask := toModelTask(*task)
+	// The ID, the timestamps and the first version are assigned here, never taken from the caller
+	newTask.ID = 0
+	newTask.Version = 1
+	newTask.CreatedAt = time.Time{}
+	newTask.UpdatedAt = time.Time{}
+	newTask.DeletedAt = gorm.DeletedAt{}
 
-	return r.DB.Create(newTask).Error
+	if err := r.DB.Create(&newTask).Error; err != nil {
+		return err
+	}
+	*task = toEntityTask(newTask)
+	return nil
 }
 
-// GetAllTasks fetches all tasks from the database
-func (r *TaskRepository) GetAllTasks() ([]entity.Task, error) {
-	var allTasks []models.Task
-	if err := r.DB.Find(&allTasks).Error; err != nil {
-		log.Println("Error fetching tasks:", err)
-		return nil, err
+// GetAllTasks fetches one page of tasks from the database, optionally only those in the given status
+func (r *TaskRepository) GetAllTasks(status string, page entity.PageRequest) (entity.TaskPage, error) {
+	query := r.DB.Model(&models.Task{})
+	if status != "" {
+		query = query.Where("status = ?", status)
 	}
 
-	// Convert allTasks to entity.Tasks
-	var entityTasks []entity.Task
-	for _, mTask := range allTasks {
-		entityTasks = append(entityTasks, entity.Task{
-			ID:       mTask.ID,
-			Name:     mTask.Name,
-			Deadline: mTask.Deadline,
-			Tag:      mTask.Tag,
-		})
+	result, err := r.findPage(query, page)
+	if err != nil {
+		log.Println("Error fetching tasks:", err)
+		return entity.TaskPage{}, err
 	}
-
-	return entityTasks, nil
+	return result, nil
 }
 
 // GetTaskById method retrieves a task by ID from the database
@@ -56,61 +57,272 @@ func (r *TaskRepository) GetTaskById(id int) (entity.Task, error) {
 		log.Println("Error fetching task:", err)
 		return entity.Task{}, err
 	}
-	e := entity.Task{
-		ID:       task.ID,
-		Name:     task.Name,
-		Deadline: task.Deadline,
-		Tag:      task.Tag,
+	return toEntityTask(task), nil
+}
+
+// GetTaskByUID method retrieves the task outside the trash with the given calendar UID, the first one by ID
+// when several carry it
+func (r *TaskRepository) GetTaskByUID(uid string) (entity.Task, error) {
+	var task models.Task
+	if err := r.DB.Where("uid = ?", uid).Order("id").First(&task).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching task:", err)
+		}
+		return entity.Task{}, err
 	}
-	return e, nil
+	return toEntityTask(task), nil
 }
 
-// GetTaskByTag method retrieves a task by tag name from the database
-func (r *TaskRepository) GetTasksByTag(tag string) ([]entity.Task, error) {
-	var tasks []models.Task
-	if err := r.DB.Where("tag = ?", tag).Find(&tasks).Error; err != nil {
-		log.Println("Error fetching tasks by tag:", err)
-		return nil, err
+// UpdateTask method replaces the editable fields of an existing task. The status is only changed through
+// UpdateTaskStatus, and a missing task yields gorm.ErrRecordNotFound instead of inserting a new row. A
+// non-zero task.Version must match the stored version; on success task is replaced by the stored task.
+func (r *TaskRepository) UpdateTask(task *entity.Task) error {
+	updated, err := r.PatchTask(int(task.ID), task.Version, taskColumns(*task))
+	if err != nil {
+		return err
 	}
-	// Convert allTasks to entity.Tasks
-	var entityTasks []entity.Task
-	for _, mTask := range tasks {
-		entityTasks = append(entityTasks, entity.Task{
-			ID:       mTask.ID,
-			Name:     mTask.Name,
-			Deadline: mTask.Deadline,
-			Tag:      mTask.Tag,
-		})
+	*task = updated
+	return nil
+}
+
+// PatchTask method updates only the given columns of an existing task and returns the task as stored
+// afterwards. When version is not zero the update only applies if the task is still at that version.
+func (r *TaskRepository) PatchTask(id int, version uint, fields map[string]interface{}) (entity.Task, error) {
+	var updated models.Task
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"version": gorm.Expr("version + 1")}
+		for column, value := range fields {
+			updates[column] = value
+		}
+
+		query := tx.Model(&models.Task{}).Where("id = ?", id)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			log.Println("Error updating task:", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrConflict(tx, id)
+		}
+
+		return tx.First(&updated, id).Error
+	})
+	if err != nil {
+		return entity.Task{}, err
 	}
-	return entityTasks, nil
+	return toEntityTask(updated), nil
 }
 
-// UpdateTask method updates a task in the database
-func (r *TaskRepository) UpdateTask(task *entity.Task) error {
-	return r.DB.Save(task).Error
+// UpdateTaskStatus method stores a new lifecycle state for a task and returns the task as stored afterwards
+func (r *TaskRepository) UpdateTaskStatus(id int, version uint, status string, completedAt *time.Time) (entity.Task, error) {
+	return r.PatchTask(id, version, map[string]interface{}{
+		"status":       status,
+		"completed_at": completedAt,
+	})
 }
 
-// SearchTasksByName method searches for tasks by keyword in their name
-func (r *TaskRepository) SearchTasksByName(keyword string) ([]entity.Task, error) {
-	var tasks []entity.Task
-	if err := r.DB.Where("name LIKE ?", "%"+keyword+"%").Find(&tasks).Error; err != nil {
+// SearchTasksByName method searches for a page of tasks by keyword in their name
+func (r *TaskRepository) SearchTasksByName(keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	result, err := r.searchPage(r.DB.Model(&models.Task{}), keyword, page)
+	if err != nil {
 		log.Println("Error searching tasks by keyword:", err)
+		return entity.TaskPage{}, err
+	}
+	return result, nil
+}
+
+// DeleteTask method moves a task to the trash by its ID. When version is not zero the task is only deleted
+// if it is still at that version.
+func (r *TaskRepository) DeleteTask(id int, version uint) error {
+	query := r.DB
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	result := query.Delete(&models.Task{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return missingOrConflict(r.DB, id)
+	}
+	return nil
+}
+
+// GetTrash method retrieves a page of the soft deleted tasks
+func (r *TaskRepository) GetTrash(page entity.PageRequest) (entity.TaskPage, error) {
+	result, err := r.findPage(r.DB.Unscoped().Model(&models.Task{}).Where("deleted_at IS NOT NULL"), page)
+	if err != nil {
+		log.Println("Error fetching trash:", err)
+		return entity.TaskPage{}, err
+	}
+	return result, nil
+}
+
+// RestoreTask method moves a task out of the trash, returning gorm.ErrRecordNotFound if it is not trashed
+func (r *TaskRepository) RestoreTask(id int) error {
+	result := r.DB.Unscoped().Model(&models.Task{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		log.Println("Error restoring task:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// PurgeTask method permanently deletes a trashed task with its dependencies and labels, returning gorm.ErrRecordNotFound
+// if it is not trashed
+func (r *TaskRepository) PurgeTask(id int) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Task{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("task_id = ? OR blocker_id = ?", id, id).Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("task_id = ?", id).Delete(&models.TaskLabel{}).Error
+	})
+}
+
+// PurgeTrash method permanently deletes the tasks trashed before the given time with their dependencies and labels, and
+// returns how many tasks were removed
+func (r *TaskRepository) PurgeTrash(before time.Time) (int64, error) {
+	var purged int64
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		trashed := tx.Unscoped().Model(&models.Task{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Where("task_id IN (?) OR blocker_id IN (?)", trashed, trashed).Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("task_id IN (?)", trashed).Delete(&models.TaskLabel{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Task{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Println("Error purging trash:", err)
+	}
+	return purged, err
+}
+
+// GetRecurringTasks method retrieves the open tasks with a recurrence rule that are due no later than before
+func (r *TaskRepository) GetRecurringTasks(before time.Time) ([]entity.Task, error) {
+	var mTasks []models.Task
+	err := r.DB.Where("recurrence <> '' AND deadline <= ? AND status NOT IN ?", before, []string{entity.StatusDone, entity.StatusCancelled}).
+		Order("id").Find(&mTasks).Error
+	if err != nil {
+		log.Println("Error fetching recurring tasks:", err)
 		return nil, err
 	}
-	return tasks, nil
+
+	return toEntityTasks(mTasks), nil
 }
 
-// FilterTasksByDeadline method retrieves tasks with deadlines within the specified range
-func (r *TaskRepository) FilterTasksByDeadline(start, end time.Time) ([]entity.Task, error) {
-	var tasks []entity.Task
-	if err := r.DB.Where("deadline BETWEEN ? AND ?", start, end).Find(&tasks).Error; err != nil {
+// GetSubtasks method retrieves the direct subtasks of the given tasks, ordered by ID
+func (r *TaskRepository) GetSubtasks(parentIds []uint) ([]entity.Task, error) {
+	var mTasks []models.Task
+	if err := r.DB.Where("parent_id IN ?", parentIds).Order("id").Find(&mTasks).Error; err != nil {
+		log.Println("Error fetching subtasks:", err)
 		return nil, err
 	}
-	return tasks, nil
+
+	return toEntityTasks(mTasks), nil
+}
+
+// AddDependency method stores that a task waits for a blocker. Adding a dependency that exists is a no-op.
+func (r *TaskRepository) AddDependency(dependency entity.Dependency) error {
+	row := toModelDependency(dependency)
+	if err := r.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		log.Println("Error adding dependency:", err)
+		return err
+	}
+	return nil
+}
+
+// RemoveDependency method deletes a dependency, returning gorm.ErrRecordNotFound if it does not exist
+func (r *TaskRepository) RemoveDependency(dependency entity.Dependency) error {
+	result := r.DB.Where("task_id = ? AND blocker_id = ?", dependency.TaskID, dependency.BlockerID).Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		log.Println("Error removing dependency:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-// DeleteTask method deletes a task by its ID
-func (r *TaskRepository) DeleteTask(id int) error {
-	result := r.DB.Delete(&entity.Task{}, id)
-	return result.Error
+// GetDependencies method retrieves the dependencies of the given tasks, ordered by task and blocker
+func (r *TaskRepository) GetDependencies(taskIds []uint) ([]entity.Dependency, error) {
+	var rows []models.TaskDependency
+	if err := r.DB.Where("task_id IN ?", taskIds).Order("task_id, blocker_id").Find(&rows).Error; err != nil {
+		log.Println("Error fetching dependencies:", err)
+		return nil, err
+	}
+
+	return toEntityDependencies(rows), nil
+}
+
+// GetTasksByListId method retrieves a page of the tasks that belong to a list
+func (r *TaskRepository) GetTasksByListId(listId int, page entity.PageRequest) (entity.TaskPage, error) {
+	result, err := r.findPage(r.DB.Model(&models.Task{}).Where("list_id = ?", listId), page)
+	if err != nil {
+		log.Println("Error fetching list tasks:", err)
+		return entity.TaskPage{}, err
+	}
+	return result, nil
+}
+
+// SearchListTasksByName method searches a page of the tasks of a list by keyword in their name
+func (r *TaskRepository) SearchListTasksByName(listId int, keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	result, err := r.searchPage(r.DB.Model(&models.Task{}).Where("list_id = ?", listId), keyword, page)
+	if err != nil {
+		log.Println("Error searching list tasks by keyword:", err)
+		return entity.TaskPage{}, err
+	}
+	return result, nil
+}
+
+// Transaction runs fn with task, list and label repositories that share one database transaction, committed when fn
+// returns nil and rolled back otherwise. Inside another transaction it rolls back to a savepoint, undoing only
+// what fn changed.
+func (r *TaskRepository) Transaction(fn func(tasks IRepo, lists IListRepo, labels ILabelRepo) error) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&TaskRepository{DB: tx}, &ListRepository{DB: tx}, &LabelRepository{DB: tx})
+	})
+}
+
+// searchPage finds a page of the tasks whose name matches keyword. On Postgres the words of the keyword are
+// matched with full-text search and results can be ranked by relevance; other backends match a substring.
+func (r *TaskRepository) searchPage(query *gorm.DB, keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	if r.DB.Dialector.Name() != "postgres" {
+		return r.findPage(query.Where("name LIKE ?", "%"+keyword+"%"), page)
+	}
+
+	query = query.Where("to_tsvector('english', name) @@ plainto_tsquery('english', ?)", keyword)
+	if page.Sort == entity.SortRank {
+		return r.findRankedPage(query, keyword, page)
+	}
+	return r.findPage(query, page)
+}
+
+// missingOrConflict explains why a conditional write matched no row: the task is gone, or its version moved on
+func missingOrConflict(db *gorm.DB, id int) error {
+	if err := db.Select("id").First(&models.Task{}, id).Error; err != nil {
+		return err
+	}
+	return ErrVersionConflict
 }
@@ -6,13 +6,18 @@ import (
 	"testing"
 	"time"
 	"todo-lists/entity"
+	"todo-lists/query"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// defaultPage is the page requested when a client sends no pagination parameters
+var defaultPage = entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
 func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	// Create a mock SQL connection
 	db, mock, err := sqlmock.New()
@@ -45,11 +50,16 @@ func TestCreateTask(t *testing.T) {
 	mock.ExpectCommit()
 
 	repo := &TaskRepository{DB: gormDB}
-	task := &entity.Task{Name: "Test Task", Deadline: time.Now(), Tag: "high"}
+	task := &entity.Task{Name: "Test Task", Deadline: due(time.Now()), Priority: "high"}
 
 	err := repo.CreateTask(task)
 	assert.NoError(t, err)
 
+	// The generated ID and the timestamps are copied back
+	assert.Equal(t, uint(1), task.ID)
+	assert.Equal(t, uint(1), task.Version)
+	assert.False(t, task.CreatedAt.IsZero())
+
 	// Ensure all expectations were met
 	err = mock.ExpectationsWereMet()
 	assert.NoError(t, err)
@@ -61,21 +71,22 @@ func TestGetAllTasks(t *testing.T) {
 
 	// Define the tasks to return on a successful query
 	tasks := []entity.Task{
-		{ID: 1, Name: "Task 1", Deadline: time.Now(), Tag: "high"},
-		{ID: 2, Name: "Task 2", Deadline: time.Now(), Tag: "medium"},
+		{ID: 1, Name: "Task 1", Deadline: due(time.Now()), Priority: "high"},
+		{ID: 2, Name: "Task 2", Deadline: due(time.Now()), Priority: "medium"},
 	}
 
 	// Mock the successful retrieval of tasks
 	mock.ExpectQuery("SELECT \\* FROM `tasks`").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "tag"}).
-			AddRow(tasks[0].ID, tasks[0].Name, tasks[0].Deadline, tasks[0].Tag).
-			AddRow(tasks[1].ID, tasks[1].Name, tasks[1].Deadline, tasks[1].Tag))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(tasks[0].ID, tasks[0].Name, tasks[0].Deadline, tasks[0].Priority).
+			AddRow(tasks[1].ID, tasks[1].Name, tasks[1].Deadline, tasks[1].Priority))
 
 	// Create the repository instance
 	repo := &TaskRepository{DB: gormDB}
 
 	// Call the GetAllTasks method for successful case
-	fetchedTasks, err := repo.GetAllTasks()
+	fetchedPage, err := repo.GetAllTasks("", defaultPage)
+	fetchedTasks := fetchedPage.Tasks
 	assert.NoError(t, err)
 	assert.Equal(t, len(tasks), len(fetchedTasks))
 	assert.Equal(t, tasks[0].Name, fetchedTasks[0].Name)
@@ -89,10 +100,10 @@ func TestGetAllTasks(t *testing.T) {
 	mock.ExpectQuery("SELECT \\* FROM `tasks`").WillReturnError(errors.New("db error"))
 
 	// Call the GetAllTasks method again for error case
-	fetchedTasks, err = repo.GetAllTasks()
+	fetchedPage, err = repo.GetAllTasks("", defaultPage)
 	assert.Error(t, err)
 	assert.Equal(t, "db error", err.Error()) // Check the specific error message
-	assert.Nil(t, fetchedTasks)              // Should return nil tasks on error
+	assert.Nil(t, fetchedPage.Tasks)         // Should return nil tasks on error
 
 	// Verify expectations were met again after error case
 	err = mock.ExpectationsWereMet()
@@ -110,9 +121,9 @@ func TestGetTaskById(t *testing.T) {
 	taskTag := "medium"
 
 	// Mock the retrieval of the task by ID (successful case)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? ORDER BY `tasks`.`id` LIMIT ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL ORDER BY `tasks`.`id` LIMIT ?")).
 		WithArgs(taskID, 1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "tag"}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
 			AddRow(taskID, taskName, taskDeadline, taskTag))
 
 	// Create the repository instance
@@ -128,7 +139,7 @@ func TestGetTaskById(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Test for task not found (error scenario)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? ORDER BY `tasks`.`id` LIMIT ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL ORDER BY `tasks`.`id` LIMIT ?")).
 		WithArgs(taskID, 1).
 		WillReturnRows(sqlmock.NewRows([]string{})) // No rows returned
 
@@ -141,7 +152,7 @@ func TestGetTaskById(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Test for other errors (error scenario)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? ORDER BY `tasks`.`id` LIMIT ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL ORDER BY `tasks`.`id` LIMIT ?")).
 		WithArgs(taskID, 1).
 		WillReturnError(errors.New("db error"))
 
@@ -155,24 +166,28 @@ func TestGetTaskById(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestGetTasksByTag(t *testing.T) {
+// labelQuery is the subquery that selects the tasks carrying a label
+const labelQuery = "id IN (SELECT task_labels.task_id FROM task_labels JOIN labels ON labels.id = task_labels.label_id WHERE labels.name = ?)"
+
+func TestQueryTasks(t *testing.T) {
 	gormDB, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// Mock the database to return tasks when querying by tag
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE tag = ?")).
-		WithArgs("high").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "tag"}).
+	filter, err := query.Parse(`(label:work OR tag:urgent) AND name~"100%_done" AND NOT status:done`)
+	assert.NoError(t, err)
+
+	// Values are passed as arguments, LIKE wildcards in them are escaped
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE ((("+labelQuery+" OR "+labelQuery+") AND LOWER(name) LIKE ? ESCAPE '!') AND (status = ?) IS NOT TRUE) AND `tasks`.`deleted_at` IS NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs("work", "urgent", "%100!%!_done%", "done", defaultPage.Limit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
 			AddRow(1, "Task 1", time.Now(), "high").
-			AddRow(2, "Task 2", time.Now(), "high")) // Add more tasks with the same tag as needed
+			AddRow(2, "Task 2", time.Now(), "high"))
 
 	repo := &TaskRepository{DB: gormDB}
 
-	// Fetch tasks by tag
-	tasks, err := repo.GetTasksByTag("high")
+	page, err := repo.QueryTasks(filter, defaultPage)
+	tasks := page.Tasks
 	assert.NoError(t, err)
-
-	// Check the number of tasks returned
 	assert.Equal(t, 2, len(tasks))
 	assert.Equal(t, "Task 1", tasks[0].Name)
 	assert.Equal(t, "Task 2", tasks[1].Name)
@@ -182,15 +197,16 @@ func TestGetTasksByTag(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Now test the error handling
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE tag = ?")).
-		WithArgs("high").
+	filter, err = query.Parse("list:3 priority:high")
+	assert.NoError(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE (list_id = ? AND priority = ?) AND `tasks`.`deleted_at` IS NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs(3, "high", defaultPage.Limit+1).
 		WillReturnError(errors.New("db error"))
 
-	// Call the GetTasksByTag method again
-	tasks, err = repo.GetTasksByTag("high")
+	page, err = repo.QueryTasks(filter, defaultPage)
 	assert.Error(t, err)
 	assert.Equal(t, "db error", err.Error()) // Check the specific error message
-	assert.Nil(t, tasks)                     // Should return nil tasks on error
+	assert.Nil(t, page.Tasks)                // Should return nil tasks on error
 
 	// Verify all expectations were met again
 	err = mock.ExpectationsWereMet()
@@ -203,21 +219,22 @@ func TestSearchTasksByName(t *testing.T) {
 
 	// Define the expected tasks
 	expectedTasks := []entity.Task{
-		{ID: 1, Name: "Task One", Deadline: time.Now(), Tag: "high"},
-		{ID: 2, Name: "Task Two", Deadline: time.Now(), Tag: "high"},
+		{ID: 1, Name: "Task One", Deadline: due(time.Now()), Priority: "high"},
+		{ID: 2, Name: "Task Two", Deadline: due(time.Now()), Priority: "high"},
 	}
 
 	// Mock the search operation
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE name LIKE ?")).
-		WithArgs("%One%").
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "tag"}).
-			AddRow(expectedTasks[0].ID, expectedTasks[0].Name, expectedTasks[0].Deadline, expectedTasks[0].Tag))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE name LIKE ? AND `tasks`.`deleted_at` IS NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs("%One%", defaultPage.Limit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(expectedTasks[0].ID, expectedTasks[0].Name, expectedTasks[0].Deadline, expectedTasks[0].Priority))
 
 	// Create the repository instance
 	repo := &TaskRepository{DB: gormDB}
 
 	// Perform the search
-	tasks, err := repo.SearchTasksByName("One")
+	page, err := repo.SearchTasksByName("One", defaultPage)
+	tasks := page.Tasks
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(tasks))
 	assert.Equal(t, "Task One", tasks[0].Name)
@@ -227,31 +244,34 @@ func TestSearchTasksByName(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestFilterTasksByDeadline(t *testing.T) {
+func TestQueryTasksByDeadline(t *testing.T) {
 	gormDB, mock, cleanup := setupTestDB(t)
 	defer cleanup()
 
-	// Define expected tasks based on the deadlines
-	start := time.Now().Add(-72 * time.Hour)
-	end := time.Now().Add(-12 * time.Hour)
+	// A date compared for equality covers the whole day
+	filter, err := query.Parse("deadline:2026-11-02 OR deadline>=2026-12-01T08:00:00Z")
+	assert.NoError(t, err)
+	start := time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2026, 12, 1, 8, 0, 0, 0, time.UTC)
 
 	expectedTasks := []entity.Task{
-		{ID: 1, Name: "Task A", Deadline: time.Now().Add(-48 * time.Hour), Tag: "high"},
-		{ID: 2, Name: "Task B", Deadline: time.Now().Add(-24 * time.Hour), Tag: "high"},
+		{ID: 1, Name: "Task A", Deadline: due(time.Now().Add(-48 * time.Hour)), Priority: "high"},
+		{ID: 2, Name: "Task B", Deadline: due(time.Now().Add(-24 * time.Hour)), Priority: "high"},
 	}
 
 	// Mock the database query for filtering tasks by deadline
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE deadline BETWEEN ? AND ?")).
-		WithArgs(start, end).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "tag"}).
-			AddRow(expectedTasks[0].ID, expectedTasks[0].Name, expectedTasks[0].Deadline, expectedTasks[0].Tag).
-			AddRow(expectedTasks[1].ID, expectedTasks[1].Name, expectedTasks[1].Deadline, expectedTasks[1].Tag)) // Return both tasks
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE ((deadline >= ? AND deadline < ?) OR deadline >= ?) AND `tasks`.`deleted_at` IS NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs(start, start.AddDate(0, 0, 1), from, defaultPage.Limit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(expectedTasks[0].ID, expectedTasks[0].Name, expectedTasks[0].Deadline, expectedTasks[0].Priority).
+			AddRow(expectedTasks[1].ID, expectedTasks[1].Name, expectedTasks[1].Deadline, expectedTasks[1].Priority)) // Return both tasks
 
 	// Create the repository instance
 	repo := &TaskRepository{DB: gormDB}
 
 	// Perform the filter operation
-	tasks, err := repo.FilterTasksByDeadline(start, end)
+	page, err := repo.QueryTasks(filter, defaultPage)
+	tasks := page.Tasks
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(tasks))
 	assert.Equal(t, expectedTasks[0].Name, tasks[0].Name)
@@ -271,12 +291,12 @@ func TestDeleteTask(t *testing.T) {
 
 	// Test successful deletion
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE `tasks`.`id` = ?")).
-		WithArgs(taskID).
-		WillReturnResult(sqlmock.NewResult(0, 1)) // Simulate successful delete
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deleted_at`=? WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), taskID).
+		WillReturnResult(sqlmock.NewResult(0, 1)) // Simulate successful soft delete
 	mock.ExpectCommit()
 
-	err := repo.DeleteTask(taskID)
+	err := repo.DeleteTask(taskID, 0)
 	assert.NoError(t, err)
 
 	// Ensure all expectations are met
@@ -284,28 +304,394 @@ func TestDeleteTask(t *testing.T) {
 
 	// Test deletion when task is not found
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE `tasks`.`id` = ?")).
-		WithArgs(taskID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deleted_at`=? WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), taskID).
 		WillReturnResult(sqlmock.NewResult(0, 0)) // Simulate delete not found
 	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(taskID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-	err = repo.DeleteTask(taskID)
-	assert.NoError(t, err)
+	err = repo.DeleteTask(taskID, 0)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
 
 	// Ensure all expectations are met
 	assert.NoError(t, mock.ExpectationsWereMet())
 
+	// Test deletion with an outdated version
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deleted_at`=? WHERE version = ? AND `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), 2, taskID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(taskID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(taskID))
+
+	err = repo.DeleteTask(taskID, 2)
+	assert.Equal(t, ErrVersionConflict, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+
 	// Test error during deletion
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE `tasks`.`id` = ?")).
-		WithArgs(taskID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deleted_at`=? WHERE `tasks`.`id` = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), taskID).
 		WillReturnError(errors.New("some database error")) // Simulate an error during delete
 	mock.ExpectRollback()
 
-	err = repo.DeleteTask(taskID)
+	err = repo.DeleteTask(taskID, 0)
 	assert.Error(t, err)
 	assert.Equal(t, "some database error", err.Error())
 
 	// Ensure all expectations are met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetAllTasksByStatus(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE status = ? AND `tasks`.`deleted_at` IS NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs("done", defaultPage.Limit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority", "status", "completed_at"}).
+			AddRow(1, "Task 1", time.Now(), "high", "done", time.Now()))
+
+	repo := &TaskRepository{DB: gormDB}
+
+	page, err := repo.GetAllTasks("done", defaultPage)
+	tasks := page.Tasks
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(tasks))
+	assert.Equal(t, "done", tasks[0].Status)
+	assert.NotNil(t, tasks[0].CompletedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTaskStatus(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	completedAt := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `completed_at`=?,`status`=?,`version`=version + 1,`updated_at`=? WHERE id = ? AND version = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(completedAt, "done", sqlmock.AnyArg(), 1, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "status", "completed_at", "version"}).AddRow(1, "Task 1", "done", completedAt, 4))
+	mock.ExpectCommit()
+
+	repo := &TaskRepository{DB: gormDB}
+
+	task, err := repo.UpdateTaskStatus(1, 3, "done", &completedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), task.Version)
+	assert.Equal(t, "done", task.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllTasksPagination(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+	first := time.Date(2024, 10, 1, 9, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	// The first page asks for one row more than the limit to detect a following page
+	page, err := entity.ParsePageRequest("2", "deadline", "")
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`deleted_at` IS NULL ORDER BY deadline IS NULL ASC,deadline ASC,id ASC LIMIT ?")).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(4, "Task 4", first, "high").
+			AddRow(2, "Task 2", second, "less").
+			AddRow(3, "Task 3", second, "medium"))
+
+	result, err := repo.GetAllTasks("", page)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(result.Tasks))
+	assert.NotEmpty(t, result.Next)
+
+	// The cursor continues after the last row of the previous page
+	page, err = entity.ParsePageRequest("2", "deadline", result.Next)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE ((deadline > ? OR (deadline = ? AND id > ?) OR deadline IS NULL)) AND `tasks`.`deleted_at` IS NULL ORDER BY deadline IS NULL ASC,deadline ASC,id ASC LIMIT ?")).
+		WithArgs(second, second, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(3, "Task 3", second, "medium"))
+
+	result, err = repo.GetAllTasks("", page)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result.Tasks))
+	assert.Empty(t, result.Next) // Last page has no cursor
+
+	// Tasks without a deadline come last, so a cursor on one only continues among them
+	page, err = entity.ParsePageRequest("2", "deadline", entity.Cursor{Sort: "deadline", ID: 5}.Encode())
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE ((deadline IS NULL AND id > ?)) AND `tasks`.`deleted_at` IS NULL ORDER BY deadline IS NULL ASC,deadline ASC,id ASC LIMIT ?")).
+		WithArgs(5, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}).
+			AddRow(6, "Task 6", nil, "less"))
+
+	result, err = repo.GetAllTasks("", page)
+	assert.NoError(t, err)
+	if assert.Len(t, result.Tasks, 1) {
+		assert.Nil(t, result.Tasks[0].Deadline)
+	}
+
+	// Descending sort on the ID only compares IDs
+	page, err = entity.ParsePageRequest("2", "-id", entity.Cursor{Sort: "-id", Value: "9", ID: 9}.Encode())
+	assert.NoError(t, err)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE id < ? AND `tasks`.`deleted_at` IS NULL ORDER BY id DESC LIMIT ?")).
+		WithArgs(9, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority"}))
+
+	result, err = repo.GetAllTasks("", page)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Task{}, result.Tasks)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTask(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+	task := &entity.Task{ID: 1, ListID: 2, Name: "Updated", Deadline: due(time.Now()), Priority: "high"}
+
+	// Existing task is updated in place and gets its new version
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deadline`=?,`list_id`=?,`name`=?,`parent_id`=?,`priority`=?,`recurrence`=?,`version`=version + 1,`updated_at`=? WHERE id = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs(task.Deadline, task.ListID, task.Name, task.ParentID, task.Priority, task.Recurrence, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "list_id", "name", "priority", "status", "version"}).AddRow(1, 2, "Updated", "high", "in_progress", 2))
+	mock.ExpectCommit()
+
+	// The task is replaced by the stored one, including the status the update does not touch
+	assert.NoError(t, repo.UpdateTask(task))
+	assert.Equal(t, uint(2), task.Version)
+	assert.Equal(t, "in_progress", task.Status)
+
+	// Missing task is not inserted
+	task.Version = 0
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	assert.Equal(t, gorm.ErrRecordNotFound, repo.UpdateTask(task))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPatchTask(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+
+	// Only the given columns are written
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE id = ? AND version = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs("Renamed", sqlmock.AnyArg(), 3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "version"}).AddRow(3, "Renamed", 6))
+	mock.ExpectCommit()
+
+	task, err := repo.PatchTask(3, 5, map[string]interface{}{"name": "Renamed"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(6), task.Version)
+	assert.Equal(t, "Renamed", task.Name)
+
+	// Another writer bumped the version first
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `name`=?,`version`=version + 1,`updated_at`=? WHERE id = ? AND version = ? AND `tasks`.`deleted_at` IS NULL")).
+		WithArgs("Renamed", sqlmock.AnyArg(), 3, 5).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `tasks` WHERE `tasks`.`id` = ?")).
+		WithArgs(3, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectRollback()
+
+	_, err = repo.PatchTask(3, 5, map[string]interface{}{"name": "Renamed"})
+	assert.Equal(t, ErrVersionConflict, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTrash(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+	deletedAt := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `tasks` WHERE deleted_at IS NOT NULL ORDER BY id ASC LIMIT ?")).
+		WithArgs(entity.DefaultPageLimit + 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "deadline", "priority", "deleted_at"}).
+			AddRow(1, "Task 1", time.Now(), "high", deletedAt))
+
+	result, err := repo.GetTrash(defaultPage)
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 1)
+	assert.NotNil(t, result.Tasks[0].DeletedAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRestoreTask(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+
+	// A trashed task is restored and gets a new version
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET `deleted_at`=?,`version`=version + 1,`updated_at`=? WHERE id = ? AND deleted_at IS NOT NULL")).
+		WithArgs(nil, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RestoreTask(1))
+
+	// A task that is not in the trash cannot be restored
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `tasks` SET")).
+		WithArgs(nil, sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.Equal(t, gorm.ErrRecordNotFound, repo.RestoreTask(2))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeTask(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+
+	// Only trashed tasks are removed for good
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE deleted_at IS NOT NULL AND `tasks`.`id` = ?")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `task_dependencies` WHERE task_id = ? OR blocker_id = ?")).
+		WithArgs(1, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `task_labels` WHERE task_id = ?")).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.PurgeTask(1))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE deleted_at IS NOT NULL AND `tasks`.`id` = ?")).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.Equal(t, gorm.ErrRecordNotFound, repo.PurgeTask(2))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeTrash(t *testing.T) {
+	gormDB, mock, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := &TaskRepository{DB: gormDB}
+	before := time.Now()
+
+	// Dependencies and labels of the purged tasks go first, while the subquery can still find them
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `task_dependencies` WHERE task_id IN (SELECT `id` FROM `tasks` WHERE deleted_at < ?) OR blocker_id IN (SELECT `id` FROM `tasks` WHERE deleted_at < ?)")).
+		WithArgs(before, before).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `task_labels` WHERE task_id IN (SELECT `id` FROM `tasks` WHERE deleted_at < ?)")).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `tasks` WHERE deleted_at < ?")).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := repo.PurgeTrash(before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func setupPostgresTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+	return gormDB, mock
+}
+
+func TestSearchTasksByNamePostgres(t *testing.T) {
+	gormDB, mock := setupPostgresTestDB(t)
+	repo := &TaskRepository{DB: gormDB}
+	rankPage := entity.PageRequest{Limit: 1, Sort: entity.SortRank}
+
+	// Results are ranked by relevance and the cursor carries the score of the last row
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT tasks.*, ts_rank(to_tsvector('english', name), plainto_tsquery('english', $1)) AS search_rank FROM "tasks" WHERE to_tsvector('english', name) @@ plainto_tsquery('english', $2) AND "tasks"."deleted_at" IS NULL ORDER BY search_rank DESC,id ASC LIMIT $3`)).
+		WithArgs("report", "report", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "search_rank"}).
+			AddRow(4, "Quarterly report", float32(0.0991)).
+			AddRow(2, "Report draft", float32(0.0607)))
+
+	result, err := repo.SearchTasksByName("report", rankPage)
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 1)
+	assert.Equal(t, uint(4), result.Tasks[0].ID)
+
+	after, err := entity.DecodeCursor(result.Next)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Cursor{Sort: entity.SortRank, Value: "0.0991", ID: 4}, after)
+
+	// The next page continues below the score of the cursor
+	rankPage.After = &after
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE to_tsvector('english', name) @@ plainto_tsquery('english', $2) AND ((ts_rank(to_tsvector('english', name), plainto_tsquery('english', $3)) < $4 OR (ts_rank(to_tsvector('english', name), plainto_tsquery('english', $5)) = $6 AND id > $7))) AND "tasks"."deleted_at" IS NULL`)).
+		WithArgs("report", "report", "report", float32(0.0991), "report", float32(0.0991), 4, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "search_rank"}).AddRow(2, "Report draft", float32(0.0607)))
+
+	result, err = repo.SearchTasksByName("report", rankPage)
+	assert.NoError(t, err)
+	assert.Len(t, result.Tasks, 1)
+	assert.Empty(t, result.Next)
+
+	// Other orders keep the full-text match but use the regular keyset pagination
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tasks" WHERE list_id = $1 AND to_tsvector('english', name) @@ plainto_tsquery('english', $2) AND "tasks"."deleted_at" IS NULL ORDER BY id ASC LIMIT $3`)).
+		WithArgs(1, "report", entity.DefaultPageLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	_, err = repo.SearchListTasksByName(1, "report", defaultPage)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
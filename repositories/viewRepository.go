@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"log"
+	"strconv"
+	"todo-lists/entity"
+	"todo-lists/models"
+
+	"gorm.io/gorm"
+)
+
+type ViewRepository struct {
+	DB *gorm.DB
+}
+
+// CreateView saves a new view in the database and sets its ID
+func (r *ViewRepository) CreateView(view *entity.View) error {
+	newView := toModelView(*view)
+	newView.ID = 0
+	if err := r.DB.Create(&newView).Error; err != nil {
+		return err
+	}
+	view.ID = strconv.FormatUint(uint64(newView.ID), 10)
+	return nil
+}
+
+// GetAllViews fetches all saved views from the database, ordered by name
+func (r *ViewRepository) GetAllViews() ([]entity.View, error) {
+	var mViews []models.View
+	if err := r.DB.Order("name").Find(&mViews).Error; err != nil {
+		log.Println("Error fetching views:", err)
+		return nil, err
+	}
+
+	return toEntityViews(mViews), nil
+}
+
+// GetViewById method retrieves a saved view by ID from the database
+func (r *ViewRepository) GetViewById(id int) (entity.View, error) {
+	var view models.View
+	if err := r.DB.First(&view, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching view:", err)
+		}
+		return entity.View{}, err
+	}
+	return toEntityView(view), nil
+}
+
+// GetViewByName method retrieves a saved view by its name, returning gorm.ErrRecordNotFound if there is none
+func (r *ViewRepository) GetViewByName(name string) (entity.View, error) {
+	var view models.View
+	if err := r.DB.Where("name = ?", name).First(&view).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Println("Error fetching view:", err)
+		}
+		return entity.View{}, err
+	}
+	return toEntityView(view), nil
+}
+
+// UpdateView method renames a saved view or replaces its query, returning gorm.ErrRecordNotFound if it does
+// not exist
+func (r *ViewRepository) UpdateView(view *entity.View) error {
+	mView := toModelView(*view)
+	if mView.ID == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	result := r.DB.Model(&models.View{}).Where("id = ?", mView.ID).
+		Updates(map[string]interface{}{"name": mView.Name, "query": mView.Query})
+	if result.Error != nil {
+		log.Println("Error updating view:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Nothing changed is not an error for a view that exists
+		_, err := r.GetViewById(int(mView.ID))
+		return err
+	}
+	return nil
+}
+
+// DeleteView method deletes a saved view by its ID
+func (r *ViewRepository) DeleteView(id int) error {
+	result := r.DB.Delete(&models.View{}, id)
+	if result.Error != nil {
+		log.Println("Error deleting view:", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// viewId parses the ID of a saved view
+func viewId(id string) (uint, bool) {
+	n, err := strconv.ParseUint(id, 10, 32)
+	return uint(n), err == nil && n > 0
+}
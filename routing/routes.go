@@ -7,19 +7,88 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func StartServer(taskController *controllers.TaskController) {
+func StartServer(taskController *controllers.TaskController, listController *controllers.ListController, labelController *controllers.LabelController, viewController *controllers.ViewController, idempotency *controllers.IdempotencyMiddleware) {
 	router := gin.Default()
 
-	// Task API
-	router.POST("/tasks", taskController.CreateTask)
+	// Task API; the endpoints that create tasks accept an Idempotency-Key header so clients can retry them
+	router.POST("/tasks", idempotency.Handle, taskController.CreateTask)
 	router.GET("/tasks", taskController.GetTasks)
 	router.GET("/tasks/:id", taskController.GetTaskById)
+	router.GET("/tasks/:id/tree", taskController.GetTaskTree)
 	router.GET("/tasks/tag/:tag", taskController.GetTaskByTag)
 	router.PUT("/tasks/:id", taskController.UpdateTask)
+	router.PATCH("/tasks/:id", taskController.PatchTask)
 	router.GET("/tasks/search", taskController.SearchTasks)
+	router.GET("/tasks/occurrences", taskController.GetOccurrences)
+	router.GET("/tasks/plan", taskController.GetPlan)
 	router.GET("/tasks/filter", taskController.FilterTasksByDeadline)
+	router.POST("/tasks/bulk", idempotency.Handle, taskController.BulkTasks)
+	router.GET("/tasks/export.csv", taskController.ExportTasksCSV)
+	router.GET("/tasks/export.ics", taskController.ExportTasksICal)
+	router.POST("/tasks/import.ics", idempotency.Handle, taskController.ImportTasksICal)
+	router.GET("/tasks/export.todotxt", taskController.ExportTasksTodoTxt)
+	router.POST("/tasks/import.todotxt", idempotency.Handle, taskController.ImportTasksTodoTxt)
+	router.POST("/tasks/import", idempotency.Handle, taskController.ImportTasks)
 	router.DELETE("/tasks/:id", taskController.DeleteTask)
 
+	// Task dependency API
+	router.GET("/tasks/:id/blockers", taskController.GetBlockers)
+	router.POST("/tasks/:id/blockers", taskController.AddBlocker)
+	router.DELETE("/tasks/:id/blockers/:blockerId", taskController.RemoveBlocker)
+
+	// Task label API
+	router.GET("/tasks/:id/labels", labelController.GetTaskLabels)
+	router.POST("/tasks/:id/labels", labelController.AttachLabel)
+	router.DELETE("/tasks/:id/labels/:labelId", labelController.DetachLabel)
+
+	// Trash API
+	router.GET("/trash", taskController.GetTrash)
+	router.POST("/tasks/:id/restore", taskController.RestoreTask)
+	router.DELETE("/trash/:id", taskController.PurgeTask)
+
+	// Task lifecycle API
+	router.POST("/tasks/:id/start", taskController.StartTask)
+	router.POST("/tasks/:id/block", taskController.BlockTask)
+	router.POST("/tasks/:id/complete", taskController.CompleteTask)
+	router.POST("/tasks/:id/reopen", taskController.ReopenTask)
+	router.POST("/tasks/:id/cancel", taskController.CancelTask)
+
+	// List API
+	router.POST("/lists", listController.CreateList)
+	router.GET("/lists", listController.GetLists)
+	router.GET("/lists/:id", listController.GetListById)
+	router.PUT("/lists/:id", listController.UpdateList)
+	router.DELETE("/lists/:id", listController.DeleteList)
+	router.POST("/lists/:id/feed", listController.CreateListFeed)
+	router.DELETE("/lists/:id/feed", listController.DeleteListFeed)
+
+	// Calendar feed API, authorized by the token in the URL
+	router.GET("/feeds/:token", taskController.GetFeed)
+
+	// Label API
+	router.POST("/labels", labelController.CreateLabel)
+	router.GET("/labels", labelController.GetLabels)
+	router.GET("/labels/:id", labelController.GetLabelById)
+	router.PUT("/labels/:id", labelController.UpdateLabel)
+	router.DELETE("/labels/:id", labelController.DeleteLabel)
+
+	// Saved and built-in view API
+	router.POST("/views", viewController.CreateView)
+	router.GET("/views", viewController.GetViews)
+	router.GET("/views/:id", viewController.GetViewById)
+	router.PUT("/views/:id", viewController.UpdateView)
+	router.DELETE("/views/:id", viewController.DeleteView)
+	router.GET("/views/:id/tasks", viewController.GetViewTasks)
+
+	// List scoped task API
+	router.POST("/lists/:id/tasks", idempotency.Handle, taskController.CreateListTask)
+	router.GET("/lists/:id/tasks", taskController.GetListTasks)
+	router.GET("/lists/:id/tasks/tag/:tag", taskController.GetListTasksByTag)
+	router.GET("/lists/:id/tasks/search", taskController.SearchListTasks)
+	router.GET("/lists/:id/tasks/filter", taskController.FilterListTasksByDeadline)
+	router.GET("/lists/:id/tasks/export.md", taskController.ExportListTasksMarkdown)
+	router.POST("/lists/:id/tasks/import.md", idempotency.Handle, taskController.ImportListTasksMarkdown)
+
 	// Start the server
 	if err := router.Run(":8080"); err != nil {
 		log.Fatal("Error starting server:", err)
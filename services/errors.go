@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"todo-lists/query"
+	"todo-lists/recurrence"
+	"todo-lists/repositories"
+)
+
+var (
+	// ErrListNotFound is returned when a task references a list that does not exist
+	ErrListNotFound = errors.New("list not found")
+	// ErrParentNotFound is returned when a task references a parent task that does not exist
+	ErrParentNotFound = errors.New("parent task not found")
+	// ErrTaskCycle is returned when a task would become a subtask of itself or of one of its subtasks
+	ErrTaskCycle = errors.New("task cannot be a subtask of itself")
+	// ErrListNotEmpty is returned when deleting a list that still owns tasks
+	ErrListNotEmpty = errors.New("list still has tasks")
+	// ErrInvalidStatus is returned for a status outside the task lifecycle
+	ErrInvalidStatus = errors.New("invalid task status")
+	// ErrInvalidPriority is returned for a priority other than less, medium or high
+	ErrInvalidPriority = errors.New("invalid task priority")
+	// ErrIllegalTransition is returned when the lifecycle does not allow moving a task to the requested state
+	ErrIllegalTransition = errors.New("illegal status transition")
+	// ErrUnsupportedPatch is returned for a patch media type other than merge patch or JSON patch
+	ErrUnsupportedPatch = errors.New("unsupported patch type")
+	// ErrInvalidPatch is returned for a malformed patch document
+	ErrInvalidPatch = errors.New("invalid patch document")
+	// ErrPatchFailed is returned when a JSON patch cannot be applied, including failed test operations
+	ErrPatchFailed = errors.New("patch could not be applied")
+	// ErrReadOnlyField is returned when a patch changes a field that cannot be patched
+	ErrReadOnlyField = errors.New("field cannot be patched")
+	// ErrInvalidTask is returned when a task fails validation
+	ErrInvalidTask = errors.New("invalid task")
+	// ErrInvalidRecurrence is returned for a recurrence rule outside the supported RRULE subset
+	ErrInvalidRecurrence = recurrence.ErrInvalidRule
+	// ErrInvalidQuery is returned, wrapped in a *query.Error with its position, for a malformed filter expression
+	ErrInvalidQuery = query.ErrInvalidQuery
+	// ErrTooManyOccurrences is returned when a date window holds more than MaxOccurrences occurrences
+	ErrTooManyOccurrences = errors.New("too many occurrences in date range")
+	// ErrBlockerNotFound is returned when a dependency references a blocker task that does not exist
+	ErrBlockerNotFound = errors.New("blocker task not found")
+	// ErrDependencyCycle is returned when a dependency would make a task wait for itself
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrBlocked is returned when a task is started or completed while one of its blockers is still open
+	ErrBlocked = errors.New("task is waiting for its blockers")
+	// ErrInvalidLabel is returned when a label fails validation
+	ErrInvalidLabel = errors.New("invalid label")
+	// ErrLabelExists is returned when a label is created or renamed with the name of another label
+	ErrLabelExists = errors.New("label already exists")
+	// ErrLabelNotFound is returned when a task is given a label that does not exist
+	ErrLabelNotFound = errors.New("label not found")
+	// ErrInvalidView is returned when a saved view fails validation
+	ErrInvalidView = errors.New("invalid view")
+	// ErrViewExists is returned when a view is saved with the name of another view
+	ErrViewExists = errors.New("view already exists")
+	// ErrBuiltInView is returned when a built-in view is changed or deleted
+	ErrBuiltInView = errors.New("built-in views cannot be changed")
+	// ErrInvalidBulk is returned for a bulk request whose operations or action are malformed
+	ErrInvalidBulk = errors.New("invalid bulk request")
+	// ErrInvalidImport is returned for an import file that cannot be read, such as one without a name column
+	ErrInvalidImport = errors.New("invalid import file")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+	// ErrIdempotencyInProgress is returned when an idempotency key is sent again before its first request is handled
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is still in progress")
+	// ErrVersionConflict is returned when a task was changed since the version the caller expected
+	ErrVersionConflict = repositories.ErrVersionConflict
+)
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"log"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+// DefaultIdempotencyLease is how long a claimed key is held for its first request when no Lease is set
+const DefaultIdempotencyLease = 5 * time.Minute
+
+type IdempotencyService struct {
+	Repo repositories.IIdempotencyRepo
+	// TTL is how long a key and its response are kept
+	TTL time.Duration
+	// Lease is how long a key is held for its first request before it has a response. A key whose request
+	// never finished, because the server stopped, can be claimed again after it.
+	Lease time.Duration
+}
+
+// Begin claims an idempotency key for the request with the given hash, for the Lease. When the key was used
+// before with the same request its stored response is returned with replay set. A key used with a different
+// request returns ErrIdempotencyKeyReused, and one whose first request is still being handled
+// ErrIdempotencyInProgress. Expired keys and leases are claimed again.
+func (s *IdempotencyService) Begin(key, requestHash string) (record entity.IdempotencyRecord, replay bool, err error) {
+	now := time.Now()
+	lease := s.Lease
+	if lease <= 0 {
+		lease = DefaultIdempotencyLease
+	}
+	claim := entity.IdempotencyRecord{Key: key, RequestHash: requestHash, ExpiresAt: now.Add(lease)}
+
+	// A key can expire or be released between the attempt to claim it and reading it, claim it once more then
+	for attempt := 0; attempt < 2; attempt++ {
+		err := s.Repo.CreateIdempotencyRecord(&claim)
+		if err == nil {
+			return claim, false, nil
+		}
+		if err != gorm.ErrDuplicatedKey {
+			return entity.IdempotencyRecord{}, false, err
+		}
+
+		stored, err := s.Repo.GetIdempotencyRecord(key)
+		if err == gorm.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return entity.IdempotencyRecord{}, false, err
+		}
+		if stored.ExpiresAt.Before(now) {
+			// Only the expired record is deleted: when another request claimed the key again meanwhile, it is
+			// handling it
+			err := s.Repo.DeleteExpiredIdempotencyRecord(key, now)
+			if err == gorm.ErrRecordNotFound {
+				return entity.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+			}
+			if err != nil {
+				return entity.IdempotencyRecord{}, false, err
+			}
+			continue
+		}
+
+		switch {
+		case stored.RequestHash != requestHash:
+			return entity.IdempotencyRecord{}, false, ErrIdempotencyKeyReused
+		case stored.Status == 0:
+			return entity.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+		}
+		return stored, true, nil
+	}
+	return entity.IdempotencyRecord{}, false, ErrIdempotencyInProgress
+}
+
+// Complete stores the response to the request that claimed a key, for TTL from now instead of the lease
+func (s *IdempotencyService) Complete(record entity.IdempotencyRecord) error {
+	record.ExpiresAt = time.Now().Add(s.TTL)
+	return s.Repo.UpdateIdempotencyRecord(&record)
+}
+
+// Release gives up a claimed key without a response, so the request can be retried with it
+func (s *IdempotencyService) Release(key string) error {
+	if err := s.Repo.DeleteIdempotencyRecord(key); err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	return nil
+}
+
+// PurgeExpired deletes the keys whose TTL is over and returns how many were removed
+func (s *IdempotencyService) PurgeExpired() (int64, error) {
+	return s.Repo.PurgeIdempotencyRecords(time.Now())
+}
+
+// StartIdempotencyPurge deletes expired idempotency keys once right away and then every interval, until the
+// returned stop function is called.
+func StartIdempotencyPurge(service IIdempotencyService, interval time.Duration) (stop func()) {
+	return runEvery(interval, func() {
+		purged, err := service.PurgeExpired()
+		if err != nil {
+			log.Println("Error purging idempotency keys:", err)
+			return
+		}
+		if purged > 0 {
+			log.Printf("Purged %d expired idempotency keys", purged)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestIdempotencyService_Begin(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	idempotencyService := IdempotencyService{Repo: repo, TTL: time.Hour, Lease: 2 * time.Minute}
+
+	// A claim is held for the lease only, the response for the TTL
+	claim, replay, err := idempotencyService.Begin("retry-1", "abc")
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.WithinDuration(t, time.Now().Add(2*time.Minute), claim.ExpiresAt, time.Second)
+
+	// Until the first request is handled a retry has to wait
+	_, _, err = idempotencyService.Begin("retry-1", "abc")
+	assert.Equal(t, ErrIdempotencyInProgress, err)
+
+	claim.Status = 201
+	claim.Header = map[string]string{"Location": "/tasks/7"}
+	claim.Body = []byte(`{"id":7}`)
+	require.NoError(t, idempotencyService.Complete(claim))
+
+	stored, replay, err := idempotencyService.Begin("retry-1", "abc")
+	require.NoError(t, err)
+	assert.True(t, replay)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Second)
+	assert.Equal(t, 201, stored.Status)
+	assert.Equal(t, claim.Header, stored.Header)
+	assert.Equal(t, claim.Body, stored.Body)
+
+	_, _, err = idempotencyService.Begin("retry-1", "def")
+	assert.Equal(t, ErrIdempotencyKeyReused, err)
+
+	// A released key can be used again, with any request
+	_, _, err = idempotencyService.Begin("retry-2", "abc")
+	require.NoError(t, err)
+	require.NoError(t, idempotencyService.Release("retry-2"))
+	require.NoError(t, idempotencyService.Release("retry-2"))
+	_, replay, err = idempotencyService.Begin("retry-2", "def")
+	require.NoError(t, err)
+	assert.False(t, replay)
+}
+
+func TestIdempotencyService_Expiry(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	idempotencyService := IdempotencyService{Repo: repo, TTL: time.Hour}
+
+	require.NoError(t, repo.CreateIdempotencyRecord(&entity.IdempotencyRecord{
+		Key: "retry-1", RequestHash: "abc", Status: 201, ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+	require.NoError(t, repo.CreateIdempotencyRecord(&entity.IdempotencyRecord{
+		Key: "retry-2", RequestHash: "abc", Status: 201, ExpiresAt: time.Now().Add(time.Minute),
+	}))
+
+	require.NoError(t, repo.CreateIdempotencyRecord(&entity.IdempotencyRecord{
+		Key: "retry-3", RequestHash: "abc", ExpiresAt: time.Now().Add(-time.Second),
+	}))
+
+	// A claim whose lease is over, as the server stopped before its request finished, is claimed again
+	claim, replay, err := idempotencyService.Begin("retry-3", "abc")
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.WithinDuration(t, time.Now().Add(DefaultIdempotencyLease), claim.ExpiresAt, time.Second)
+
+	// An expired key is claimed again, even for another request
+	claim, replay, err = idempotencyService.Begin("retry-1", "def")
+	require.NoError(t, err)
+	assert.False(t, replay)
+	assert.Equal(t, "def", claim.RequestHash)
+
+	require.NoError(t, repo.UpdateIdempotencyRecord(&entity.IdempotencyRecord{Key: "retry-1", ExpiresAt: time.Now().Add(-time.Minute)}))
+	purged, err := idempotencyService.PurgeExpired()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	_, err = repo.GetIdempotencyRecord("retry-2")
+	assert.NoError(t, err)
+}
+
+func TestIdempotencyService_RepositoryErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIIdempotencyRepo(ctrl)
+	idempotencyService := IdempotencyService{Repo: mockRepo, TTL: time.Hour}
+
+	mockRepo.EXPECT().CreateIdempotencyRecord(gomock.Any()).Return(errors.New("db error"))
+	_, _, err := idempotencyService.Begin("retry-1", "abc")
+	assert.EqualError(t, err, "db error")
+
+	// A key released between claiming and reading it is claimed once more
+	gomock.InOrder(
+		mockRepo.EXPECT().CreateIdempotencyRecord(gomock.Any()).Return(gorm.ErrDuplicatedKey),
+		mockRepo.EXPECT().GetIdempotencyRecord("retry-1").Return(entity.IdempotencyRecord{}, gorm.ErrRecordNotFound),
+		mockRepo.EXPECT().CreateIdempotencyRecord(gomock.Any()).Return(nil),
+	)
+	_, replay, err := idempotencyService.Begin("retry-1", "abc")
+	assert.NoError(t, err)
+	assert.False(t, replay)
+
+	// A key claimed again by another request between reading and deleting its expired record is in use
+	expired := entity.IdempotencyRecord{Key: "retry-1", RequestHash: "abc", ExpiresAt: time.Now().Add(-time.Minute)}
+	gomock.InOrder(
+		mockRepo.EXPECT().CreateIdempotencyRecord(gomock.Any()).Return(gorm.ErrDuplicatedKey),
+		mockRepo.EXPECT().GetIdempotencyRecord("retry-1").Return(expired, nil),
+		mockRepo.EXPECT().DeleteExpiredIdempotencyRecord("retry-1", gomock.Any()).Return(gorm.ErrRecordNotFound),
+	)
+	_, _, err = idempotencyService.Begin("retry-1", "abc")
+	assert.Equal(t, ErrIdempotencyInProgress, err)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().CreateIdempotencyRecord(gomock.Any()).Return(gorm.ErrDuplicatedKey),
+		mockRepo.EXPECT().GetIdempotencyRecord("retry-1").Return(expired, nil),
+		mockRepo.EXPECT().DeleteExpiredIdempotencyRecord("retry-1", gomock.Any()).Return(errors.New("db error")),
+	)
+	_, _, err = idempotencyService.Begin("retry-1", "abc")
+	assert.EqualError(t, err, "db error")
+
+	mockRepo.EXPECT().DeleteIdempotencyRecord("retry-1").Return(errors.New("db error"))
+	assert.EqualError(t, idempotencyService.Release("retry-1"), "db error")
+}
@@ -1,17 +1,81 @@
 package services
 
 import (
+	"io"
 	"time"
 	"todo-lists/entity"
 )
 
 type IService interface {
 	CreateTask(task *entity.Task) error
-	GetAllTasks() ([]entity.Task, error)
+	GetAllTasks(status string, page entity.PageRequest) (entity.TaskPage, error)
 	GetTaskById(id int) (entity.Task, error)
-	GetTasksByTag(tag string) ([]entity.Task, error)
+	GetTaskTree(id int) (entity.TaskTree, error)
+	AddBlocker(taskId, blockerId int) error
+	RemoveBlocker(taskId, blockerId int) error
+	GetBlockers(taskId int) ([]entity.Task, error)
+	GetPlan(listId int) ([]entity.PlanItem, error)
+	GetTasksByTag(labels []string, matchAll bool, page entity.PageRequest) (entity.TaskPage, error)
 	UpdateTask(task *entity.Task) error
-	SearchTasksByName(keyword string) ([]entity.Task, error)
-	FilterTasksByDeadline(start, end time.Time) ([]entity.Task, error)
-	DeleteTask(id int) error
+	PatchTask(id int, version uint, patchType string, patch []byte) (entity.Task, error)
+	BulkTasks(request entity.BulkRequest, loc *time.Location) ([]entity.BulkResult, error)
+	ExportTasks(filter entity.TaskFilter, loc *time.Location) ([]entity.Task, error)
+	ImportTasksCSV(r io.Reader, options entity.CSVImport) ([]entity.Task, []entity.ImportRowError, error)
+	ImportTasksICal(r io.Reader, options entity.ICalImport) (entity.ImportResult, []entity.ImportRowError, error)
+	ExportLabeledTasks(filter entity.TaskFilter, loc *time.Location) ([]entity.LabeledTask, error)
+	ImportTasksTodoTxt(r io.Reader, options entity.TodoTxtImport) ([]entity.Task, []entity.ImportRowError, error)
+	ExportListTasks(listId int, filter entity.TaskFilter, loc *time.Location) (entity.List, []entity.LabeledTask, error)
+	ImportTasksMarkdown(r io.Reader, options entity.MarkdownImport) (entity.ImportResult, []entity.ImportRowError, error)
+	GetFeedTasks(token string) (entity.List, []entity.Task, error)
+	TransitionTask(id int, status string) (entity.Task, error)
+	QueryTasks(q string, loc *time.Location, page entity.PageRequest) (entity.TaskPage, error)
+	SearchTasksByName(keyword string, page entity.PageRequest) (entity.TaskPage, error)
+	FilterTasksByDeadline(start, end time.Time, page entity.PageRequest) (entity.TaskPage, error)
+	DeleteTask(id int, version uint) error
+	GetTrash(page entity.PageRequest) (entity.TaskPage, error)
+	GetOccurrences(start, end time.Time) ([]entity.Occurrence, error)
+	RestoreTask(id int) (entity.Task, error)
+	PurgeTask(id int) error
+	PurgeTrash(retention time.Duration) (int64, error)
+	GetTasksByListId(listId int, page entity.PageRequest) (entity.TaskPage, error)
+	GetListTasksByTag(listId int, labels []string, matchAll bool, page entity.PageRequest) (entity.TaskPage, error)
+	SearchListTasksByName(listId int, keyword string, page entity.PageRequest) (entity.TaskPage, error)
+	FilterListTasksByDeadline(listId int, start, end time.Time, page entity.PageRequest) (entity.TaskPage, error)
+}
+
+type IListService interface {
+	CreateList(list *entity.List) error
+	GetAllLists() ([]entity.List, error)
+	GetListById(id int) (entity.List, error)
+	UpdateList(list *entity.List) error
+	DeleteList(id int) error
+	CreateListFeed(listId int) (string, error)
+	DeleteListFeed(listId int) error
+}
+
+type ILabelService interface {
+	CreateLabel(label *entity.Label) error
+	GetAllLabels() ([]entity.Label, error)
+	GetLabelById(id int) (entity.Label, error)
+	UpdateLabel(label *entity.Label) error
+	DeleteLabel(id int) error
+	AttachLabel(taskId, labelId int) error
+	DetachLabel(taskId, labelId int) error
+	GetTaskLabels(taskId int) ([]entity.Label, error)
+}
+
+type IViewService interface {
+	CreateView(view *entity.View) error
+	GetAllViews() ([]entity.View, error)
+	GetView(id string) (entity.View, error)
+	UpdateView(view *entity.View) error
+	DeleteView(id string) error
+	GetViewTasks(id string, loc *time.Location, page entity.PageRequest) (entity.ViewTasks, error)
+}
+
+type IIdempotencyService interface {
+	Begin(key, requestHash string) (entity.IdempotencyRecord, bool, error)
+	Complete(record entity.IdempotencyRecord) error
+	Release(key string) error
+	PurgeExpired() (int64, error)
 }
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"todo-lists/entity"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+type LabelService struct {
+	Repo  repositories.ILabelRepo
+	Tasks repositories.IRepo
+}
+
+// CreateLabel method creates a new label. Labels without a color get DefaultLabelColor.
+func (s *LabelService) CreateLabel(label *entity.Label) error {
+	if err := s.validateLabel(label); err != nil {
+		return err
+	}
+	return s.Repo.CreateLabel(label)
+}
+
+// GetAllLabels method retrieves all labels
+func (s *LabelService) GetAllLabels() ([]entity.Label, error) {
+	return s.Repo.GetAllLabels()
+}
+
+// GetLabelById method retrieves a label by ID
+func (s *LabelService) GetLabelById(id int) (entity.Label, error) {
+	return s.Repo.GetLabelById(id)
+}
+
+// UpdateLabel method renames or recolors an existing label
+func (s *LabelService) UpdateLabel(label *entity.Label) error {
+	if _, err := s.Repo.GetLabelById(int(label.ID)); err != nil {
+		return err
+	}
+	if err := s.validateLabel(label); err != nil {
+		return err
+	}
+	return s.Repo.UpdateLabel(label)
+}
+
+// DeleteLabel method deletes a label by its ID, taking it off every task that carries it
+func (s *LabelService) DeleteLabel(id int) error {
+	return s.Repo.DeleteLabel(id)
+}
+
+// AttachLabel method puts a label on a task
+func (s *LabelService) AttachLabel(taskId, labelId int) error {
+	if _, err := s.Tasks.GetTaskById(taskId); err != nil {
+		return err
+	}
+	if _, err := s.Repo.GetLabelById(labelId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLabelNotFound
+		}
+		return err
+	}
+	return s.Repo.AttachLabel(uint(taskId), uint(labelId))
+}
+
+// DetachLabel method takes a label off a task
+func (s *LabelService) DetachLabel(taskId, labelId int) error {
+	return s.Repo.DetachLabel(uint(taskId), uint(labelId))
+}
+
+// GetTaskLabels method retrieves the labels of a task
+func (s *LabelService) GetTaskLabels(taskId int) ([]entity.Label, error) {
+	if _, err := s.Tasks.GetTaskById(taskId); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetTaskLabels(uint(taskId))
+}
+
+// validateLabel trims and checks the name and color of a label and makes sure no other label has its name
+func (s *LabelService) validateLabel(label *entity.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidLabel)
+	}
+	if len(label.Name) > entity.MaxLabelNameLength {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidLabel, entity.MaxLabelNameLength)
+	}
+	if strings.Contains(label.Name, ",") {
+		return fmt.Errorf("%w: name cannot contain a comma", ErrInvalidLabel)
+	}
+	if label.Color == "" {
+		label.Color = entity.DefaultLabelColor
+	}
+	if !entity.ValidLabelColor(label.Color) {
+		return fmt.Errorf("%w: color must be in #rrggbb form", ErrInvalidLabel)
+	}
+	label.Color = strings.ToLower(label.Color)
+
+	existing, err := s.Repo.GetLabelByName(label.Name)
+	if err == nil && existing.ID != label.ID {
+		return ErrLabelExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLabelService_CreateLabel(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	labelService := LabelService{Repo: repo, Tasks: repo}
+
+	// Names are trimmed, colors default and are lowercased
+	label := &entity.Label{Name: "  work "}
+	require.NoError(t, labelService.CreateLabel(label))
+	assert.Equal(t, "work", label.Name)
+	assert.Equal(t, entity.DefaultLabelColor, label.Color)
+	colored := &entity.Label{Name: "urgent", Color: "#FF0000"}
+	require.NoError(t, labelService.CreateLabel(colored))
+	assert.Equal(t, "#ff0000", colored.Color)
+
+	assert.Equal(t, ErrLabelExists, labelService.CreateLabel(&entity.Label{Name: "work"}))
+	for _, invalid := range []entity.Label{
+		{Name: " "},
+		{Name: "a,b"},
+		{Name: "blue", Color: "blue"},
+		{Name: "short", Color: "#fff"},
+		{Name: string(make([]byte, entity.MaxLabelNameLength+1))},
+	} {
+		assert.ErrorIs(t, labelService.CreateLabel(&invalid), ErrInvalidLabel, invalid.Name)
+	}
+
+	labels, err := labelService.GetAllLabels()
+	require.NoError(t, err)
+	assert.Len(t, labels, 2)
+}
+
+func TestLabelService_UpdateLabel(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	labelService := LabelService{Repo: repo, Tasks: repo}
+	work := &entity.Label{Name: "work"}
+	require.NoError(t, labelService.CreateLabel(work))
+	require.NoError(t, labelService.CreateLabel(&entity.Label{Name: "home"}))
+
+	// Keeping the own name is not a conflict
+	require.NoError(t, labelService.UpdateLabel(&entity.Label{ID: work.ID, Name: "work", Color: "#00ff00"}))
+	found, err := labelService.GetLabelById(int(work.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "#00ff00", found.Color)
+
+	assert.Equal(t, ErrLabelExists, labelService.UpdateLabel(&entity.Label{ID: work.ID, Name: "home"}))
+	assert.Equal(t, gorm.ErrRecordNotFound, labelService.UpdateLabel(&entity.Label{ID: 99, Name: "other"}))
+}
+
+func TestLabelService_TaskLabels(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	labelService := LabelService{Repo: repo, Tasks: repo}
+	task := &entity.Task{Name: "Write report", Deadline: due(time.Now()), Priority: entity.PriorityHigh}
+	require.NoError(t, repo.CreateTask(task))
+	work := &entity.Label{Name: "work"}
+	require.NoError(t, labelService.CreateLabel(work))
+
+	require.NoError(t, labelService.AttachLabel(int(task.ID), int(work.ID)))
+	labels, err := labelService.GetTaskLabels(int(task.ID))
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Label{*work}, labels)
+
+	// Missing tasks are not found, missing labels are rejected
+	assert.Equal(t, gorm.ErrRecordNotFound, labelService.AttachLabel(99, int(work.ID)))
+	assert.Equal(t, ErrLabelNotFound, labelService.AttachLabel(int(task.ID), 99))
+	_, err = labelService.GetTaskLabels(99)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	require.NoError(t, labelService.DetachLabel(int(task.ID), int(work.ID)))
+	assert.Equal(t, gorm.ErrRecordNotFound, labelService.DetachLabel(int(task.ID), int(work.ID)))
+}
+
+func TestLabelService_DeleteLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockILabelRepo(ctrl)
+	labelService := LabelService{Repo: mockRepo}
+
+	mockRepo.EXPECT().DeleteLabel(1).Return(nil)
+	assert.NoError(t, labelService.DeleteLabel(1))
+
+	mockRepo.EXPECT().DeleteLabel(2).Return(errors.New("delete error"))
+	assert.Equal(t, "delete error", labelService.DeleteLabel(2).Error())
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+	"todo-lists/entity"
+)
+
+// FeedRefresh is how often clients subscribed to a calendar feed are asked to fetch it again
+const FeedRefresh = time.Hour
+
+// CreateListFeed method gives a list a new calendar feed token, which replaces the token it had before. Only
+// the hash of the token is stored, so this is the only time it is returned.
+func (s *ListService) CreateListFeed(listId int) (string, error) {
+	if _, err := s.Repo.GetListById(listId); err != nil {
+		return "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+	if err := s.Repo.SaveListFeed(entity.ListFeed{ListID: uint(listId), TokenHash: feedTokenHash(token)}); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// DeleteListFeed method revokes the calendar feed of a list
+func (s *ListService) DeleteListFeed(listId int) error {
+	return s.Repo.DeleteListFeed(listId)
+}
+
+// GetFeedTasks method retrieves the list a calendar feed token belongs to, with all its tasks outside the
+// trash. A token that belongs to no feed is gorm.ErrRecordNotFound.
+func (s *TaskService) GetFeedTasks(token string) (entity.List, []entity.Task, error) {
+	feed, err := s.Lists.GetListFeed(feedTokenHash(token))
+	if err != nil {
+		return entity.List{}, nil, err
+	}
+	list, err := s.Lists.GetListById(int(feed.ListID))
+	if err != nil {
+		return entity.List{}, nil, err
+	}
+	tasks, err := s.ExportTasks(entity.TaskFilter{ListID: list.ID}, time.UTC)
+	if err != nil {
+		return entity.List{}, nil, err
+	}
+	return list, tasks, nil
+}
+
+// feedTokenHash returns the hash a feed token is stored and looked up by
+func feedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
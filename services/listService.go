@@ -0,0 +1,42 @@
+package services
+
+import (
+	"todo-lists/entity"
+	"todo-lists/repositories"
+)
+
+type ListService struct {
+	Repo repositories.IListRepo
+}
+
+// CreateList method creates a new list
+func (s *ListService) CreateList(list *entity.List) error {
+	return s.Repo.CreateList(list)
+}
+
+// GetAllLists method retrieves all lists
+func (s *ListService) GetAllLists() ([]entity.List, error) {
+	return s.Repo.GetAllLists()
+}
+
+// GetListById method retrieves a list by ID
+func (s *ListService) GetListById(id int) (entity.List, error) {
+	return s.Repo.GetListById(id)
+}
+
+// UpdateList method updates an existing list
+func (s *ListService) UpdateList(list *entity.List) error {
+	return s.Repo.UpdateList(list)
+}
+
+// DeleteList deletes a list by its ID. Lists that still own tasks are not deleted.
+func (s *ListService) DeleteList(id int) error {
+	count, err := s.Repo.CountTasks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrListNotEmpty
+	}
+	return s.Repo.DeleteList(id)
+}
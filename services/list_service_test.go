@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestListService_CreateList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIListRepo(ctrl)
+	listService := ListService{Repo: mockRepo}
+	list := &entity.List{Name: "Backend"}
+
+	mockRepo.EXPECT().CreateList(list).Return(nil)
+	assert.NoError(t, listService.CreateList(list))
+
+	mockRepo.EXPECT().CreateList(list).Return(errors.New("creation error"))
+	err := listService.CreateList(list)
+	assert.Equal(t, "creation error", err.Error())
+}
+
+func TestListService_GetListById(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIListRepo(ctrl)
+	listService := ListService{Repo: mockRepo}
+	list := entity.List{ID: 1, Name: "Backend"}
+
+	mockRepo.EXPECT().GetListById(1).Return(list, nil)
+	result, err := listService.GetListById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, list, result)
+
+	mockRepo.EXPECT().GetListById(2).Return(entity.List{}, gorm.ErrRecordNotFound)
+	_, err = listService.GetListById(2)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestListService_DeleteList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIListRepo(ctrl)
+	listService := ListService{Repo: mockRepo}
+
+	// Empty list is deleted
+	mockRepo.EXPECT().CountTasks(1).Return(int64(0), nil)
+	mockRepo.EXPECT().DeleteList(1).Return(nil)
+	assert.NoError(t, listService.DeleteList(1))
+
+	// List with tasks is kept
+	mockRepo.EXPECT().CountTasks(2).Return(int64(3), nil)
+	assert.Equal(t, ErrListNotEmpty, listService.DeleteList(2))
+
+	// Count error is returned as is
+	mockRepo.EXPECT().CountTasks(3).Return(int64(0), errors.New("count error"))
+	assert.Equal(t, "count error", listService.DeleteList(3).Error())
+}
+
+func TestListService_CreateListFeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIListRepo(ctrl)
+	listService := ListService{Repo: mockRepo}
+
+	// Only the hash of the returned token is stored
+	var stored entity.ListFeed
+	mockRepo.EXPECT().GetListById(1).Return(entity.List{ID: 1, Name: "Backend"}, nil).Times(2)
+	mockRepo.EXPECT().SaveListFeed(gomock.Any()).DoAndReturn(func(feed entity.ListFeed) error {
+		stored = feed
+		return nil
+	}).Times(2)
+	token, err := listService.CreateListFeed(1)
+	assert.NoError(t, err)
+	assert.Len(t, token, 43)
+	assert.Equal(t, entity.ListFeed{ListID: 1, TokenHash: feedTokenHash(token)}, stored)
+	assert.Len(t, stored.TokenHash, 64)
+	assert.NotContains(t, stored.TokenHash, token)
+
+	// Every feed gets a new token
+	again, err := listService.CreateListFeed(1)
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, again)
+
+	mockRepo.EXPECT().GetListById(2).Return(entity.List{}, gorm.ErrRecordNotFound)
+	_, err = listService.CreateListFeed(2)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+// MaxBulkOperations caps the operations of one bulk request, and the tasks its filter may match
+const MaxBulkOperations = 500
+
+// BulkError reports the operation that failed an atomic bulk request, which changed nothing
+type BulkError struct {
+	Index int
+	Err   error
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("operation %d: %v", e.Index, e.Err)
+}
+
+func (e *BulkError) Unwrap() error {
+	return e.Err
+}
+
+// BulkTasks method applies a batch of create, update, delete and complete operations, or an action to every
+// task a filter matches, in one transaction. Dates in the filter are days in the given location. An atomic
+// request stops at the first failing operation with a *BulkError and rolls everything back; otherwise only
+// the failed operations are rolled back and their errors are reported in the results.
+func (s *TaskService) BulkTasks(request entity.BulkRequest, loc *time.Location) ([]entity.BulkResult, error) {
+	if err := validateBulk(request); err != nil {
+		return nil, err
+	}
+
+	var results []entity.BulkResult
+	err := s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		operations := request.Operations
+		if request.Action != nil {
+			var err error
+			if operations, err = tx.filterOperations(request.Filter, *request.Action, loc); err != nil {
+				return err
+			}
+		}
+
+		results = make([]entity.BulkResult, 0, len(operations))
+		for i, operation := range operations {
+			result := entity.BulkResult{Index: i, Op: operation.Op, ID: operation.ID}
+			if request.Atomic {
+				if err := tx.applyOperation(operation, loc, &result); err != nil {
+					return &BulkError{Index: i, Err: err}
+				}
+			} else {
+				// Each operation runs in a savepoint, so a failure leaves none of its changes behind
+				result.Err = tx.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+					item := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+					return item.applyOperation(operation, loc, &result)
+				})
+				if result.Err != nil {
+					result.Task = nil
+				}
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// validateBulk checks the shape of a bulk request before anything is applied
+func validateBulk(request entity.BulkRequest) error {
+	if request.Action != nil {
+		if len(request.Operations) > 0 {
+			return fmt.Errorf("%w: operations cannot be combined with a filter and action", ErrInvalidBulk)
+		}
+		if request.Filter == "" {
+			return fmt.Errorf("%w: an action needs a filter", ErrInvalidBulk)
+		}
+		if err := validateOperation(*request.Action, true); err != nil {
+			return fmt.Errorf("%w: action: %v", ErrInvalidBulk, err)
+		}
+		return nil
+	}
+
+	if request.Filter != "" {
+		return fmt.Errorf("%w: a filter needs an action", ErrInvalidBulk)
+	}
+	if len(request.Operations) == 0 {
+		return fmt.Errorf("%w: no operations", ErrInvalidBulk)
+	}
+	if len(request.Operations) > MaxBulkOperations {
+		return fmt.Errorf("%w: more than %d operations", ErrInvalidBulk, MaxBulkOperations)
+	}
+	for i, operation := range request.Operations {
+		if err := validateOperation(operation, false); err != nil {
+			return fmt.Errorf("%w: operation %d: %v", ErrInvalidBulk, i, err)
+		}
+	}
+	return nil
+}
+
+// validateOperation checks that an operation carries the fields its kind needs. An action applies to every
+// task a filter matches, so it names no task itself.
+func validateOperation(operation entity.BulkOperation, action bool) error {
+	switch operation.Op {
+	case entity.BulkCreate:
+		if action {
+			return errors.New("a filter cannot create tasks")
+		}
+		if operation.Task == nil {
+			return errors.New("create needs a task")
+		}
+		if operation.ID != 0 {
+			return errors.New("create takes no id")
+		}
+		return nil
+	case entity.BulkUpdate:
+		if len(operation.Patch) == 0 {
+			return errors.New("update needs a patch")
+		}
+	case entity.BulkDelete, entity.BulkComplete:
+	case entity.BulkShiftDeadline:
+		if !action {
+			return errors.New("shift_deadline is only an action for a filter")
+		}
+		if operation.Days == 0 {
+			return errors.New("shift_deadline needs a number of days")
+		}
+	default:
+		return fmt.Errorf("unknown op %q", operation.Op)
+	}
+
+	if action {
+		if operation.ID != 0 || operation.Version != 0 {
+			return errors.New("an action takes no id or version")
+		}
+		return nil
+	}
+	if operation.ID == 0 {
+		return fmt.Errorf("%s needs an id", operation.Op)
+	}
+	return nil
+}
+
+// filterOperations turns an action into one operation for each task the filter matches, ordered by ID.
+// Shifting deadlines leaves out the tasks without one. Deleting or completing leaves out the subtasks of
+// matched tasks, which the cascade already covers.
+func (s *TaskService) filterOperations(filter string, action entity.BulkOperation, loc *time.Location) ([]entity.BulkOperation, error) {
+	expr, err := query.ParseAt(filter, time.Now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	if action.Op == entity.BulkShiftDeadline {
+		expr = query.And{Left: expr, Right: query.Not{Expr: query.Term{Field: query.FieldDeadline, Op: query.Equal, Value: query.None}}}
+	}
+
+	tasks, err := collectPages(func(page entity.PageRequest) (entity.TaskPage, error) {
+		return s.Repo.QueryTasks(expr, page)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > MaxBulkOperations {
+		return nil, fmt.Errorf("%w: the filter matches more than %d tasks", ErrInvalidBulk, MaxBulkOperations)
+	}
+
+	if action.Op == entity.BulkDelete || action.Op == entity.BulkComplete {
+		if tasks, err = s.withoutDescendants(tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	operations := make([]entity.BulkOperation, 0, len(tasks))
+	for _, task := range tasks {
+		operation := action
+		operation.ID = task.ID
+		operations = append(operations, operation)
+	}
+	return operations, nil
+}
+
+// withoutDescendants leaves out the tasks below another one of tasks, at any depth
+func (s *TaskService) withoutDescendants(tasks []entity.Task) ([]entity.Task, error) {
+	matched := make(map[uint]bool, len(tasks))
+	parents := map[uint]uint{}
+	for _, task := range tasks {
+		matched[task.ID] = true
+		parents[task.ID] = task.ParentID
+	}
+
+	kept := make([]entity.Task, 0, len(tasks))
+	for _, task := range tasks {
+		below := false
+		seen := map[uint]bool{task.ID: true}
+		for ancestor := task.ParentID; ancestor != 0 && !seen[ancestor]; {
+			if matched[ancestor] {
+				below = true
+				break
+			}
+			seen[ancestor] = true
+			parent, ok := parents[ancestor]
+			if !ok {
+				stored, err := s.Repo.GetTaskById(int(ancestor))
+				if err == gorm.ErrRecordNotFound {
+					// The chain ends at a task in the trash
+					break
+				}
+				if err != nil {
+					return nil, err
+				}
+				parent = stored.ParentID
+				parents[ancestor] = parent
+			}
+			ancestor = parent
+		}
+		if !below {
+			kept = append(kept, task)
+		}
+	}
+	return kept, nil
+}
+
+// applyOperation applies one bulk operation and records the task it leaves behind in the result
+func (s *TaskService) applyOperation(operation entity.BulkOperation, loc *time.Location, result *entity.BulkResult) error {
+	switch operation.Op {
+	case entity.BulkCreate:
+		task := *operation.Task
+		if err := s.CreateTask(&task); err != nil {
+			return err
+		}
+		result.ID = task.ID
+		result.Task = &task
+	case entity.BulkUpdate:
+		task, err := s.PatchTask(int(operation.ID), operation.Version, MergePatchType, operation.Patch)
+		if err != nil {
+			return err
+		}
+		result.Task = &task
+	case entity.BulkDelete:
+		return s.DeleteTask(int(operation.ID), operation.Version)
+	case entity.BulkComplete:
+		task, err := s.TransitionTask(int(operation.ID), entity.StatusDone)
+		if err != nil {
+			return err
+		}
+		result.Task = &task
+	case entity.BulkShiftDeadline:
+		task, err := s.shiftDeadline(operation.ID, operation.Days, loc)
+		if err != nil {
+			return err
+		}
+		result.Task = &task
+	}
+	return nil
+}
+
+// shiftDeadline moves the deadline of a task by a number of calendar days in the given location, keeping its
+// time of day there across daylight saving changes
+func (s *TaskService) shiftDeadline(id uint, days int, loc *time.Location) (entity.Task, error) {
+	task, err := s.Repo.GetTaskById(int(id))
+	if err != nil {
+		return entity.Task{}, err
+	}
+	if task.Deadline == nil {
+		return entity.Task{}, fmt.Errorf("%w: the task has no deadline to shift", ErrInvalidTask)
+	}
+
+	patch, err := json.Marshal(map[string]time.Time{"deadline": task.Deadline.In(loc).AddDate(0, 0, days)})
+	if err != nil {
+		return entity.Task{}, err
+	}
+	return s.PatchTask(int(id), task.Version, MergePatchType, patch)
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/repositories"
+)
+
+// CSVColumns are the columns of a CSV export, in order. An import reads the columns of csvImportFields and
+// ignores the others, which the server assigns.
+var CSVColumns = []string{"id", "name", "deadline", "priority", "status", "list_id", "parent_id", "recurrence", "completed_at", "created_at", "updated_at"}
+
+// csvImportFields are the task fields a CSV import reads
+var csvImportFields = []string{"name", "deadline", "priority", "status", "list_id", "parent_id", "recurrence"}
+
+// MaxImportRows caps the rows of one import, which is stored in a single transaction
+const MaxImportRows = 5000
+
+// importLayouts are the date formats an import accepts besides RFC 3339, read in the location of the import
+var importLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+// errRollback ends an import transaction that must not store anything
+var errRollback = errors.New("rollback")
+
+// WriteTasksCSV writes tasks as CSV, with a header row of CSVColumns. Times are RFC 3339 in the given
+// location. A missing deadline or completion time, and a task without list or parent, are empty cells. Cells
+// a spreadsheet would run as a formula are escaped, see escapeCSVCell.
+func WriteTasksCSV(w io.Writer, tasks []entity.Task, loc *time.Location) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVColumns); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		values := map[string]string{
+			"id":           strconv.FormatUint(uint64(task.ID), 10),
+			"name":         task.Name,
+			"deadline":     formatCSVTime(task.Deadline, loc),
+			"priority":     task.Priority,
+			"status":       task.Status,
+			"list_id":      formatCSVID(task.ListID),
+			"parent_id":    formatCSVID(task.ParentID),
+			"recurrence":   task.Recurrence,
+			"completed_at": formatCSVTime(task.CompletedAt, loc),
+			"created_at":   formatCSVTime(&task.CreatedAt, loc),
+			"updated_at":   formatCSVTime(&task.UpdatedAt, loc),
+		}
+		record := make([]string, len(CSVColumns))
+		for i, column := range CSVColumns {
+			record[i] = escapeCSVCell(values[column])
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvFormulaPrefixes are the first characters that make a spreadsheet read a cell as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// escapeCSVCell prefixes a cell that starts like a formula with an apostrophe, which spreadsheets show as
+// text instead of running it. Cells that would lose an apostrophe to unescapeCSVCell get one too, so an
+// export imports back unchanged.
+func escapeCSVCell(value string) string {
+	if rest := strings.TrimLeft(value, "'"); rest != "" && strings.IndexByte(csvFormulaPrefixes, rest[0]) >= 0 {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell removes the apostrophe escapeCSVCell added to a cell
+func unescapeCSVCell(value string) string {
+	if strings.HasPrefix(value, "'") && escapeCSVCell(value[1:]) == value {
+		return value[1:]
+	}
+	return value
+}
+
+// ImportTasksCSV method creates a task for each row of a CSV file whose first row names the columns. Every
+// row is checked like a task created on its own, and the tasks are stored in one transaction: when any row
+// is rejected nothing is stored, and the rejected rows are returned with their errors instead. A dry run
+// stores nothing either, and returns the tasks without the fields the server assigns. Problems with the file
+// itself, such as a missing name column, are an ErrInvalidImport.
+func (s *TaskService) ImportTasksCSV(r io.Reader, options entity.CSVImport) ([]entity.Task, []entity.ImportRowError, error) {
+	loc := options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, fmt.Errorf("%w: the file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	fields, err := importColumns(header, options.Columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type row struct {
+		line int
+		task entity.Task
+	}
+	var rows []row
+	var rowErrors []entity.ImportRowError
+	for count := 0; ; count++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The rows after a malformed one cannot be told apart reliably
+			rowErrors = append(rowErrors, entity.ImportRowError{Line: parseErr.StartLine, Err: fmt.Errorf("%w: %v", ErrInvalidImport, parseErr.Err)})
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if count == MaxImportRows {
+			return nil, nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, MaxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		task, field, err := csvTask(record, fields, loc)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.ImportRowError{Line: line, Field: field, Err: err})
+			continue
+		}
+		rows = append(rows, row{line: line, task: task})
+	}
+	if len(rows) == 0 && len(rowErrors) == 0 {
+		return nil, nil, fmt.Errorf("%w: no rows", ErrInvalidImport)
+	}
+
+	created := make([]entity.Task, 0, len(rows))
+	err = s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		for _, row := range rows {
+			task := row.task
+			if err := tx.CreateTask(&task); err != nil {
+				field, ok := rejectedField(err)
+				if !ok {
+					return err
+				}
+				rowErrors = append(rowErrors, entity.ImportRowError{Line: row.line, Field: field, Err: err})
+				continue
+			}
+			created = append(created, task)
+		}
+		if len(rowErrors) > 0 || options.DryRun {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		return nil, nil, err
+	}
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return nil, rowErrors, nil
+	}
+
+	if options.DryRun {
+		for i := range created {
+			clearAssigned(&created[i])
+		}
+	}
+	return created, nil, nil
+}
+
+// clearAssigned clears the fields the server assigned to a task a dry run created and did not keep
+func clearAssigned(task *entity.Task) {
+	task.ID = 0
+	task.Version = 0
+	task.CreatedAt = time.Time{}
+	task.UpdatedAt = time.Time{}
+}
+
+// importColumns finds the column of each import field in the header row, by the given mapping or else by the
+// name of the field. Headers are matched without regard to case and surrounding space.
+func importColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, name := range header {
+		// Spreadsheets often save UTF-8 with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+
+	fields := map[string]int{}
+	for field, column := range mapping {
+		if !slices.Contains(csvImportFields, field) {
+			return nil, fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidImport, field, strings.Join(csvImportFields, ", "))
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return nil, fmt.Errorf("%w: column %q not found", ErrInvalidImport, column)
+		}
+		fields[field] = i
+	}
+	for _, field := range csvImportFields {
+		if _, ok := fields[field]; ok {
+			continue
+		}
+		if i, ok := index[field]; ok {
+			fields[field] = i
+		}
+	}
+
+	if _, ok := fields["name"]; !ok {
+		return nil, fmt.Errorf("%w: no name column", ErrInvalidImport)
+	}
+	return fields, nil
+}
+
+// csvTask reads a task from a row, returning the field at fault with an error. Priority and status are
+// matched without regard to case, an empty cell leaves the field to its default, and the apostrophe of an
+// escaped formula is removed.
+func csvTask(record []string, fields map[string]int, loc *time.Location) (entity.Task, string, error) {
+	cell := func(field string) string {
+		i, ok := fields[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return unescapeCSVCell(strings.TrimSpace(record[i]))
+	}
+
+	task := entity.Task{
+		Name:       cell("name"),
+		Priority:   strings.ToLower(cell("priority")),
+		Status:     strings.ToLower(cell("status")),
+		Recurrence: cell("recurrence"),
+	}
+	if task.Name == "" {
+		return entity.Task{}, "name", fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+	if value := cell("deadline"); value != "" {
+		deadline, ok := parseImportTime(value, loc)
+		if !ok {
+			return entity.Task{}, "deadline", fmt.Errorf("%w: deadline %q is not a date", ErrInvalidTask, value)
+		}
+		task.Deadline = &deadline
+	}
+
+	var ok bool
+	if task.ListID, ok = parseImportID(cell("list_id")); !ok {
+		return entity.Task{}, "list_id", fmt.Errorf("%w: list_id %q is not an ID", ErrInvalidTask, cell("list_id"))
+	}
+	if task.ParentID, ok = parseImportID(cell("parent_id")); !ok {
+		return entity.Task{}, "parent_id", fmt.Errorf("%w: parent_id %q is not an ID", ErrInvalidTask, cell("parent_id"))
+	}
+	return task, "", nil
+}
+
+// rejectedField returns the field a task was rejected for when it was stored. Any other error is not about
+// the task, and fails the whole import.
+func rejectedField(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrIllegalTransition), errors.Is(err, ErrBlocked):
+		return "status", true
+	case errors.Is(err, ErrInvalidPriority):
+		return "priority", true
+	case errors.Is(err, ErrInvalidRecurrence):
+		return "recurrence", true
+	case errors.Is(err, ErrListNotFound):
+		return "list_id", true
+	case errors.Is(err, ErrParentNotFound), errors.Is(err, ErrTaskCycle):
+		return "parent_id", true
+	case errors.Is(err, ErrInvalidTask):
+		return "", true
+	}
+	return "", false
+}
+
+// parseImportTime reads an RFC 3339 timestamp, or a date with an optional time of day in loc
+func parseImportTime(value string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	for _, layout := range importLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseImportID reads an optional ID cell, where empty means none
+func parseImportID(value string) (uint, bool) {
+	if value == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	return uint(id), err == nil
+}
+
+// formatCSVTime formats an optional time as RFC 3339 in loc, empty when it is missing
+func formatCSVTime(t *time.Time, loc *time.Location) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// formatCSVID formats an optional ID, empty when it is zero
+func formatCSVID(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"sort"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// AddBlocker method records that a task cannot start until the blocker is done. A dependency that would
+// close a cycle is rejected with ErrDependencyCycle.
+func (s *TaskService) AddBlocker(taskId, blockerId int) error {
+	if _, err := s.Repo.GetTaskById(taskId); err != nil {
+		return err
+	}
+	if _, err := s.Repo.GetTaskById(blockerId); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrBlockerNotFound
+		}
+		return err
+	}
+	if taskId == blockerId {
+		return ErrDependencyCycle
+	}
+
+	// The new edge closes a cycle when the task already blocks the blocker, directly or through others
+	seen := map[uint]bool{uint(blockerId): true}
+	level := []uint{uint(blockerId)}
+	for len(level) > 0 {
+		dependencies, err := s.Repo.GetDependencies(level)
+		if err != nil {
+			return err
+		}
+		level = nil
+		for _, dependency := range dependencies {
+			if dependency.BlockerID == uint(taskId) {
+				return ErrDependencyCycle
+			}
+			if !seen[dependency.BlockerID] {
+				seen[dependency.BlockerID] = true
+				level = append(level, dependency.BlockerID)
+			}
+		}
+	}
+
+	return s.Repo.AddDependency(entity.Dependency{TaskID: uint(taskId), BlockerID: uint(blockerId)})
+}
+
+// RemoveBlocker method deletes the dependency of a task on a blocker
+func (s *TaskService) RemoveBlocker(taskId, blockerId int) error {
+	return s.Repo.RemoveDependency(entity.Dependency{TaskID: uint(taskId), BlockerID: uint(blockerId)})
+}
+
+// GetBlockers method retrieves the tasks a task waits for. Blockers in the trash are left out.
+func (s *TaskService) GetBlockers(taskId int) ([]entity.Task, error) {
+	if _, err := s.Repo.GetTaskById(taskId); err != nil {
+		return nil, err
+	}
+	return s.blockers(uint(taskId))
+}
+
+// blockers retrieves the blockers of a task that are not in the trash
+func (s *TaskService) blockers(taskId uint) ([]entity.Task, error) {
+	dependencies, err := s.Repo.GetDependencies([]uint{taskId})
+	if err != nil {
+		return nil, err
+	}
+
+	blockers := []entity.Task{}
+	for _, dependency := range dependencies {
+		blocker, err := s.Repo.GetTaskById(int(dependency.BlockerID))
+		if err == gorm.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, blocker)
+	}
+	return blockers, nil
+}
+
+// checkBlockers returns ErrBlocked when a task still waits for a blocker that is neither done nor cancelled
+func (s *TaskService) checkBlockers(taskId uint) error {
+	blockers, err := s.blockers(taskId)
+	if err != nil {
+		return err
+	}
+	for _, blocker := range blockers {
+		if isOpen(blocker) {
+			return ErrBlocked
+		}
+	}
+	return nil
+}
+
+// GetPlan method orders the open tasks of a list, or of all lists when listId is zero, so that every task
+// comes after its blockers. Tasks that are ready at the same point are ordered by deadline. Dependencies on
+// tasks outside the plan do not affect the order.
+func (s *TaskService) GetPlan(listId int) ([]entity.PlanItem, error) {
+	query := func(page entity.PageRequest) (entity.TaskPage, error) {
+		return s.Repo.GetAllTasks("", page)
+	}
+	if listId != 0 {
+		if _, err := s.Lists.GetListById(listId); err != nil {
+			return nil, err
+		}
+		query = func(page entity.PageRequest) (entity.TaskPage, error) {
+			return s.Repo.GetTasksByListId(listId, page)
+		}
+	}
+	all, err := collectPages(query)
+	if err != nil {
+		return nil, err
+	}
+
+	items := map[uint]*entity.PlanItem{}
+	var ids []uint
+	for _, task := range all {
+		if isOpen(task) {
+			items[task.ID] = &entity.PlanItem{Task: task, BlockedBy: []uint{}}
+			ids = append(ids, task.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return []entity.PlanItem{}, nil
+	}
+
+	dependencies, err := s.Repo.GetDependencies(ids)
+	if err != nil {
+		return nil, err
+	}
+	waiting := map[uint]int{}
+	blocks := map[uint][]uint{}
+	for _, dependency := range dependencies {
+		item, blocker := items[dependency.TaskID], items[dependency.BlockerID]
+		if blocker == nil {
+			continue
+		}
+		item.BlockedBy = append(item.BlockedBy, blocker.ID)
+		if item.Deadline != nil && blocker.Deadline != nil && item.Deadline.Before(*blocker.Deadline) {
+			item.DeadlineConflict = true
+		}
+		waiting[item.ID]++
+		blocks[blocker.ID] = append(blocks[blocker.ID], item.ID)
+	}
+
+	var ready []*entity.PlanItem
+	for _, id := range ids {
+		if waiting[id] == 0 {
+			ready = append(ready, items[id])
+		}
+	}
+	plan := make([]entity.PlanItem, 0, len(ids))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if cmp := entity.CompareDeadlines(ready[i].Deadline, ready[j].Deadline); cmp != 0 {
+				return cmp < 0
+			}
+			return ready[i].ID < ready[j].ID
+		})
+		next := ready[0]
+		ready = ready[1:]
+		plan = append(plan, *next)
+
+		for _, id := range blocks[next.ID] {
+			waiting[id]--
+			if waiting[id] == 0 {
+				ready = append(ready, items[id])
+			}
+		}
+	}
+	if len(plan) < len(ids) {
+		return nil, ErrDependencyCycle
+	}
+	return plan, nil
+}
+
+// collectPages reads every page of a paginated task query
+func collectPages(query func(entity.PageRequest) (entity.TaskPage, error)) ([]entity.Task, error) {
+	var tasks []entity.Task
+	page := entity.PageRequest{Limit: entity.MaxPageLimit, Sort: "id"}
+	for {
+		result, err := query(page)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, result.Tasks...)
+		if result.Next == "" {
+			return tasks, nil
+		}
+		after, err := entity.DecodeCursor(result.Next)
+		if err != nil {
+			return nil, err
+		}
+		page.After = &after
+	}
+}
+
+// isOpen reports whether a task still has work left
+func isOpen(task entity.Task) bool {
+	return task.Status != entity.StatusDone && task.Status != entity.StatusCancelled
+}
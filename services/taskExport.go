@@ -0,0 +1,109 @@
+package services
+
+import (
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+)
+
+// ExportTasks method retrieves every task the filter selects, ordered by ID. Dates in its query are days in
+// the given location.
+func (s *TaskService) ExportTasks(filter entity.TaskFilter, loc *time.Location) ([]entity.Task, error) {
+	expr, err := exportFilter(filter, loc)
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil {
+		return collectPages(func(page entity.PageRequest) (entity.TaskPage, error) {
+			return s.Repo.GetAllTasks("", page)
+		})
+	}
+	return collectPages(func(page entity.PageRequest) (entity.TaskPage, error) {
+		return s.Repo.QueryTasks(expr, page)
+	})
+}
+
+// labelBatch caps the tasks whose labels are fetched in one query, below the parameter limits of the databases
+const labelBatch = 1000
+
+// ExportLabeledTasks method retrieves the tasks of an export like ExportTasks, together with the names of
+// their lists and labels, for the formats that write them by name
+func (s *TaskService) ExportLabeledTasks(filter entity.TaskFilter, loc *time.Location) ([]entity.LabeledTask, error) {
+	tasks, err := s.ExportTasks(filter, loc)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := s.Lists.GetAllLists()
+	if err != nil {
+		return nil, err
+	}
+	listNames := map[uint]string{}
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+	}
+
+	labeled := make([]entity.LabeledTask, len(tasks))
+	for start := 0; start < len(tasks); start += labelBatch {
+		batch := tasks[start:min(start+labelBatch, len(tasks))]
+		ids := make([]uint, len(batch))
+		for i, task := range batch {
+			ids[i] = task.ID
+		}
+		labels, err := s.Labels.GetLabelsOfTasks(ids)
+		if err != nil {
+			return nil, err
+		}
+		for i, task := range batch {
+			item := entity.LabeledTask{Task: task, List: listNames[task.ListID]}
+			for _, label := range labels[task.ID] {
+				item.Labels = append(item.Labels, label.Name)
+			}
+			labeled[start+i] = item
+		}
+	}
+	return labeled, nil
+}
+
+// exportFilter combines the parts of an export filter into one expression, nil when the filter is empty.
+// Each part is the expression the endpoint that filters by it alone uses.
+func exportFilter(filter entity.TaskFilter, loc *time.Location) (query.Expr, error) {
+	var parts []query.Expr
+	if filter.ListID != 0 {
+		parts = append(parts, query.Term{Field: query.FieldList, Op: query.Equal, ID: filter.ListID})
+	}
+	if filter.Query != "" {
+		expr, err := query.ParseAt(filter.Query, time.Now().In(loc))
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+	}
+	if filter.Status != "" {
+		if !entity.ValidStatus(filter.Status) {
+			return nil, ErrInvalidStatus
+		}
+		parts = append(parts, query.Term{Field: query.FieldStatus, Op: query.Equal, Value: filter.Status})
+	}
+	if labels := labelFilter(filter.Labels, filter.MatchAll); labels != nil {
+		parts = append(parts, labels)
+	}
+	if filter.Keyword != "" {
+		parts = append(parts, query.Term{Field: query.FieldName, Op: query.Contains, Value: filter.Keyword})
+	}
+	if filter.Start != nil {
+		parts = append(parts, query.Term{Field: query.FieldDeadline, Op: query.GreaterEqual, Time: *filter.Start})
+	}
+	if filter.End != nil {
+		parts = append(parts, query.Term{Field: query.FieldDeadline, Op: query.Less, Time: filter.End.AddDate(0, 0, 1)})
+	}
+
+	var expr query.Expr
+	for _, part := range parts {
+		if expr == nil {
+			expr = part
+		} else {
+			expr = query.And{Left: expr, Right: part}
+		}
+	}
+	return expr, nil
+}
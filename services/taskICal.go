@@ -0,0 +1,356 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/ical"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+// icalProductID identifies the server as the producer of its calendars
+const icalProductID = "-//todo-lists//Tasks//EN"
+
+// icalPriorities maps task priorities to the RFC 5545 PRIORITY scale, where 1 is the highest and 9 the lowest
+var icalPriorities = map[string]string{
+	entity.PriorityHigh:   "1",
+	entity.PriorityMedium: "5",
+	entity.PriorityLess:   "9",
+}
+
+// icalTodoStatuses maps task statuses to the STATUS values of a VTODO. A blocked task still needs action.
+var icalTodoStatuses = map[string]string{
+	entity.StatusTodo:       "NEEDS-ACTION",
+	entity.StatusInProgress: "IN-PROCESS",
+	entity.StatusBlocked:    "NEEDS-ACTION",
+	entity.StatusDone:       "COMPLETED",
+	entity.StatusCancelled:  "CANCELLED",
+}
+
+// newTaskUID returns a random iCalendar UID for a new task. It never changes, so a client that imported or
+// subscribed to a calendar replaces its copy of a task when the task changes instead of adding another, and
+// it is unique across servers, so a calendar exported elsewhere never matches a task here by accident.
+func newTaskUID() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(random) + "@todo-lists", nil
+}
+
+// WriteTasksICal writes tasks as an RFC 5545 calendar. Each task is a VTODO due at its deadline, or with
+// Events a VEVENT starting at it, in which case tasks without a deadline are left out. SEQUENCE follows the
+// task version, so clients can tell which copy of a task is newer.
+func WriteTasksICal(w io.Writer, tasks []entity.Task, export entity.ICalExport) error {
+	calendar := ical.Component{Name: "VCALENDAR"}
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", icalProductID)
+	calendar.Add("CALSCALE", "GREGORIAN")
+	if export.Name != "" {
+		calendar.Add("NAME", ical.Text(export.Name))
+		calendar.Add("X-WR-CALNAME", ical.Text(export.Name))
+	}
+	if export.Refresh > 0 {
+		interval := icalDuration(export.Refresh)
+		calendar.AddParams("REFRESH-INTERVAL", map[string]string{"VALUE": "DURATION"}, interval)
+		calendar.Add("X-PUBLISHED-TTL", interval)
+	}
+
+	uids := make(map[uint]string, len(tasks))
+	for _, task := range tasks {
+		uids[task.ID] = task.UID
+	}
+	for _, task := range tasks {
+		if export.Events {
+			if task.Deadline == nil {
+				continue
+			}
+			calendar.Components = append(calendar.Components, icalEvent(task, uids))
+		} else {
+			calendar.Components = append(calendar.Components, icalTodo(task, uids))
+		}
+	}
+	return calendar.Encode(w)
+}
+
+// icalTodo renders a task as a VTODO
+func icalTodo(task entity.Task, uids map[uint]string) ical.Component {
+	todo := icalComponent("VTODO", task, uids)
+	if task.Deadline != nil {
+		todo.Add("DUE", ical.DateTime(*task.Deadline))
+	}
+	todo.Add("STATUS", icalTodoStatuses[task.Status])
+	if task.CompletedAt != nil && task.Status == entity.StatusDone {
+		todo.Add("COMPLETED", ical.DateTime(*task.CompletedAt))
+		todo.Add("PERCENT-COMPLETE", "100")
+	}
+	return todo
+}
+
+// icalEvent renders a task with a deadline as a VEVENT without duration at its deadline. It does not make
+// the calendar busy at that time.
+func icalEvent(task entity.Task, uids map[uint]string) ical.Component {
+	event := icalComponent("VEVENT", task, uids)
+	event.Add("DTSTART", ical.DateTime(*task.Deadline))
+	event.Add("TRANSP", "TRANSPARENT")
+	if task.Status == entity.StatusCancelled {
+		event.Add("STATUS", "CANCELLED")
+	} else {
+		event.Add("STATUS", "CONFIRMED")
+	}
+	return event
+}
+
+// icalComponent starts a component with the properties VTODO and VEVENT share. The parent of a subtask is
+// named by its UID in uids, where the exported tasks are; a parent that is not exported is left out.
+func icalComponent(name string, task entity.Task, uids map[uint]string) ical.Component {
+	component := ical.Component{Name: name}
+	component.Add("UID", uids[task.ID])
+	component.Add("DTSTAMP", ical.DateTime(task.UpdatedAt))
+	component.Add("CREATED", ical.DateTime(task.CreatedAt))
+	component.Add("LAST-MODIFIED", ical.DateTime(task.UpdatedAt))
+	if task.Version > 1 {
+		component.Add("SEQUENCE", fmt.Sprint(task.Version-1))
+	}
+	component.Add("SUMMARY", ical.Text(task.Name))
+	if priority, ok := icalPriorities[task.Priority]; ok {
+		component.Add("PRIORITY", priority)
+	}
+	if parent, ok := uids[task.ParentID]; ok && task.ParentID != 0 {
+		component.AddParams("RELATED-TO", map[string]string{"RELTYPE": "PARENT"}, parent)
+	}
+	return component
+}
+
+// icalDuration formats a positive duration of whole seconds as an RFC 5545 DURATION such as PT1H
+func icalDuration(d time.Duration) string {
+	seconds := int64(d / time.Second)
+	value := "PT"
+	if hours := seconds / 3600; hours > 0 {
+		value += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := seconds % 3600 / 60; minutes > 0 {
+		value += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds%60 > 0 || value == "PT" {
+		value += fmt.Sprintf("%dS", seconds%60)
+	}
+	return value
+}
+
+// icalImportStatuses maps the STATUS values of a VTODO to task statuses
+var icalImportStatuses = map[string]string{
+	"NEEDS-ACTION": entity.StatusTodo,
+	"IN-PROCESS":   entity.StatusInProgress,
+	"COMPLETED":    entity.StatusDone,
+	"CANCELLED":    entity.StatusCancelled,
+}
+
+// icalItem is a VTODO read from an import, with the line of its BEGIN
+type icalItem struct {
+	line int
+	uid  string
+	task entity.Task
+}
+
+// ImportTasksICal method creates a task for each VTODO of an iCalendar file, or updates the task it was
+// imported as or exported from before, found by its UID. SUMMARY is the name, DUE the deadline and PRIORITY
+// the priority; STATUS moves the task through its lifecycle when it differs. Like a CSV import everything is
+// stored in one transaction, and nothing is when a VTODO is rejected: the rejected ones are returned with the
+// line of their BEGIN instead. A dry run returns the tasks it would create without the fields the server
+// assigns. Problems with the file itself are an ErrInvalidImport.
+func (s *TaskService) ImportTasksICal(r io.Reader, options entity.ICalImport) (entity.ImportResult, []entity.ImportRowError, error) {
+	loc := options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if err := s.checkList(options.ListID); err != nil {
+		return entity.ImportResult{}, nil, err
+	}
+
+	calendar, err := ical.Decode(r)
+	if err != nil {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if calendar.Name != "VCALENDAR" {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: expected a VCALENDAR, found %s", ErrInvalidImport, calendar.Name)
+	}
+
+	zones := ical.NewZones(calendar)
+	var items []icalItem
+	var rowErrors []entity.ImportRowError
+	for _, component := range calendar.Components {
+		// Changes to single occurrences of a recurring VTODO have the UID of the series
+		if _, ok := component.Get("RECURRENCE-ID"); component.Name != "VTODO" || ok {
+			continue
+		}
+		if len(items)+len(rowErrors) == MaxImportRows {
+			return entity.ImportResult{}, nil, fmt.Errorf("%w: more than %d tasks", ErrInvalidImport, MaxImportRows)
+		}
+		item, field, err := icalTask(component, zones, loc)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.ImportRowError{Line: component.Line, Field: field, Err: err})
+			continue
+		}
+		items = append(items, item)
+	}
+	if len(items) == 0 && len(rowErrors) == 0 {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: no VTODO components", ErrInvalidImport)
+	}
+
+	var result entity.ImportResult
+	err = s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		for _, item := range items {
+			task, created, err := tx.importICalItem(item, options.ListID)
+			if err != nil {
+				field, ok := rejectedField(err)
+				if !ok {
+					return err
+				}
+				rowErrors = append(rowErrors, entity.ImportRowError{Line: item.line, Field: field, Err: err})
+				continue
+			}
+			if created {
+				if options.DryRun {
+					clearAssigned(&task)
+				}
+				result.Created++
+			} else {
+				result.Updated++
+			}
+			result.Tasks = append(result.Tasks, task)
+		}
+		if len(rowErrors) > 0 || options.DryRun {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		return entity.ImportResult{}, nil, err
+	}
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return entity.ImportResult{}, rowErrors, nil
+	}
+	return result, nil, nil
+}
+
+// importICalItem creates the task of a VTODO, or updates the task with its UID, reporting whether it created
+// one. A task is found by its UID, the one it was imported with or was given when it was created.
+func (s *TaskService) importICalItem(item icalItem, listId uint) (entity.Task, bool, error) {
+	existing, found, err := s.findByUID(item.uid)
+	if err != nil {
+		return entity.Task{}, false, err
+	}
+	if !found {
+		task := item.task
+		task.ListID = listId
+		task.UID = item.uid
+		if err := s.CreateTask(&task); err != nil {
+			return entity.Task{}, false, err
+		}
+		return task, true, nil
+	}
+
+	task := existing
+	task.Name = item.task.Name
+	task.Deadline = item.task.Deadline
+	task.Priority = item.task.Priority
+	if task.Name != existing.Name || entity.CompareDeadlines(task.Deadline, existing.Deadline) != 0 || task.Priority != existing.Priority {
+		if err := s.UpdateTask(&task); err != nil {
+			return entity.Task{}, false, err
+		}
+	}
+	// Statuses that export alike, such as todo and blocked, are left as they are
+	status := item.task.Status
+	if status != "" && icalImportStatuses[icalTodoStatuses[task.Status]] != status {
+		if task, err = s.transitionImported(task, status); err != nil {
+			return entity.Task{}, false, err
+		}
+	}
+	return task, false, nil
+}
+
+// transitionImported moves a task to the status of an imported item. When the lifecycle has no direct
+// transition it goes through the state in between, so a cancelled task is reopened to complete it.
+func (s *TaskService) transitionImported(task entity.Task, status string) (entity.Task, error) {
+	if !entity.CanTransition(task.Status, status) {
+		for _, via := range []string{entity.StatusTodo, entity.StatusInProgress} {
+			if entity.CanTransition(task.Status, via) && entity.CanTransition(via, status) {
+				if _, err := s.TransitionTask(int(task.ID), via); err != nil {
+					return entity.Task{}, err
+				}
+				break
+			}
+		}
+	}
+	return s.TransitionTask(int(task.ID), status)
+}
+
+// findByUID finds the task outside the trash with a calendar UID
+func (s *TaskService) findByUID(uid string) (entity.Task, bool, error) {
+	if uid == "" {
+		return entity.Task{}, false, nil
+	}
+	task, err := s.Repo.GetTaskByUID(uid)
+	if err == gorm.ErrRecordNotFound {
+		return entity.Task{}, false, nil
+	}
+	if err != nil {
+		return entity.Task{}, false, err
+	}
+	return task, true, nil
+}
+
+// icalTask reads the task of a VTODO, returning the field at fault with an error
+func icalTask(component ical.Component, zones ical.Zones, loc *time.Location) (icalItem, string, error) {
+	item := icalItem{line: component.Line}
+	if uid, ok := component.Get("UID"); ok {
+		item.uid = strings.TrimSpace(uid.Value)
+	}
+
+	summary, _ := component.Get("SUMMARY")
+	item.task.Name = strings.TrimSpace(ical.Unescape(summary.Value))
+	if item.task.Name == "" {
+		return icalItem{}, "name", fmt.Errorf("%w: SUMMARY is required", ErrInvalidTask)
+	}
+
+	if due, ok := component.Get("DUE"); ok {
+		deadline, _, err := zones.Time(due, loc)
+		if err != nil {
+			return icalItem{}, "deadline", fmt.Errorf("%w: DUE: %v", ErrInvalidTask, err)
+		}
+		item.task.Deadline = &deadline
+	}
+
+	item.task.Priority = entity.PriorityMedium
+	if property, ok := component.Get("PRIORITY"); ok {
+		priority, err := strconv.Atoi(strings.TrimSpace(property.Value))
+		switch {
+		case err != nil || priority < 0 || priority > 9:
+			return icalItem{}, "priority", fmt.Errorf("%w: PRIORITY %q is not 0 to 9", ErrInvalidPriority, property.Value)
+		case priority >= 1 && priority <= 4:
+			item.task.Priority = entity.PriorityHigh
+		case priority >= 6:
+			item.task.Priority = entity.PriorityLess
+		}
+	}
+
+	if property, ok := component.Get("STATUS"); ok {
+		status, known := icalImportStatuses[strings.ToUpper(strings.TrimSpace(property.Value))]
+		if !known {
+			return icalItem{}, "status", fmt.Errorf("%w: STATUS %q", ErrInvalidStatus, property.Value)
+		}
+		item.task.Status = status
+	}
+	return item, "", nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/markdown"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+// UntaggedSection is the heading of the tasks without labels in a Markdown export
+const UntaggedSection = "Untagged"
+
+// ExportListTasks method retrieves a list and the tasks of it an export filter selects, like
+// ExportLabeledTasks. It returns gorm.ErrRecordNotFound when the list does not exist.
+func (s *TaskService) ExportListTasks(listId int, filter entity.TaskFilter, loc *time.Location) (entity.List, []entity.LabeledTask, error) {
+	list, err := s.Lists.GetListById(listId)
+	if err != nil {
+		return entity.List{}, nil, err
+	}
+	filter.ListID = list.ID
+	tasks, err := s.ExportLabeledTasks(filter, loc)
+	if err != nil {
+		return entity.List{}, nil, err
+	}
+	return list, tasks, nil
+}
+
+// WriteTasksMarkdown writes tasks as a Markdown document with a task list for each label, in name order and
+// followed by the tasks without labels. A task with several labels is listed under the first. Done tasks are
+// checked, cancelled ones also struck through, and the deadline is a due date in loc. Every item ends with a
+// comment holding the ID of its task, which an import matches it by.
+func WriteTasksMarkdown(w io.Writer, title string, tasks []entity.LabeledTask, loc *time.Location) error {
+	sections := map[string][]markdown.Item{}
+	for _, task := range tasks {
+		item := markdown.Item{
+			Checked: task.Status == entity.StatusDone || task.Status == entity.StatusCancelled,
+			Struck:  task.Status == entity.StatusCancelled,
+			Text:    strings.Join(strings.Fields(task.Name), " "),
+			ID:      task.ID,
+		}
+		if task.Deadline != nil {
+			item.Due = markdownDue(*task.Deadline, loc)
+		}
+		section := UntaggedSection
+		if len(task.Labels) > 0 {
+			section = task.Labels[0]
+		}
+		sections[section] = append(sections[section], item)
+	}
+
+	titles := make([]string, 0, len(sections))
+	for title := range sections {
+		if title != UntaggedSection {
+			titles = append(titles, title)
+		}
+	}
+	sort.Strings(titles)
+	if _, ok := sections[UntaggedSection]; ok {
+		titles = append(titles, UntaggedSection)
+	}
+	document := make([]markdown.Section, 0, len(titles))
+	for _, title := range titles {
+		document = append(document, markdown.Section{Title: title, Items: sections[title]})
+	}
+	return markdown.Write(w, title, document)
+}
+
+// markdownDue formats a deadline as its day in loc, with the time of day when it is not midnight
+func markdownDue(deadline time.Time, loc *time.Location) string {
+	deadline = deadline.In(loc)
+	if deadline.Hour() == 0 && deadline.Minute() == 0 && deadline.Second() == 0 {
+		return deadline.Format("2006-01-02")
+	}
+	return deadline.Format("2006-01-02 15:04")
+}
+
+// markdownItem is a task list item read from an import, with the task it describes
+type markdownItem struct {
+	line    int
+	id      uint
+	section string
+	task    entity.Task
+}
+
+// ImportTasksMarkdown method reconciles a list with the task list items of a Markdown document, such as an
+// export edited in a README. An item with the comment of a task updates that task: its name and deadline, and
+// its status when it was checked or unchecked. An item without one creates a task in the list, with the
+// label its heading names, created when it does not exist. Tasks the document leaves out are not changed.
+// Like a CSV import everything is stored in one transaction, and nothing is when an item is rejected: the
+// rejected ones are returned instead. A dry run returns the tasks it would create without the fields the
+// server assigns.
+func (s *TaskService) ImportTasksMarkdown(r io.Reader, options entity.MarkdownImport) (entity.ImportResult, []entity.ImportRowError, error) {
+	loc := options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if err := s.checkList(options.ListID); err != nil {
+		return entity.ImportResult{}, nil, err
+	}
+
+	parsed, err := markdown.Parse(r)
+	if err != nil {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if len(parsed) > MaxImportRows {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: more than %d tasks", ErrInvalidImport, MaxImportRows)
+	}
+	if len(parsed) == 0 {
+		return entity.ImportResult{}, nil, fmt.Errorf("%w: no task list items", ErrInvalidImport)
+	}
+
+	var items []markdownItem
+	var rowErrors []entity.ImportRowError
+	seen := map[uint]int{}
+	for _, line := range parsed {
+		item, field, err := markdownTask(line, loc)
+		if err == nil && item.id != 0 && seen[item.id] != 0 {
+			field, err = "id", fmt.Errorf("%w: task %d is also on line %d", ErrInvalidTask, item.id, seen[item.id])
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, entity.ImportRowError{Line: line.Line, Field: field, Err: err})
+			continue
+		}
+		if item.id != 0 {
+			seen[item.id] = item.line
+		}
+		items = append(items, item)
+	}
+
+	var result entity.ImportResult
+	err = s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		names, err := tx.loadImportNames()
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			task, created, field, err := tx.importMarkdownItem(item, options.ListID, names)
+			if err != nil {
+				if field == "" {
+					var ok bool
+					if field, ok = rejectedField(err); !ok {
+						return err
+					}
+				}
+				rowErrors = append(rowErrors, entity.ImportRowError{Line: item.line, Field: field, Err: err})
+				continue
+			}
+			if created {
+				if options.DryRun {
+					clearAssigned(&task)
+				}
+				result.Created++
+			} else {
+				result.Updated++
+			}
+			result.Tasks = append(result.Tasks, task)
+		}
+		if len(rowErrors) > 0 || options.DryRun {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		return entity.ImportResult{}, nil, err
+	}
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return entity.ImportResult{}, rowErrors, nil
+	}
+	return result, nil, nil
+}
+
+// importMarkdownItem creates the task of an item, or updates the task of its comment, reporting whether it
+// created one. A missing task, or one of another list, is rejected with the id field.
+func (s *TaskService) importMarkdownItem(item markdownItem, listId uint, names importNames) (entity.Task, bool, string, error) {
+	if item.id == 0 {
+		task := item.task
+		task.ListID = listId
+		if err := s.CreateTask(&task); err != nil {
+			return entity.Task{}, false, "", err
+		}
+		if item.section != "" && item.section != UntaggedSection {
+			if err := s.importLabel(names, task.ID, item.section); err != nil {
+				return entity.Task{}, false, "", err
+			}
+		}
+		return task, true, "", nil
+	}
+
+	existing, err := s.Repo.GetTaskById(int(item.id))
+	if err == gorm.ErrRecordNotFound {
+		return entity.Task{}, false, "id", fmt.Errorf("%w: task %d does not exist", ErrInvalidTask, item.id)
+	}
+	if err != nil {
+		return entity.Task{}, false, "", err
+	}
+	if existing.ListID != listId {
+		return entity.Task{}, false, "id", fmt.Errorf("%w: task %d is not in this list", ErrInvalidTask, item.id)
+	}
+	task := existing
+	task.Name = item.task.Name
+	task.Deadline = item.task.Deadline
+	if task.Name != existing.Name || entity.CompareDeadlines(task.Deadline, existing.Deadline) != 0 {
+		if err := s.UpdateTask(&task); err != nil {
+			return entity.Task{}, false, "", err
+		}
+	}
+
+	// An unchecked item reopens a closed task and leaves an open one in its status
+	status := item.task.Status
+	closed := task.Status == entity.StatusDone || task.Status == entity.StatusCancelled
+	if status == entity.StatusTodo && !closed {
+		status = task.Status
+	}
+	if status != task.Status {
+		if task, err = s.TransitionTask(int(task.ID), status); err != nil {
+			return entity.Task{}, false, "", err
+		}
+	}
+	return task, false, "", nil
+}
+
+// markdownTask reads the task of an item, returning the field at fault with an error. A checked item is
+// done, and cancelled when it is also struck through.
+func markdownTask(line markdown.Item, loc *time.Location) (markdownItem, string, error) {
+	item := markdownItem{line: line.Line, id: line.ID, section: line.Section}
+	item.task = entity.Task{Name: line.Text, Status: entity.StatusTodo}
+	if item.task.Name == "" {
+		return markdownItem{}, "name", fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+	if line.Checked && line.Struck {
+		item.task.Status = entity.StatusCancelled
+	} else if line.Checked {
+		item.task.Status = entity.StatusDone
+	}
+	if line.Due != "" {
+		deadline, ok := parseImportTime(line.Due, loc)
+		if !ok {
+			return markdownItem{}, "deadline", fmt.Errorf("%w: due %q is not a date", ErrInvalidTask, line.Due)
+		}
+		item.task.Deadline = &deadline
+	}
+	if item.id == 0 && item.section != UntaggedSection && (len(item.section) > entity.MaxLabelNameLength || strings.Contains(item.section, ",")) {
+		return markdownItem{}, "section", fmt.Errorf("%w: heading %q is not a label name of at most %d characters without commas", ErrInvalidTask, item.section, entity.MaxLabelNameLength)
+	}
+	return item, "", nil
+}
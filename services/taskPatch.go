@@ -0,0 +1,171 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"todo-lists/entity"
+	"todo-lists/recurrence"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// Media types accepted by PatchTask
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// patchableFields maps the JSON fields a patch may change to their database columns
+var patchableFields = map[string]string{
+	"list_id":    "list_id",
+	"parent_id":  "parent_id",
+	"name":       "name",
+	"deadline":   "deadline",
+	"priority":   "priority",
+	"recurrence": "recurrence",
+}
+
+// PatchTask method applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document to a task. Only
+// the fields the patch changes are written, and the patched task is validated before it is stored. A
+// non-zero version must match the current version of the task.
+func (s *TaskService) PatchTask(id int, version uint, patchType string, patch []byte) (entity.Task, error) {
+	current, err := s.Repo.GetTaskById(id)
+	if err != nil {
+		return entity.Task{}, err
+	}
+	if version != 0 && version != current.Version {
+		return entity.Task{}, ErrVersionConflict
+	}
+
+	original, err := json.Marshal(current)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	patched, err := applyPatch(patchType, original, patch)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	fields, err := changedFields(original, patched)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	var task entity.Task
+	if err := json.Unmarshal(patched, &task); err != nil {
+		return entity.Task{}, fmt.Errorf("%w: %v", ErrInvalidTask, err)
+	}
+	if err := validateTask(task); err != nil {
+		return entity.Task{}, err
+	}
+	if len(fields) == 0 {
+		return task, nil
+	}
+
+	if _, ok := fields["list_id"]; ok {
+		if err := s.checkList(task.ListID); err != nil {
+			return entity.Task{}, err
+		}
+	}
+	if _, ok := fields["parent_id"]; ok {
+		if err := s.checkParent(current.ID, task.ParentID); err != nil {
+			return entity.Task{}, err
+		}
+	}
+
+	columns := make(map[string]interface{}, len(fields))
+	values := map[string]interface{}{
+		"list_id":    task.ListID,
+		"parent_id":  task.ParentID,
+		"name":       task.Name,
+		"deadline":   task.Deadline,
+		"priority":   task.Priority,
+		"recurrence": task.Recurrence,
+	}
+	for field := range fields {
+		columns[patchableFields[field]] = values[field]
+	}
+
+	return s.Repo.PatchTask(id, current.Version, columns)
+}
+
+// applyPatch applies a patch document of the given media type to a JSON document
+func applyPatch(patchType string, original, patch []byte) ([]byte, error) {
+	switch patchType {
+	case MergePatchType:
+		if !json.Valid(patch) {
+			return nil, ErrInvalidPatch
+		}
+		patched, err := jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return patched, nil
+	case JSONPatchType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		patched, err := operations.Apply(original)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPatchFailed, err)
+		}
+		return patched, nil
+	}
+	return nil, ErrUnsupportedPatch
+}
+
+// changedFields returns the top level fields whose values differ between two task documents. Changes to
+// fields outside patchableFields are rejected.
+func changedFields(original, patched []byte) (map[string]bool, error) {
+	var before, after map[string]interface{}
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTask, err)
+	}
+
+	changed := map[string]bool{}
+	for field := range keys(before, after) {
+		if reflect.DeepEqual(before[field], after[field]) {
+			continue
+		}
+		if _, ok := patchableFields[field]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrReadOnlyField, field)
+		}
+		changed[field] = true
+	}
+	return changed, nil
+}
+
+// validateTask checks the fields every stored task must satisfy
+func validateTask(task entity.Task) error {
+	if task.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+	if !entity.ValidPriority(task.Priority) {
+		return fmt.Errorf("%w: priority must be one of less, medium, high", ErrInvalidTask)
+	}
+	if task.Recurrence != "" {
+		if task.Deadline == nil {
+			return fmt.Errorf("%w: a recurring task needs a deadline", ErrInvalidTask)
+		}
+		if _, err := recurrence.Parse(task.Recurrence); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidTask, err)
+		}
+	}
+	return nil
+}
+
+func keys(maps ...map[string]interface{}) map[string]bool {
+	all := map[string]bool{}
+	for _, m := range maps {
+		for k := range m {
+			all[k] = true
+		}
+	}
+	return all
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+)
+
+// QueryTasks method retrieves the tasks that match a filter expression such as
+// `label:work AND deadline<2024-12-01 AND NOT status:done`, see the query package for the language. Dates
+// in the expression, relative ones like today included, are days in the given location.
+func (s *TaskService) QueryTasks(q string, loc *time.Location, page entity.PageRequest) (entity.TaskPage, error) {
+	filter, err := query.ParseAt(q, time.Now().In(loc))
+	if err != nil {
+		return entity.TaskPage{}, err
+	}
+	return s.Repo.QueryTasks(filter, page)
+}
+
+// labelFilter matches the tasks that carry any of the labels, or all of them when matchAll is set. It is nil
+// when no labels are given.
+func labelFilter(labels []string, matchAll bool) query.Expr {
+	var filter query.Expr
+	for _, label := range labels {
+		term := query.Term{Field: query.FieldLabel, Op: query.Equal, Value: label}
+		switch {
+		case filter == nil:
+			filter = term
+		case matchAll:
+			filter = query.And{Left: filter, Right: term}
+		default:
+			filter = query.Or{Left: filter, Right: term}
+		}
+	}
+	return filter
+}
+
+// deadlineFilter matches the tasks due between start and end, both included, end with its whole day
+func deadlineFilter(start, end time.Time) query.Expr {
+	return query.And{
+		Left:  query.Term{Field: query.FieldDeadline, Op: query.GreaterEqual, Time: start},
+		Right: query.Term{Field: query.FieldDeadline, Op: query.Less, Time: end.AddDate(0, 0, 1)},
+	}
+}
+
+// listFilter narrows a filter to the tasks of a list
+func listFilter(listId int, filter query.Expr) query.Expr {
+	return query.And{Left: query.Term{Field: query.FieldList, Op: query.Equal, ID: uint(listId)}, Right: filter}
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/recurrence"
+)
+
+// MaxOccurrences caps how many occurrences GetOccurrences returns for one window
+const MaxOccurrences = 1000
+
+// normalizeRecurrence validates the recurrence rule of a task and stores it in its canonical form. A series
+// counts from the deadline, so a recurring task needs one.
+func normalizeRecurrence(task *entity.Task) error {
+	if task.Recurrence == "" {
+		return nil
+	}
+	if task.Deadline == nil {
+		return fmt.Errorf("%w: a recurring task needs a deadline", ErrInvalidRecurrence)
+	}
+	rule, err := recurrence.Parse(task.Recurrence)
+	if err != nil {
+		return err
+	}
+	task.Recurrence = rule.String()
+	return nil
+}
+
+// completeRecurring marks a recurring task done and creates its next occurrence, which takes over the
+// recurrence rule. The completed task keeps no rule, so reopening and completing it again does not repeat
+// the series. It runs in the transaction of TransitionTask, so a task is never completed without its next
+// occurrence.
+func (s *TaskService) completeRecurring(task entity.Task) (entity.Task, error) {
+	rule, err := recurrence.Parse(task.Recurrence)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	uid, err := newTaskUID()
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	task, err = s.Repo.PatchTask(int(task.ID), task.Version, map[string]interface{}{
+		"status":       entity.StatusDone,
+		"completed_at": completedAt(entity.StatusDone),
+		"recurrence":   "",
+	})
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	deadline, rest, ok := rule.Next(*task.Deadline)
+	if !ok {
+		return task, nil
+	}
+	next := entity.Task{
+		UID:        uid,
+		ListID:     task.ListID,
+		ParentID:   task.ParentID,
+		Name:       task.Name,
+		Deadline:   &deadline,
+		Priority:   task.Priority,
+		Status:     entity.StatusTodo,
+		Recurrence: rest.String(),
+	}
+	if err := s.Repo.CreateTask(&next); err != nil {
+		return entity.Task{}, err
+	}
+	return task, nil
+}
+
+// GetOccurrences method lists the tasks due within the given range, up to the end of its last day, together
+// with the virtual occurrences of recurring tasks in that range, ordered by deadline
+func (s *TaskService) GetOccurrences(start, end time.Time) ([]entity.Occurrence, error) {
+	occurrences := []entity.Occurrence{}
+
+	page := entity.PageRequest{Limit: entity.MaxPageLimit, Sort: "deadline"}
+	for {
+		result, err := s.Repo.QueryTasks(deadlineFilter(start, end), page)
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range result.Tasks {
+			occurrences = append(occurrences, entity.Occurrence{Task: task})
+		}
+		if len(occurrences) > MaxOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+		if result.Next == "" {
+			break
+		}
+		after, err := entity.DecodeCursor(result.Next)
+		if err != nil {
+			return nil, err
+		}
+		page.After = &after
+	}
+
+	last := end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	recurring, err := s.Repo.GetRecurringTasks(last)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range recurring {
+		rule, err := recurrence.Parse(task.Recurrence)
+		if err != nil {
+			log.Printf("Skipping task %d with invalid recurrence %q: %v", task.ID, task.Recurrence, err)
+			continue
+		}
+		for _, deadline := range rule.Occurrences(*task.Deadline, start, last) {
+			// The current occurrence is the task itself
+			if deadline.Equal(*task.Deadline) {
+				continue
+			}
+			virtual := task
+			virtual.Deadline = &deadline
+			virtual.Status = entity.StatusTodo
+			virtual.CompletedAt = nil
+			virtual.Version = 0
+			occurrences = append(occurrences, entity.Occurrence{Task: virtual, Virtual: true})
+			if len(occurrences) > MaxOccurrences {
+				return nil, ErrTooManyOccurrences
+			}
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		a, b := occurrences[i], occurrences[j]
+		if cmp := entity.CompareDeadlines(a.Deadline, b.Deadline); cmp != 0 {
+			return cmp < 0
+		}
+		if a.ID != b.ID {
+			return a.ID < b.ID
+		}
+		return !a.Virtual && b.Virtual
+	})
+	return occurrences, nil
+}
@@ -4,20 +4,57 @@ import (
 	"time"
 	"todo-lists/entity"
 	"todo-lists/repositories"
+
+	"gorm.io/gorm"
 )
 
 type TaskService struct {
-	Repo repositories.IRepo
+	Repo   repositories.IRepo
+	Lists  repositories.IListRepo
+	Labels repositories.ILabelRepo
 }
 
-// CreateTask method creates a new task
+// CreateTask method creates a new task, with a calendar UID of its own unless it brings one
 func (s *TaskService) CreateTask(task *entity.Task) error {
+	if task.Status == "" {
+		task.Status = entity.StatusTodo
+	}
+	if !entity.ValidStatus(task.Status) {
+		return ErrInvalidStatus
+	}
+	task.CompletedAt = completedAt(task.Status)
+	if task.Priority == "" {
+		task.Priority = entity.PriorityMedium
+	}
+	if !entity.ValidPriority(task.Priority) {
+		return ErrInvalidPriority
+	}
+	if err := normalizeRecurrence(task); err != nil {
+		return err
+	}
+
+	if err := s.checkList(task.ListID); err != nil {
+		return err
+	}
+	if err := s.checkParent(0, task.ParentID); err != nil {
+		return err
+	}
+	if task.UID == "" {
+		uid, err := newTaskUID()
+		if err != nil {
+			return err
+		}
+		task.UID = uid
+	}
 	return s.Repo.CreateTask(task)
 }
 
-// GetAllTasks method retrieves all tasks
-func (s *TaskService) GetAllTasks() ([]entity.Task, error) {
-	return s.Repo.GetAllTasks()
+// GetAllTasks method retrieves a page of tasks, or only those in the given status when it is not empty
+func (s *TaskService) GetAllTasks(status string, page entity.PageRequest) (entity.TaskPage, error) {
+	if status != "" && !entity.ValidStatus(status) {
+		return entity.TaskPage{}, ErrInvalidStatus
+	}
+	return s.Repo.GetAllTasks(status, page)
 }
 
 // GetTaskById method retrieves a task by ID
@@ -25,27 +62,214 @@ func (s *TaskService) GetTaskById(id int) (entity.Task, error) {
 	return s.Repo.GetTaskById(id)
 }
 
-// GetTaskByTag method retrieves a task by tag name
-func (s *TaskService) GetTasksByTag(tag string) ([]entity.Task, error) {
-	return s.Repo.GetTasksByTag(tag)
+// GetTasksByTag method retrieves the tasks that carry any of the given labels, or all of them when matchAll is set
+func (s *TaskService) GetTasksByTag(labels []string, matchAll bool, page entity.PageRequest) (entity.TaskPage, error) {
+	filter := labelFilter(labels, matchAll)
+	if filter == nil {
+		return entity.TaskPage{Tasks: []entity.Task{}}, nil
+	}
+	return s.Repo.QueryTasks(filter, page)
 }
 
 // UpdateTask method updates an existing task
 func (s *TaskService) UpdateTask(task *entity.Task) error {
+	if task.Priority == "" {
+		task.Priority = entity.PriorityMedium
+	}
+	if !entity.ValidPriority(task.Priority) {
+		return ErrInvalidPriority
+	}
+	if err := normalizeRecurrence(task); err != nil {
+		return err
+	}
+	if err := s.checkList(task.ListID); err != nil {
+		return err
+	}
+	if err := s.checkParent(task.ID, task.ParentID); err != nil {
+		return err
+	}
 	return s.Repo.UpdateTask(task)
 }
 
+// TransitionTask method moves a task to a new lifecycle state, rejecting transitions the lifecycle does not
+// allow. A task cannot be started or completed while it waits for open blockers. Completing a recurring task
+// creates its next occurrence. Completing or cancelling a task does the same to its open subtasks, see
+// cascadeStatus. All of it happens in one transaction, so when any write fails nothing changes.
+func (s *TaskService) TransitionTask(id int, status string) (entity.Task, error) {
+	var task entity.Task
+	err := s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		var err error
+		task, err = tx.transitionTask(id, status)
+		return err
+	})
+	if err != nil {
+		return entity.Task{}, err
+	}
+	return task, nil
+}
+
+// transitionTask moves a task to a new lifecycle state like TransitionTask, within its transaction
+func (s *TaskService) transitionTask(id int, status string) (entity.Task, error) {
+	task, err := s.Repo.GetTaskById(id)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	if !entity.CanTransition(task.Status, status) {
+		return entity.Task{}, ErrIllegalTransition
+	}
+	if status == entity.StatusInProgress || status == entity.StatusDone {
+		if err := s.checkBlockers(task.ID); err != nil {
+			return entity.Task{}, err
+		}
+	}
+	if status == entity.StatusDone || status == entity.StatusCancelled {
+		if err := s.cascadeStatus(task, status); err != nil {
+			return entity.Task{}, err
+		}
+	}
+	if status == entity.StatusDone && task.Recurrence != "" {
+		return s.completeRecurring(task)
+	}
+
+	return s.Repo.UpdateTaskStatus(id, task.Version, status, completedAt(status))
+}
+
 // SearchTasksByName method searches for tasks by keyword in their name
-func (s *TaskService) SearchTasksByName(keyword string) ([]entity.Task, error) {
-	return s.Repo.SearchTasksByName(keyword)
+func (s *TaskService) SearchTasksByName(keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	return s.Repo.SearchTasksByName(keyword, page)
+}
+
+// FilterTasksByDeadline method retrieves a page of the stored tasks within a specified date range. Future
+// occurrences of recurring tasks are not stored, so they are not included: a cursor can only point at stored
+// tasks. GetOccurrences lists them for a range in one unpaginated response instead.
+func (s *TaskService) FilterTasksByDeadline(start, end time.Time, page entity.PageRequest) (entity.TaskPage, error) {
+	return s.Repo.QueryTasks(deadlineFilter(start, end), page)
+}
+
+// DeleteTask deletes a task by its ID, only if it is still at version when version is not zero. Its
+// subtasks at every depth are moved to the trash with it, in one transaction.
+func (s *TaskService) DeleteTask(id int, version uint) error {
+	return s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		subtasks, err := tx.descendants(uint(id))
+		if err != nil {
+			return err
+		}
+		if err := tx.Repo.DeleteTask(id, version); err != nil {
+			return err
+		}
+		for _, subtask := range subtasks {
+			if err := tx.Repo.DeleteTask(int(subtask.ID), 0); err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTrash method retrieves a page of the deleted tasks
+func (s *TaskService) GetTrash(page entity.PageRequest) (entity.TaskPage, error) {
+	return s.Repo.GetTrash(page)
+}
+
+// RestoreTask method moves a deleted task back out of the trash. Its subtasks stay in the trash. A task
+// whose list or parent task is gone in the meantime is restored without a list or as a top level task.
+func (s *TaskService) RestoreTask(id int) (entity.Task, error) {
+	if err := s.Repo.RestoreTask(id); err != nil {
+		return entity.Task{}, err
+	}
+
+	task, err := s.Repo.GetTaskById(id)
+	if err != nil {
+		return entity.Task{}, err
+	}
+
+	detach := map[string]interface{}{}
+	if err := s.checkList(task.ListID); err == ErrListNotFound {
+		detach["list_id"] = 0
+	} else if err != nil {
+		return entity.Task{}, err
+	}
+	if err := s.checkParent(task.ID, task.ParentID); err == ErrParentNotFound {
+		detach["parent_id"] = 0
+	} else if err != nil {
+		return entity.Task{}, err
+	}
+	if len(detach) == 0 {
+		return task, nil
+	}
+
+	return s.Repo.PatchTask(id, task.Version, detach)
+}
+
+// PurgeTask method permanently deletes a task from the trash
+func (s *TaskService) PurgeTask(id int) error {
+	return s.Repo.PurgeTask(id)
+}
+
+// PurgeTrash method permanently deletes the tasks that have been in the trash longer than retention
+func (s *TaskService) PurgeTrash(retention time.Duration) (int64, error) {
+	return s.Repo.PurgeTrash(time.Now().Add(-retention))
+}
+
+// GetTasksByListId method retrieves all tasks of a list
+func (s *TaskService) GetTasksByListId(listId int, page entity.PageRequest) (entity.TaskPage, error) {
+	if _, err := s.Lists.GetListById(listId); err != nil {
+		return entity.TaskPage{}, err
+	}
+	return s.Repo.GetTasksByListId(listId, page)
+}
+
+// GetListTasksByTag method retrieves the tasks of a list that carry any of the given labels, or all of them when
+// matchAll is set
+func (s *TaskService) GetListTasksByTag(listId int, labels []string, matchAll bool, page entity.PageRequest) (entity.TaskPage, error) {
+	if _, err := s.Lists.GetListById(listId); err != nil {
+		return entity.TaskPage{}, err
+	}
+	filter := labelFilter(labels, matchAll)
+	if filter == nil {
+		return entity.TaskPage{Tasks: []entity.Task{}}, nil
+	}
+	return s.Repo.QueryTasks(listFilter(listId, filter), page)
+}
+
+// SearchListTasksByName method searches the tasks of a list by keyword in their name
+func (s *TaskService) SearchListTasksByName(listId int, keyword string, page entity.PageRequest) (entity.TaskPage, error) {
+	if _, err := s.Lists.GetListById(listId); err != nil {
+		return entity.TaskPage{}, err
+	}
+	return s.Repo.SearchListTasksByName(listId, keyword, page)
+}
+
+// FilterListTasksByDeadline method retrieves the tasks of a list within a specified date range
+func (s *TaskService) FilterListTasksByDeadline(listId int, start, end time.Time, page entity.PageRequest) (entity.TaskPage, error) {
+	if _, err := s.Lists.GetListById(listId); err != nil {
+		return entity.TaskPage{}, err
+	}
+	return s.Repo.QueryTasks(listFilter(listId, deadlineFilter(start, end)), page)
 }
 
-// FilterTasksByDeadline method retrieves tasks within a specified date range
-func (s *TaskService) FilterTasksByDeadline(start, end time.Time) ([]entity.Task, error) {
-	return s.Repo.FilterTasksByDeadline(start, end)
+// checkList makes sure a task only references an existing list. Tasks without a list are always valid.
+func (s *TaskService) checkList(listId uint) error {
+	if listId == 0 {
+		return nil
+	}
+	if _, err := s.Lists.GetListById(int(listId)); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return ErrListNotFound
+		}
+		return err
+	}
+	return nil
 }
 
-// DeleteTask deletes a task by its ID
-func (s *TaskService) DeleteTask(id int) error {
-	return s.Repo.DeleteTask(id)
+// completedAt returns the completion timestamp for a task entering the given state
+func completedAt(status string) *time.Time {
+	if status != entity.StatusDone {
+		return nil
+	}
+	now := time.Now()
+	return &now
 }
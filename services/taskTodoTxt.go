@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/repositories"
+	"todo-lists/todotxt"
+)
+
+// todoTxtPriorities maps task priorities to todo.txt priorities
+var todoTxtPriorities = map[string]string{
+	entity.PriorityHigh:   "A",
+	entity.PriorityMedium: "B",
+	entity.PriorityLess:   "C",
+}
+
+// WriteTasksTodoTxt writes tasks as todo.txt lines. The priority is (A), (B) or (C), done and cancelled tasks
+// are completed, the list is a +project and the labels are @contexts, with spaces written as underscores.
+// The deadline is a due tag with the day in loc, and statuses the format has no mark for are a status tag.
+func WriteTasksTodoTxt(w io.Writer, tasks []entity.LabeledTask, loc *time.Location) error {
+	writer := bufio.NewWriter(w)
+	for _, task := range tasks {
+		item := todotxt.Task{
+			Done:     task.Status == entity.StatusDone || task.Status == entity.StatusCancelled,
+			Priority: todoTxtPriorities[task.Priority],
+			Created:  todoTxtDate(task.CreatedAt, loc),
+			Text:     strings.Join(strings.Fields(task.Name), " "),
+		}
+		if task.CompletedAt != nil {
+			item.Completed = todoTxtDate(*task.CompletedAt, loc)
+		}
+		if task.List != "" {
+			item.Projects = []string{todotxt.Word(task.List)}
+		}
+		for _, label := range task.Labels {
+			item.Contexts = append(item.Contexts, todotxt.Word(label))
+		}
+		if task.Deadline != nil {
+			item.Tags = append(item.Tags, todotxt.Tag{Key: "due", Value: task.Deadline.In(loc).Format("2006-01-02")})
+		}
+		if task.Status != entity.StatusTodo && task.Status != entity.StatusDone {
+			item.Tags = append(item.Tags, todotxt.Tag{Key: "status", Value: task.Status})
+		}
+		if _, err := writer.WriteString(item.String() + "\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// todoTxtDate returns the day of t in loc, as todo.txt dates are
+func todoTxtDate(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// todoTxtItem is a task read from a todo.txt import, with its line and the names of its project and contexts
+type todoTxtItem struct {
+	line     int
+	task     entity.Task
+	project  string
+	contexts []string
+}
+
+// ImportTasksTodoTxt method creates a task for each line of a todo.txt file, skipping blank lines. The text is
+// the name, (A), (B) and (C) are high, medium and less priority, and lower priorities are less. A completed
+// task is done, unless a status tag says otherwise, and a due tag is the deadline. The +project names the list
+// of the task and the @contexts its labels, matched by name without regard to case and with underscores for
+// spaces; lists and labels that do not exist are created. Like a CSV import everything is stored in one
+// transaction, and nothing is when a line is rejected: the rejected lines are returned instead. A dry run
+// returns the tasks it would create without the fields the server assigns.
+func (s *TaskService) ImportTasksTodoTxt(r io.Reader, options entity.TodoTxtImport) ([]entity.Task, []entity.ImportRowError, error) {
+	loc := options.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	if err := s.checkList(options.ListID); err != nil {
+		return nil, nil, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var items []todoTxtItem
+	var rowErrors []entity.ImportRowError
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(items)+len(rowErrors) == MaxImportRows {
+			return nil, nil, fmt.Errorf("%w: more than %d tasks", ErrInvalidImport, MaxImportRows)
+		}
+		item, field, err := todoTxtTask(todotxt.Parse(text), loc)
+		if err != nil {
+			rowErrors = append(rowErrors, entity.ImportRowError{Line: line, Field: field, Err: err})
+			continue
+		}
+		item.line = line
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if len(items) == 0 && len(rowErrors) == 0 {
+		return nil, nil, fmt.Errorf("%w: no tasks", ErrInvalidImport)
+	}
+
+	created := make([]entity.Task, 0, len(items))
+	err := s.Repo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		tx := &TaskService{Repo: tasks, Lists: lists, Labels: labels}
+		names, err := tx.loadImportNames()
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			task, err := tx.importTodoTxtItem(item, options.ListID, names)
+			if err != nil {
+				field, ok := rejectedField(err)
+				if !ok {
+					return err
+				}
+				rowErrors = append(rowErrors, entity.ImportRowError{Line: item.line, Field: field, Err: err})
+				continue
+			}
+			created = append(created, task)
+		}
+		if len(rowErrors) > 0 || options.DryRun {
+			return errRollback
+		}
+		return nil
+	})
+	if err != nil && err != errRollback {
+		return nil, nil, err
+	}
+	if len(rowErrors) > 0 {
+		sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Line < rowErrors[j].Line })
+		return nil, rowErrors, nil
+	}
+
+	if options.DryRun {
+		for i := range created {
+			clearAssigned(&created[i])
+		}
+	}
+	return created, nil, nil
+}
+
+// importNames finds lists and labels by name for the imports that refer to them by name, by nameKey
+type importNames struct {
+	lists  map[string]uint
+	labels map[string]uint
+}
+
+// nameKey matches the names of lists and labels without regard to case, and with underscores for spaces
+func nameKey(name string) string {
+	return strings.ToLower(todotxt.Word(name))
+}
+
+// loadImportNames reads the names of all lists and labels. Of several names with the same key, the first one
+// created is used.
+func (s *TaskService) loadImportNames() (importNames, error) {
+	names := importNames{lists: map[string]uint{}, labels: map[string]uint{}}
+	lists, err := s.Lists.GetAllLists()
+	if err != nil {
+		return importNames{}, err
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID < lists[j].ID })
+	for _, list := range lists {
+		if _, ok := names.lists[nameKey(list.Name)]; !ok {
+			names.lists[nameKey(list.Name)] = list.ID
+		}
+	}
+
+	labels, err := s.Labels.GetAllLabels()
+	if err != nil {
+		return importNames{}, err
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].ID < labels[j].ID })
+	for _, label := range labels {
+		if _, ok := names.labels[nameKey(label.Name)]; !ok {
+			names.labels[nameKey(label.Name)] = label.ID
+		}
+	}
+	return names, nil
+}
+
+// importList returns the ID of the list with a name, creating the list when there is none
+func (s *TaskService) importList(names importNames, name string) (uint, error) {
+	if id, ok := names.lists[nameKey(name)]; ok {
+		return id, nil
+	}
+	list := entity.List{Name: name}
+	if err := s.Lists.CreateList(&list); err != nil {
+		return 0, err
+	}
+	names.lists[nameKey(name)] = list.ID
+	return list.ID, nil
+}
+
+// importLabel puts the label with a name on a task, creating the label when there is none
+func (s *TaskService) importLabel(names importNames, taskId uint, name string) error {
+	id, ok := names.labels[nameKey(name)]
+	if !ok {
+		label := entity.Label{Name: name, Color: entity.DefaultLabelColor}
+		if err := s.Labels.CreateLabel(&label); err != nil {
+			return err
+		}
+		id = label.ID
+		names.labels[nameKey(name)] = id
+	}
+	return s.Labels.AttachLabel(taskId, id)
+}
+
+// importTodoTxtItem creates the task of a line in the list of its project, or else in listId, and puts the
+// labels of its contexts on it
+func (s *TaskService) importTodoTxtItem(item todoTxtItem, listId uint, names importNames) (entity.Task, error) {
+	task := item.task
+	task.ListID = listId
+	if item.project != "" {
+		id, err := s.importList(names, strings.ReplaceAll(item.project, "_", " "))
+		if err != nil {
+			return entity.Task{}, err
+		}
+		task.ListID = id
+	}
+	if err := s.CreateTask(&task); err != nil {
+		return entity.Task{}, err
+	}
+
+	for _, context := range item.contexts {
+		if err := s.importLabel(names, task.ID, strings.ReplaceAll(context, "_", " ")); err != nil {
+			return entity.Task{}, err
+		}
+	}
+	return task, nil
+}
+
+// todoTxtTask reads the task of a line, returning the field at fault with an error
+func todoTxtTask(line todotxt.Task, loc *time.Location) (todoTxtItem, string, error) {
+	item := todoTxtItem{task: entity.Task{Name: line.Text}}
+	if item.task.Name == "" {
+		return todoTxtItem{}, "name", fmt.Errorf("%w: name is required", ErrInvalidTask)
+	}
+
+	switch line.Priority {
+	case "":
+	case "A":
+		item.task.Priority = entity.PriorityHigh
+	case "B":
+		item.task.Priority = entity.PriorityMedium
+	default:
+		item.task.Priority = entity.PriorityLess
+	}
+
+	if line.Done {
+		item.task.Status = entity.StatusDone
+	}
+	if status, ok := line.Tag("status"); ok {
+		if !entity.ValidStatus(status) {
+			return todoTxtItem{}, "status", fmt.Errorf("%w: status:%s", ErrInvalidStatus, status)
+		}
+		item.task.Status = status
+	}
+
+	if value, ok := line.Tag("due"); ok {
+		deadline, ok := parseImportTime(value, loc)
+		if !ok {
+			return todoTxtItem{}, "deadline", fmt.Errorf("%w: due:%s is not a date", ErrInvalidTask, value)
+		}
+		item.task.Deadline = &deadline
+	}
+
+	if len(line.Projects) > 1 {
+		return todoTxtItem{}, "project", fmt.Errorf("%w: a task belongs to one list, found +%s", ErrInvalidTask, strings.Join(line.Projects, " +"))
+	}
+	if len(line.Projects) == 1 {
+		item.project = line.Projects[0]
+	}
+	for _, context := range line.Contexts {
+		if len(context) > entity.MaxLabelNameLength || strings.Contains(context, ",") {
+			return todoTxtItem{}, "context", fmt.Errorf("%w: @%s is not a label name of at most %d characters without commas", ErrInvalidTask, context, entity.MaxLabelNameLength)
+		}
+		item.contexts = append(item.contexts, context)
+	}
+	return item, "", nil
+}
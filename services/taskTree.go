@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"todo-lists/entity"
+
+	"gorm.io/gorm"
+)
+
+// GetTaskTree method retrieves a task with its subtasks nested to any depth, each with the progress of its
+// own subtasks rolled up
+func (s *TaskService) GetTaskTree(id int) (entity.TaskTree, error) {
+	task, err := s.Repo.GetTaskById(id)
+	if err != nil {
+		return entity.TaskTree{}, err
+	}
+	subtasks, err := s.descendants(task.ID)
+	if err != nil {
+		return entity.TaskTree{}, err
+	}
+
+	children := map[uint][]entity.Task{}
+	for _, subtask := range subtasks {
+		children[subtask.ParentID] = append(children[subtask.ParentID], subtask)
+	}
+	return buildTree(task, children), nil
+}
+
+// buildTree nests the subtasks below task and rolls up their progress
+func buildTree(task entity.Task, children map[uint][]entity.Task) entity.TaskTree {
+	tree := entity.TaskTree{Task: task, Subtasks: []entity.TaskTree{}}
+	for _, child := range children[task.ID] {
+		subtree := buildTree(child, children)
+		tree.Subtasks = append(tree.Subtasks, subtree)
+
+		tree.Progress.Total += subtree.Progress.Total
+		tree.Progress.Done += subtree.Progress.Done
+		tree.Progress.EarliestDeadline = earliest(tree.Progress.EarliestDeadline, subtree.Progress.EarliestDeadline)
+		switch child.Status {
+		case entity.StatusCancelled:
+		case entity.StatusDone:
+			tree.Progress.Total++
+			tree.Progress.Done++
+		default:
+			tree.Progress.Total++
+			tree.Progress.EarliestDeadline = earliest(tree.Progress.EarliestDeadline, child.Deadline)
+		}
+	}
+	if tree.Progress.Total > 0 {
+		tree.Progress.Percent = tree.Progress.Done * 100 / tree.Progress.Total
+	}
+	return tree
+}
+
+// descendants returns the subtasks of a task at every depth, level by level. A task that shows up twice,
+// which only corrupt data can cause, is not followed again.
+func (s *TaskService) descendants(id uint) ([]entity.Task, error) {
+	var all []entity.Task
+	seen := map[uint]bool{id: true}
+	level := []uint{id}
+	for len(level) > 0 {
+		subtasks, err := s.Repo.GetSubtasks(level)
+		if err != nil {
+			return nil, err
+		}
+		level = nil
+		for _, subtask := range subtasks {
+			if seen[subtask.ID] {
+				continue
+			}
+			seen[subtask.ID] = true
+			all = append(all, subtask)
+			level = append(level, subtask.ID)
+		}
+	}
+	return all, nil
+}
+
+// checkParent makes sure the parent of a task exists and is not the task itself or one of its subtasks.
+// Tasks without a parent are always valid; id is zero for a task that is not stored yet.
+func (s *TaskService) checkParent(id, parentId uint) error {
+	if parentId == 0 {
+		return nil
+	}
+	if parentId == id {
+		return ErrTaskCycle
+	}
+
+	seen := map[uint]bool{}
+	for ancestor := parentId; ancestor != 0 && !seen[ancestor]; {
+		task, err := s.Repo.GetTaskById(int(ancestor))
+		if err == gorm.ErrRecordNotFound && ancestor == parentId {
+			return ErrParentNotFound
+		}
+		if err == gorm.ErrRecordNotFound {
+			// The chain ends at a task in the trash
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if task.ParentID == id && id != 0 {
+			return ErrTaskCycle
+		}
+		seen[ancestor] = true
+		ancestor = task.ParentID
+	}
+	return nil
+}
+
+// cascadeStatus applies a completion or cancellation of a task to its open subtasks at every depth. Every
+// subtask must be able to follow, otherwise nothing changes and ErrIllegalTransition is returned, so a
+// blocked subtask keeps its parent from being completed. Likewise a subtask still waiting for an open
+// blocker keeps its parent from being completed with ErrBlocked. Recurring subtasks end their series instead of
+// creating a next occurrence.
+func (s *TaskService) cascadeStatus(task entity.Task, status string) error {
+	subtasks, err := s.descendants(task.ID)
+	if err != nil {
+		return err
+	}
+
+	var open []entity.Task
+	for _, subtask := range subtasks {
+		if !isOpen(subtask) {
+			continue
+		}
+		if !entity.CanTransition(subtask.Status, status) {
+			return fmt.Errorf("%w: subtask %d is %s", ErrIllegalTransition, subtask.ID, subtask.Status)
+		}
+		if status == entity.StatusDone {
+			if err := s.checkBlockers(subtask.ID); err == ErrBlocked {
+				return fmt.Errorf("%w: subtask %d", ErrBlocked, subtask.ID)
+			} else if err != nil {
+				return err
+			}
+		}
+		open = append(open, subtask)
+	}
+
+	// The deepest subtasks go first, so a failure never leaves a subtask open below a closed one
+	for i := len(open) - 1; i >= 0; i-- {
+		_, err := s.Repo.UpdateTaskStatus(int(open[i].ID), open[i].Version, status, completedAt(status))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// earliest returns the earlier of two optional times
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || b != nil && b.Before(*a) {
+		return b
+	}
+	return a
+}
@@ -1,17 +1,63 @@
 package services
 
 import (
+	"encoding/csv"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 	"todo-lists/entity"
 	"todo-lists/mocks"
+	"todo-lists/query"
+	"todo-lists/repositories"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
+// due returns a deadline for a task literal
+func due(deadline time.Time) *time.Time {
+	return &deadline
+}
+
+// passTransactions lets a mock repository run transactions on itself
+func passTransactions(mockRepo *mocks.MockIRepo) {
+	mockRepo.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(repositories.IRepo, repositories.IListRepo, repositories.ILabelRepo) error) error {
+		return fn(mockRepo, nil, nil)
+	}).AnyTimes()
+}
+
+// failingRepo is a repository whose status writes to one task, and task creations when failCreate is set,
+// fail, also within transactions
+type failingRepo struct {
+	repositories.IRepo
+	failStatus int
+	failCreate bool
+}
+
+func (r failingRepo) CreateTask(task *entity.Task) error {
+	if r.failCreate {
+		return errors.New("creation error")
+	}
+	return r.IRepo.CreateTask(task)
+}
+
+func (r failingRepo) UpdateTaskStatus(id int, version uint, status string, completedAt *time.Time) (entity.Task, error) {
+	if id == r.failStatus {
+		return entity.Task{}, repositories.ErrVersionConflict
+	}
+	return r.IRepo.UpdateTaskStatus(id, version, status, completedAt)
+}
+
+func (r failingRepo) Transaction(fn func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error) error {
+	return r.IRepo.Transaction(func(tasks repositories.IRepo, lists repositories.IListRepo, labels repositories.ILabelRepo) error {
+		return fn(failingRepo{IRepo: tasks, failStatus: r.failStatus, failCreate: r.failCreate}, lists, labels)
+	})
+}
+
 func TestTaskService_CreateTask(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -38,19 +84,20 @@ func TestTaskService_GetAllTasks(t *testing.T) {
 
 	mockRepo := mocks.NewMockIRepo(ctrl)
 	taskService := TaskService{Repo: mockRepo}
-	tasks := []entity.Task{{ID: 1, Name: "Task 1"}, {ID: 2, Name: "Task 2"}}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Task 1"}, {ID: 2, Name: "Task 2"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
 
 	// all tasks successful retrieval
-	mockRepo.EXPECT().GetAllTasks().Return(tasks, nil)
-	result, err := taskService.GetAllTasks()
+	mockRepo.EXPECT().GetAllTasks("", page).Return(tasks, nil)
+	result, err := taskService.GetAllTasks("", page)
 	assert.NoError(t, err)
 	assert.Equal(t, tasks, result)
 
 	// task retrieval error
-	mockRepo.EXPECT().GetAllTasks().Return(nil, errors.New("fetch error"))
-	result, err = taskService.GetAllTasks()
+	mockRepo.EXPECT().GetAllTasks("", page).Return(entity.TaskPage{}, errors.New("fetch error"))
+	result, err = taskService.GetAllTasks("", page)
 	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.Nil(t, result.Tasks)
 	assert.Equal(t, "fetch error", err.Error())
 }
 
@@ -87,20 +134,60 @@ func TestTaskService_GetTasksByTag(t *testing.T) {
 
 	mockRepo := mocks.NewMockIRepo(ctrl)
 	taskService := TaskService{Repo: mockRepo}
-	tasks := []entity.Task{{ID: 1, Name: "Task 1", Tag: "urgent"}}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Task 1", Priority: "high"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
 
 	// Task successful retrieval
-	mockRepo.EXPECT().GetTasksByTag("urgent").Return(tasks, nil)
-	result, err := taskService.GetTasksByTag("urgent")
+	// Labels are matched through a query, all of them with matchAll
+	allOf := query.And{
+		Left:  query.Term{Field: query.FieldLabel, Op: query.Equal, Value: "urgent"},
+		Right: query.Term{Field: query.FieldLabel, Op: query.Equal, Value: "work"},
+	}
+	mockRepo.EXPECT().QueryTasks(allOf, page).Return(tasks, nil)
+	result, err := taskService.GetTasksByTag([]string{"urgent", "work"}, true, page)
 	assert.NoError(t, err)
 	assert.Equal(t, tasks, result)
 
 	// Test retrieval error
-	mockRepo.EXPECT().GetTasksByTag("nonexistent").Return(nil, errors.New("fetch error"))
-	result, err = taskService.GetTasksByTag("nonexistent")
+	mockRepo.EXPECT().QueryTasks(query.Term{Field: query.FieldLabel, Op: query.Equal, Value: "nonexistent"}, page).
+		Return(entity.TaskPage{}, errors.New("fetch error"))
+	result, err = taskService.GetTasksByTag([]string{"nonexistent"}, false, page)
 	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.Nil(t, result.Tasks)
 	assert.Equal(t, "fetch error", err.Error())
+
+	// No labels match no tasks
+	result, err = taskService.GetTasksByTag(nil, false, page)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Tasks)
+}
+
+func TestTaskService_QueryTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+	deadline := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	for _, task := range []entity.Task{
+		{Name: "Write report", Deadline: due(deadline), Priority: entity.PriorityHigh},
+		{Name: "Review report", Deadline: due(deadline), Priority: entity.PriorityHigh, Status: entity.StatusDone},
+		{Name: "Deploy", Deadline: due(deadline), Priority: entity.PriorityLess},
+	} {
+		require.NoError(t, repo.CreateTask(&task))
+	}
+
+	result, err := taskService.QueryTasks(`priority:high AND name~"report" AND NOT status:done`, time.UTC, page)
+	require.NoError(t, err)
+	if assert.Len(t, result.Tasks, 1) {
+		assert.Equal(t, "Write report", result.Tasks[0].Name)
+	}
+
+	// Invalid queries are reported with their position
+	_, err = taskService.QueryTasks(`priority:high AND status:paused`, time.UTC, page)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+	var queryErr *query.Error
+	if assert.ErrorAs(t, err, &queryErr) {
+		assert.Equal(t, 26, queryErr.Pos)
+	}
 }
 
 func TestTaskService_UpdateTask(t *testing.T) {
@@ -129,15 +216,18 @@ func TestTaskService_DeleteTask(t *testing.T) {
 
 	mockRepo := mocks.NewMockIRepo(ctrl)
 	taskService := TaskService{Repo: mockRepo}
+	passTransactions(mockRepo)
 
 	// Task successful deletion
-	mockRepo.EXPECT().DeleteTask(1).Return(nil)
-	err := taskService.DeleteTask(1)
+	mockRepo.EXPECT().GetSubtasks([]uint{1}).Return([]entity.Task{}, nil)
+	mockRepo.EXPECT().DeleteTask(1, uint(0)).Return(nil)
+	err := taskService.DeleteTask(1, 0)
 	assert.NoError(t, err)
 
 	// Task deletion error
-	mockRepo.EXPECT().DeleteTask(2).Return(errors.New("deletion error"))
-	err = taskService.DeleteTask(2)
+	mockRepo.EXPECT().GetSubtasks([]uint{2}).Return([]entity.Task{}, nil)
+	mockRepo.EXPECT().DeleteTask(2, uint(0)).Return(errors.New("deletion error"))
+	err = taskService.DeleteTask(2, 0)
 	assert.Error(t, err)
 	assert.Equal(t, "deletion error", err.Error())
 }
@@ -148,19 +238,20 @@ func TestTaskService_SearchTasksByName(t *testing.T) {
 
 	mockRepo := mocks.NewMockIRepo(ctrl)
 	taskService := TaskService{Repo: mockRepo}
-	tasks := []entity.Task{{ID: 1, Name: "Test Task"}}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Test Task"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
 
 	// Task successful search
-	mockRepo.EXPECT().SearchTasksByName("Test").Return(tasks, nil)
-	result, err := taskService.SearchTasksByName("Test")
+	mockRepo.EXPECT().SearchTasksByName("Test", page).Return(tasks, nil)
+	result, err := taskService.SearchTasksByName("Test", page)
 	assert.NoError(t, err)
 	assert.Equal(t, tasks, result)
 
 	// Task search error
-	mockRepo.EXPECT().SearchTasksByName("Error").Return(nil, errors.New("search error"))
-	result, err = taskService.SearchTasksByName("Error")
+	mockRepo.EXPECT().SearchTasksByName("Error", page).Return(entity.TaskPage{}, errors.New("search error"))
+	result, err = taskService.SearchTasksByName("Error", page)
 	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.Nil(t, result.Tasks)
 	assert.Equal(t, "search error", err.Error())
 }
 
@@ -172,18 +263,1741 @@ func TestTaskService_FilterTasksByDeadline(t *testing.T) {
 	taskService := TaskService{Repo: mockRepo}
 	start := time.Now()
 	end := start.Add(24 * time.Hour)
-	tasks := []entity.Task{{ID: 1, Name: "Task within deadline"}}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, Name: "Task within deadline"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "deadline"}
 
 	// Task successful filtering
-	mockRepo.EXPECT().FilterTasksByDeadline(start, end).Return(tasks, nil)
-	result, err := taskService.FilterTasksByDeadline(start, end)
+	between := query.And{
+		Left:  query.Term{Field: query.FieldDeadline, Op: query.GreaterEqual, Time: start},
+		Right: query.Term{Field: query.FieldDeadline, Op: query.Less, Time: end.AddDate(0, 0, 1)},
+	}
+	mockRepo.EXPECT().QueryTasks(between, page).Return(tasks, nil)
+	result, err := taskService.FilterTasksByDeadline(start, end, page)
 	assert.NoError(t, err)
 	assert.Equal(t, tasks, result)
 
 	// Task filtering error
-	mockRepo.EXPECT().FilterTasksByDeadline(start, end).Return(nil, errors.New("filtering error"))
-	result, err = taskService.FilterTasksByDeadline(start, end)
+	mockRepo.EXPECT().QueryTasks(between, page).Return(entity.TaskPage{}, errors.New("filtering error"))
+	result, err = taskService.FilterTasksByDeadline(start, end, page)
 	assert.Error(t, err)
-	assert.Nil(t, result)
+	assert.Nil(t, result.Tasks)
 	assert.Equal(t, "filtering error", err.Error())
 }
+
+func TestTaskService_FilterTasksByDeadlineEndDay(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	start := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	tasks := []*entity.Task{
+		{Name: "First day", Deadline: due(start), Priority: "medium"},
+		{Name: "Last day", Deadline: due(time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC)), Priority: "medium"},
+		{Name: "Next year", Deadline: due(end.AddDate(0, 0, 1)), Priority: "medium"},
+	}
+	for _, task := range tasks {
+		require.NoError(t, taskService.CreateTask(task))
+	}
+
+	// Both dates are included, the end date with its whole day
+	result, err := taskService.FilterTasksByDeadline(start, end, entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	require.Len(t, result.Tasks, 2)
+	assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID}, []uint{result.Tasks[0].ID, result.Tasks[1].ID})
+}
+
+func TestTaskService_CreateTaskInList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	mockLists := mocks.NewMockIListRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo, Lists: mockLists}
+
+	// Task in an existing list
+	task := &entity.Task{Name: "Test Task", ListID: 1}
+	mockLists.EXPECT().GetListById(1).Return(entity.List{ID: 1}, nil)
+	mockRepo.EXPECT().CreateTask(task).Return(nil)
+	assert.NoError(t, taskService.CreateTask(task))
+
+	// Task in a missing list is rejected before reaching the repository
+	task = &entity.Task{Name: "Test Task", ListID: 2}
+	mockLists.EXPECT().GetListById(2).Return(entity.List{}, gorm.ErrRecordNotFound)
+	assert.Equal(t, ErrListNotFound, taskService.CreateTask(task))
+}
+
+func TestTaskService_GetTasksByListId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	mockLists := mocks.NewMockIListRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo, Lists: mockLists}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, ListID: 1, Name: "Task 1"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
+	mockLists.EXPECT().GetListById(1).Return(entity.List{ID: 1}, nil)
+	mockRepo.EXPECT().GetTasksByListId(1, page).Return(tasks, nil)
+	result, err := taskService.GetTasksByListId(1, page)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+
+	// Missing list
+	mockLists.EXPECT().GetListById(2).Return(entity.List{}, gorm.ErrRecordNotFound)
+	result, err = taskService.GetTasksByListId(2, page)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.Nil(t, result.Tasks)
+}
+
+func TestTaskService_SearchListTasksByName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	mockLists := mocks.NewMockIListRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo, Lists: mockLists}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, ListID: 1, Name: "Test Task"}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
+	mockLists.EXPECT().GetListById(1).Return(entity.List{ID: 1}, nil)
+	mockRepo.EXPECT().SearchListTasksByName(1, "Test", page).Return(tasks, nil)
+	result, err := taskService.SearchListTasksByName(1, "Test", page)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+}
+
+func TestTaskService_CreateTaskDefaultsStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo}
+
+	// New tasks start as todo
+	task := &entity.Task{Name: "Test Task"}
+	mockRepo.EXPECT().CreateTask(task).Return(nil)
+	assert.NoError(t, taskService.CreateTask(task))
+	assert.Equal(t, entity.StatusTodo, task.Status)
+	assert.Nil(t, task.CompletedAt)
+
+	// Unknown status is rejected
+	task = &entity.Task{Name: "Test Task", Status: "finished"}
+	assert.Equal(t, ErrInvalidStatus, taskService.CreateTask(task))
+}
+
+func TestTaskService_TransitionTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo}
+	passTransactions(mockRepo)
+	// stored answers like the repository, with the task as stored after the transition
+	stored := func(id int, version uint, status string, completedAt *time.Time) (entity.Task, error) {
+		return entity.Task{ID: uint(id), Status: status, CompletedAt: completedAt, Version: version + 1}, nil
+	}
+
+	// Completing an open task records the completion time
+	mockRepo.EXPECT().GetTaskById(1).Return(entity.Task{ID: 1, Status: entity.StatusInProgress, Version: 3}, nil)
+	mockRepo.EXPECT().GetDependencies([]uint{1}).Return([]entity.Dependency{}, nil)
+	mockRepo.EXPECT().GetSubtasks([]uint{1}).Return([]entity.Task{}, nil)
+	mockRepo.EXPECT().UpdateTaskStatus(1, uint(3), entity.StatusDone, gomock.Not(gomock.Nil())).DoAndReturn(stored)
+	result, err := taskService.TransitionTask(1, entity.StatusDone)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusDone, result.Status)
+	assert.NotNil(t, result.CompletedAt)
+	assert.Equal(t, uint(4), result.Version)
+
+	// Reopening clears the completion time
+	completed := time.Now()
+	mockRepo.EXPECT().GetTaskById(2).Return(entity.Task{ID: 2, Status: entity.StatusDone, CompletedAt: &completed, Version: 1}, nil)
+	mockRepo.EXPECT().UpdateTaskStatus(2, uint(1), entity.StatusTodo, nil).DoAndReturn(stored)
+	result, err = taskService.TransitionTask(2, entity.StatusTodo)
+	assert.NoError(t, err)
+	assert.Nil(t, result.CompletedAt)
+
+	// Illegal transition is rejected without touching the repository
+	mockRepo.EXPECT().GetTaskById(3).Return(entity.Task{ID: 3, Status: entity.StatusDone}, nil)
+	_, err = taskService.TransitionTask(3, entity.StatusInProgress)
+	assert.Equal(t, ErrIllegalTransition, err)
+
+	// Task not found
+	mockRepo.EXPECT().GetTaskById(4).Return(entity.Task{}, gorm.ErrRecordNotFound)
+	_, err = taskService.TransitionTask(4, entity.StatusDone)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestTaskService_GetAllTasksByStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo}
+	tasks := entity.TaskPage{Tasks: []entity.Task{{ID: 1, Status: entity.StatusDone}}}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
+	mockRepo.EXPECT().GetAllTasks(entity.StatusDone, page).Return(tasks, nil)
+	result, err := taskService.GetAllTasks(entity.StatusDone, page)
+	assert.NoError(t, err)
+	assert.Equal(t, tasks, result)
+
+	_, err = taskService.GetAllTasks("finished", page)
+	assert.Equal(t, ErrInvalidStatus, err)
+}
+
+func TestTaskService_PatchTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo}
+	deadline := time.Date(2024, 10, 22, 17, 0, 0, 0, time.UTC)
+	current := entity.Task{ID: 1, Name: "Report", Deadline: due(deadline), Priority: "medium", Status: entity.StatusTodo, Version: 2}
+
+	// Merge patch only writes the deadline
+	newDeadline := time.Date(2024, 11, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	patched := current
+	patched.Deadline = due(newDeadline)
+	patched.Version = 3
+	mockRepo.EXPECT().PatchTask(1, uint(2), map[string]interface{}{"deadline": due(newDeadline)}).Return(patched, nil)
+	result, err := taskService.PatchTask(1, 0, MergePatchType, []byte(`{"deadline": "2024-11-01T09:00:00Z"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Report", result.Name)
+	assert.True(t, newDeadline.Equal(*result.Deadline))
+	assert.Equal(t, uint(3), result.Version)
+
+	// Outdated If-Match version
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	_, err = taskService.PatchTask(1, 1, MergePatchType, []byte(`{"name": "x"}`))
+	assert.Equal(t, ErrVersionConflict, err)
+
+	// JSON patch with a passing test operation
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	patched = current
+	patched.Name = "Final report"
+	patched.Priority = "high"
+	patched.Version = 3
+	mockRepo.EXPECT().PatchTask(1, uint(2), map[string]interface{}{"name": "Final report", "priority": "high"}).Return(patched, nil)
+	result, err = taskService.PatchTask(1, 0, JSONPatchType, []byte(`[
+		{"op": "test", "path": "/name", "value": "Report"},
+		{"op": "replace", "path": "/name", "value": "Final report"},
+		{"op": "replace", "path": "/priority", "value": "high"}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Final report", result.Name)
+
+	// Failing test operation
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	_, err = taskService.PatchTask(1, 0, JSONPatchType, []byte(`[{"op": "test", "path": "/name", "value": "Other"}]`))
+	assert.ErrorIs(t, err, ErrPatchFailed)
+
+	// Status is only changed through transitions
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	_, err = taskService.PatchTask(1, 0, MergePatchType, []byte(`{"status": "done"}`))
+	assert.ErrorIs(t, err, ErrReadOnlyField)
+
+	// Patched task must still be valid
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	_, err = taskService.PatchTask(1, 0, MergePatchType, []byte(`{"priority": "urgent"}`))
+	assert.ErrorIs(t, err, ErrInvalidTask)
+
+	// Malformed document
+	mockRepo.EXPECT().GetTaskById(1).Return(current, nil)
+	_, err = taskService.PatchTask(1, 0, MergePatchType, []byte(`{"name":`))
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+
+	// Missing task
+	mockRepo.EXPECT().GetTaskById(2).Return(entity.Task{}, gorm.ErrRecordNotFound)
+	_, err = taskService.PatchTask(2, 0, MergePatchType, []byte(`{"name": "x"}`))
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestTaskService_RestoreTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	mockLists := mocks.NewMockIListRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo, Lists: mockLists}
+
+	// Task returns to its list
+	mockRepo.EXPECT().RestoreTask(1).Return(nil)
+	mockRepo.EXPECT().GetTaskById(1).Return(entity.Task{ID: 1, ListID: 2, Version: 4}, nil)
+	mockLists.EXPECT().GetListById(2).Return(entity.List{ID: 2}, nil)
+	task, err := taskService.RestoreTask(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), task.ListID)
+
+	// Task whose list was deleted is restored without a list
+	mockRepo.EXPECT().RestoreTask(1).Return(nil)
+	mockRepo.EXPECT().GetTaskById(1).Return(entity.Task{ID: 1, ListID: 3, Version: 4}, nil)
+	mockLists.EXPECT().GetListById(3).Return(entity.List{}, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().PatchTask(1, uint(4), map[string]interface{}{"list_id": 0}).Return(entity.Task{ID: 1, Version: 5}, nil)
+	task, err = taskService.RestoreTask(1)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), task.ListID)
+	assert.Equal(t, uint(5), task.Version)
+
+	// Task that is not in the trash
+	mockRepo.EXPECT().RestoreTask(9).Return(gorm.ErrRecordNotFound)
+	_, err = taskService.RestoreTask(9)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestTaskService_PurgeTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIRepo(ctrl)
+	taskService := TaskService{Repo: mockRepo}
+
+	// Only tasks deleted before the retention window are purged
+	mockRepo.EXPECT().PurgeTrash(gomock.Any()).DoAndReturn(func(before time.Time) (int64, error) {
+		assert.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+		return 2, nil
+	})
+	purged, err := taskService.PurgeTrash(24 * time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
+
+func TestTaskService_CreateTaskRecurrence(t *testing.T) {
+	taskService := TaskService{Repo: repositories.NewMemoryRepository()}
+
+	// Rules are stored in their canonical form
+	task := &entity.Task{Name: "Standup", Deadline: due(time.Now()), Priority: "less", Recurrence: "rrule:freq=weekly;byday=mo,we"}
+	assert.NoError(t, taskService.CreateTask(task))
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,WE", task.Recurrence)
+
+	task = &entity.Task{Name: "Standup", Deadline: due(time.Now()), Priority: "less", Recurrence: "FREQ=YEARLY"}
+	assert.ErrorIs(t, taskService.CreateTask(task), ErrInvalidRecurrence)
+
+	// A series counts from the deadline, tasks without one cannot repeat
+	task = &entity.Task{Name: "Standup", Priority: "less", Recurrence: "FREQ=DAILY"}
+	assert.ErrorIs(t, taskService.CreateTask(task), ErrInvalidRecurrence)
+	task = &entity.Task{Name: "Someday", Priority: "less"}
+	assert.NoError(t, taskService.CreateTask(task))
+	assert.Nil(t, task.Deadline)
+}
+
+func TestTaskService_CompleteRecurringTask(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	monday := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	task := &entity.Task{ListID: 0, Name: "Report", Deadline: due(monday), Priority: "high", Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=2"}
+	assert.NoError(t, taskService.CreateTask(task))
+
+	// Completing the task hands the rule over to the next occurrence
+	done, err := taskService.TransitionTask(int(task.ID), entity.StatusDone)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusDone, done.Status)
+	assert.Empty(t, done.Recurrence)
+
+	open, err := repo.GetAllTasks(entity.StatusTodo, entity.PageRequest{Limit: 10, Sort: "id"})
+	assert.NoError(t, err)
+	if assert.Len(t, open.Tasks, 1) {
+		next := open.Tasks[0]
+		assert.Equal(t, "Report", next.Name)
+		assert.Equal(t, "high", next.Priority)
+		assert.True(t, monday.AddDate(0, 0, 3).Equal(*next.Deadline))
+		assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=1", next.Recurrence)
+
+		// The last occurrence of the series creates no further task
+		_, err = taskService.TransitionTask(int(next.ID), entity.StatusDone)
+		assert.NoError(t, err)
+	}
+
+	open, err = repo.GetAllTasks(entity.StatusTodo, entity.PageRequest{Limit: 10, Sort: "id"})
+	assert.NoError(t, err)
+	assert.Empty(t, open.Tasks)
+
+	// Reopening a completed occurrence does not restart the series
+	_, err = taskService.TransitionTask(int(task.ID), entity.StatusTodo)
+	assert.NoError(t, err)
+	_, err = taskService.TransitionTask(int(task.ID), entity.StatusDone)
+	assert.NoError(t, err)
+	all, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+	assert.NoError(t, err)
+	assert.Len(t, all.Tasks, 2)
+}
+
+func TestTaskService_CompleteRecurringTaskRollsBack(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	monday := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	task := entity.Task{Name: "Report", Deadline: due(monday), Priority: "high", Recurrence: "FREQ=WEEKLY"}
+	require.NoError(t, (&TaskService{Repo: repo}).CreateTask(&task))
+
+	// When the next occurrence cannot be created, the task is not completed either and keeps its rule
+	taskService := TaskService{Repo: failingRepo{IRepo: repo, failCreate: true}}
+	_, err := taskService.TransitionTask(int(task.ID), entity.StatusDone)
+	assert.EqualError(t, err, "creation error")
+	stored, err := repo.GetTaskById(int(task.ID))
+	require.NoError(t, err)
+	assert.Equal(t, task, stored)
+}
+
+func TestTaskService_GetOccurrences(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	monday := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	standup := &entity.Task{Name: "Standup", Deadline: due(monday), Priority: "less", Recurrence: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR"}
+	review := &entity.Task{Name: "Review", Deadline: due(monday.AddDate(0, 0, 2)), Priority: "high"}
+	old := &entity.Task{Name: "Retro", Deadline: due(monday.AddDate(0, 0, -14)), Priority: "medium", Recurrence: "FREQ=WEEKLY;INTERVAL=2"}
+	assert.NoError(t, taskService.CreateTask(standup))
+	assert.NoError(t, taskService.CreateTask(review))
+	assert.NoError(t, taskService.CreateTask(old))
+
+	occurrences, err := taskService.GetOccurrences(monday, monday.AddDate(0, 0, 4))
+	assert.NoError(t, err)
+
+	type occurrence struct {
+		ID       uint
+		Deadline time.Time
+		Virtual  bool
+	}
+	var got []occurrence
+	for _, o := range occurrences {
+		got = append(got, occurrence{o.ID, *o.Deadline, o.Virtual})
+	}
+	assert.Equal(t, []occurrence{
+		{standup.ID, monday, false},
+		{old.ID, monday, true},
+		{standup.ID, monday.AddDate(0, 0, 1), true},
+		{standup.ID, monday.AddDate(0, 0, 2), true},
+		{review.ID, monday.AddDate(0, 0, 2), false},
+		{standup.ID, monday.AddDate(0, 0, 3), true},
+		{standup.ID, monday.AddDate(0, 0, 4), true},
+	}, got)
+	assert.Equal(t, uint(0), occurrences[1].Version)
+
+	// The end date includes its whole day
+	friday := time.Date(2026, 11, 6, 0, 0, 0, 0, time.UTC)
+	occurrences, err = taskService.GetOccurrences(friday, friday)
+	assert.NoError(t, err)
+	if assert.Len(t, occurrences, 1) {
+		assert.True(t, monday.AddDate(0, 0, 4).Equal(*occurrences[0].Deadline))
+	}
+}
+
+func TestTaskService_Subtasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	day := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	create := func(parentId uint, name string, deadline time.Time) entity.Task {
+		task := entity.Task{ParentID: parentId, Name: name, Deadline: due(deadline), Priority: "medium"}
+		require.NoError(t, taskService.CreateTask(&task))
+		return task
+	}
+	release := create(0, "Release", day.AddDate(0, 0, 10))
+	docs := create(release.ID, "Docs", day.AddDate(0, 0, 5))
+	create(docs.ID, "Changelog", day.AddDate(0, 0, 3))
+	guide := create(docs.ID, "Upgrade guide", day.AddDate(0, 0, 1))
+	build := create(release.ID, "Build", day.AddDate(0, 0, 2))
+
+	_, err := taskService.TransitionTask(int(guide.ID), entity.StatusDone)
+	require.NoError(t, err)
+
+	// The tree rolls up all subtasks below each task
+	tree, err := taskService.GetTaskTree(int(release.ID))
+	require.NoError(t, err)
+	assert.Equal(t, 4, tree.Progress.Total)
+	assert.Equal(t, 1, tree.Progress.Done)
+	assert.Equal(t, 25, tree.Progress.Percent)
+	assert.True(t, day.AddDate(0, 0, 2).Equal(*tree.Progress.EarliestDeadline), "the done subtask does not count")
+	if assert.Len(t, tree.Subtasks, 2) {
+		assert.Equal(t, docs.ID, tree.Subtasks[0].ID)
+		assert.Len(t, tree.Subtasks[0].Subtasks, 2)
+		assert.Equal(t, 50, tree.Subtasks[0].Progress.Percent)
+		assert.Empty(t, tree.Subtasks[1].Subtasks)
+	}
+
+	// Cycles and unknown parents are rejected
+	release.ParentID = guide.ID
+	assert.Equal(t, ErrTaskCycle, taskService.UpdateTask(&release))
+	_, err = taskService.PatchTask(int(docs.ID), 0, MergePatchType, []byte(fmt.Sprintf(`{"parent_id": %d}`, docs.ID)))
+	assert.Equal(t, ErrTaskCycle, err)
+	assert.Equal(t, ErrParentNotFound, taskService.CreateTask(&entity.Task{ParentID: 99, Name: "Orphan", Deadline: due(day), Priority: "less"}))
+
+	// A blocked subtask keeps its parent from being completed
+	_, err = taskService.TransitionTask(int(build.ID), entity.StatusBlocked)
+	require.NoError(t, err)
+	_, err = taskService.TransitionTask(int(release.ID), entity.StatusDone)
+	assert.ErrorIs(t, err, ErrIllegalTransition)
+	_, err = taskService.TransitionTask(int(build.ID), entity.StatusInProgress)
+	require.NoError(t, err)
+
+	// Completing a parent completes its open subtasks
+	_, err = taskService.TransitionTask(int(release.ID), entity.StatusDone)
+	require.NoError(t, err)
+	tree, err = taskService.GetTaskTree(int(release.ID))
+	require.NoError(t, err)
+	assert.Equal(t, 100, tree.Progress.Percent)
+	assert.Nil(t, tree.Progress.EarliestDeadline)
+
+	// Deleting a parent moves its subtasks to the trash, and a restored subtask is detached
+	require.NoError(t, taskService.DeleteTask(int(docs.ID), 0))
+	trash, err := taskService.GetTrash(entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Len(t, trash.Tasks, 3)
+
+	restored, err := taskService.RestoreTask(int(guide.ID))
+	require.NoError(t, err)
+	assert.Zero(t, restored.ParentID)
+}
+
+func TestTaskService_TransitionTaskRollsBack(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	create := func(parentId uint, name string) entity.Task {
+		task := entity.Task{ParentID: parentId, Name: name, Priority: "medium"}
+		require.NoError(t, (&TaskService{Repo: repo}).CreateTask(&task))
+		return task
+	}
+	release := create(0, "Release")
+	docs := create(release.ID, "Docs")
+	changelog := create(docs.ID, "Changelog")
+	build := create(release.ID, "Build")
+
+	// The status write of the parent fails after its subtasks were completed, which undoes them
+	taskService := TaskService{Repo: failingRepo{IRepo: repo, failStatus: int(release.ID)}}
+	_, err := taskService.TransitionTask(int(release.ID), entity.StatusDone)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	for _, task := range []entity.Task{release, docs, changelog, build} {
+		stored, err := repo.GetTaskById(int(task.ID))
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusTodo, stored.Status, task.Name)
+		assert.Nil(t, stored.CompletedAt, task.Name)
+		assert.Equal(t, task.Version, stored.Version, task.Name)
+	}
+
+	// Likewise when a subtask fails after others were completed
+	taskService = TaskService{Repo: failingRepo{IRepo: repo, failStatus: int(docs.ID)}}
+	_, err = taskService.TransitionTask(int(release.ID), entity.StatusCancelled)
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	stored, err := repo.GetTaskById(int(changelog.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusTodo, stored.Status)
+}
+
+func TestTaskService_CompleteParentWithBlockedSubtask(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	day := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	create := func(parentId uint, name string) entity.Task {
+		task := entity.Task{ParentID: parentId, Name: name, Deadline: due(day), Priority: "medium"}
+		require.NoError(t, taskService.CreateTask(&task))
+		return task
+	}
+	release := create(0, "Release")
+	docs := create(release.ID, "Docs")
+	build := create(release.ID, "Build")
+	design := create(0, "Design")
+	require.NoError(t, taskService.AddBlocker(int(build.ID), int(design.ID)))
+
+	// A subtask waiting for an open blocker keeps its parent from being completed, and nothing changes
+	_, err := taskService.TransitionTask(int(release.ID), entity.StatusDone)
+	assert.ErrorIs(t, err, ErrBlocked)
+	for _, task := range []entity.Task{release, docs, build} {
+		stored, err := taskService.GetTaskById(int(task.ID))
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusTodo, stored.Status, task.Name)
+		assert.Equal(t, task.Version, stored.Version, task.Name)
+	}
+
+	// Once the blocker is done, the parent completes with its subtasks
+	_, err = taskService.TransitionTask(int(design.ID), entity.StatusDone)
+	require.NoError(t, err)
+	_, err = taskService.TransitionTask(int(release.ID), entity.StatusDone)
+	require.NoError(t, err)
+	stored, err := taskService.GetTaskById(int(build.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusDone, stored.Status)
+}
+
+func TestTaskService_Dependencies(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo}
+	day := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+
+	create := func(name string, deadline time.Time) entity.Task {
+		task := entity.Task{Name: name, Deadline: due(deadline), Priority: "medium"}
+		require.NoError(t, taskService.CreateTask(&task))
+		return task
+	}
+	design := create("Design", day.AddDate(0, 0, 3))
+	build := create("Build", day.AddDate(0, 0, 2))
+	ship := create("Ship", day.AddDate(0, 0, 5))
+	create("Docs", day.AddDate(0, 0, 1))
+
+	require.NoError(t, taskService.AddBlocker(int(build.ID), int(design.ID)))
+	require.NoError(t, taskService.AddBlocker(int(ship.ID), int(build.ID)))
+
+	// Cycles, direct or through other tasks, are rejected
+	assert.Equal(t, ErrDependencyCycle, taskService.AddBlocker(int(design.ID), int(ship.ID)))
+	assert.Equal(t, ErrDependencyCycle, taskService.AddBlocker(int(design.ID), int(design.ID)))
+	assert.Equal(t, ErrBlockerNotFound, taskService.AddBlocker(int(design.ID), 99))
+	assert.Equal(t, gorm.ErrRecordNotFound, taskService.AddBlocker(99, int(design.ID)))
+
+	// Blockers come first, and a task due before its blocker is flagged
+	plan, err := taskService.GetPlan(0)
+	require.NoError(t, err)
+	var order []string
+	for _, item := range plan {
+		order = append(order, item.Name)
+	}
+	assert.Equal(t, []string{"Docs", "Design", "Build", "Ship"}, order)
+	assert.Equal(t, []uint{design.ID}, plan[2].BlockedBy)
+	assert.True(t, plan[2].DeadlineConflict)
+	assert.False(t, plan[3].DeadlineConflict)
+
+	// A task cannot start until its blockers are done
+	_, err = taskService.TransitionTask(int(build.ID), entity.StatusInProgress)
+	assert.Equal(t, ErrBlocked, err)
+	_, err = taskService.TransitionTask(int(design.ID), entity.StatusDone)
+	require.NoError(t, err)
+	_, err = taskService.TransitionTask(int(build.ID), entity.StatusInProgress)
+	assert.NoError(t, err)
+
+	// Done tasks leave the plan
+	plan, err = taskService.GetPlan(0)
+	require.NoError(t, err)
+	assert.Len(t, plan, 3)
+	assert.Empty(t, plan[1].BlockedBy)
+
+	require.NoError(t, taskService.RemoveBlocker(int(ship.ID), int(build.ID)))
+	blockers, err := taskService.GetBlockers(int(ship.ID))
+	require.NoError(t, err)
+	assert.Empty(t, blockers)
+}
+
+func TestTaskService_BulkTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	monday := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	report := &entity.Task{Name: "Report", Deadline: due(monday), Priority: "high"}
+	review := &entity.Task{Name: "Review", Deadline: due(monday), Priority: "medium"}
+	require.NoError(t, taskService.CreateTask(report))
+	require.NoError(t, taskService.CreateTask(review))
+	all := func() []entity.Task {
+		result, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+		require.NoError(t, err)
+		return result.Tasks
+	}
+
+	// Without atomic every operation succeeds or fails on its own
+	results, err := taskService.BulkTasks(entity.BulkRequest{Operations: []entity.BulkOperation{
+		{Op: entity.BulkCreate, Task: &entity.Task{Name: "Tests", Deadline: due(monday)}},
+		{Op: entity.BulkUpdate, ID: report.ID, Patch: []byte(`{"priority":"urgent"}`)},
+		{Op: entity.BulkUpdate, ID: review.ID, Version: review.Version, Patch: []byte(`{"deadline":"2026-11-05T09:00:00Z"}`)},
+		{Op: entity.BulkComplete, ID: report.ID},
+		{Op: entity.BulkDelete, ID: 99},
+	}}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+	if assert.NotNil(t, results[0].Task) {
+		assert.Equal(t, "medium", results[0].Task.Priority)
+		assert.Equal(t, results[0].Task.ID, results[0].ID)
+	}
+	assert.ErrorIs(t, results[1].Err, ErrInvalidTask)
+	assert.Nil(t, results[1].Task)
+	assert.NoError(t, results[2].Err)
+	assert.NoError(t, results[3].Err)
+	assert.Equal(t, entity.StatusDone, results[3].Task.Status)
+	assert.Equal(t, gorm.ErrRecordNotFound, results[4].Err)
+	tasks := all()
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "high", tasks[0].Priority)
+	assert.True(t, monday.AddDate(0, 0, 3).Equal(*tasks[1].Deadline))
+
+	// An atomic request stops at the first failure and changes nothing
+	_, err = taskService.BulkTasks(entity.BulkRequest{Atomic: true, Operations: []entity.BulkOperation{
+		{Op: entity.BulkCreate, Task: &entity.Task{Name: "Docs"}},
+		{Op: entity.BulkDelete, ID: review.ID},
+		{Op: entity.BulkUpdate, ID: review.ID, Patch: []byte(`{"name":"Review again"}`)},
+	}}, time.UTC)
+	var bulkErr *BulkError
+	if assert.ErrorAs(t, err, &bulkErr) {
+		assert.Equal(t, 2, bulkErr.Index)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	}
+	assert.Equal(t, tasks, all())
+
+	results, err = taskService.BulkTasks(entity.BulkRequest{Atomic: true, Operations: []entity.BulkOperation{
+		{Op: entity.BulkCreate, Task: &entity.Task{Name: "Docs"}},
+		{Op: entity.BulkDelete, ID: review.ID, Version: tasks[1].Version},
+	}}, time.UTC)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Len(t, all(), 3)
+}
+
+func TestTaskService_BulkTasksFilter(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Daylight saving time ends in Berlin on 2026-10-25, the shifted deadlines stay at 9:00 there
+	friday := time.Date(2026, 10, 23, 9, 0, 0, 0, berlin)
+	tasks := []*entity.Task{
+		{Name: "Report", Deadline: due(friday.UTC()), Priority: "medium"},
+		{Name: "Review", Deadline: due(friday.UTC()), Priority: "high"},
+		{Name: "Someday", Priority: "medium"},
+		{Name: "Tests", Deadline: due(friday.UTC().AddDate(0, 0, 1)), Priority: "medium"},
+	}
+	for _, task := range tasks {
+		require.NoError(t, taskService.CreateTask(task))
+	}
+
+	results, err := taskService.BulkTasks(entity.BulkRequest{
+		Filter: "priority:medium",
+		Action: &entity.BulkOperation{Op: entity.BulkShiftDeadline, Days: 3},
+	}, berlin)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, []uint{tasks[0].ID, tasks[3].ID}, []uint{results[0].ID, results[1].ID})
+	assert.True(t, time.Date(2026, 10, 26, 9, 0, 0, 0, berlin).Equal(*results[0].Task.Deadline))
+	assert.True(t, time.Date(2026, 10, 27, 9, 0, 0, 0, berlin).Equal(*results[1].Task.Deadline))
+
+	review, err := repo.GetTaskById(int(tasks[1].ID))
+	require.NoError(t, err)
+	assert.True(t, friday.Equal(*review.Deadline))
+
+	// Filters use the query language and any action but create
+	results, err = taskService.BulkTasks(entity.BulkRequest{
+		Atomic: true,
+		Filter: "deadline:none OR name:review",
+		Action: &entity.BulkOperation{Op: entity.BulkComplete},
+	}, berlin)
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+	open, err := repo.GetAllTasks(entity.StatusTodo, entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Len(t, open.Tasks, 2)
+
+	_, err = taskService.BulkTasks(entity.BulkRequest{Filter: "priority:urgent", Action: &entity.BulkOperation{Op: entity.BulkDelete}}, berlin)
+	var queryErr *query.Error
+	assert.ErrorAs(t, err, &queryErr)
+}
+
+func TestTaskService_BulkTasksFilterSubtasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	create := func(parentId uint, name, priority string) *entity.Task {
+		task := entity.Task{ParentID: parentId, Name: name, Priority: priority}
+		require.NoError(t, taskService.CreateTask(&task))
+		return &task
+	}
+	release := create(0, "Release", "high")
+	notes := create(release.ID, "Notes", "high")
+	docs := create(release.ID, "Docs", "less")
+	draft := create(docs.ID, "Draft", "high")
+	other := create(0, "Other", "high")
+
+	// The cascade from a matched parent covers its matched subtasks, at any depth
+	results, err := taskService.BulkTasks(entity.BulkRequest{
+		Atomic: true,
+		Filter: "priority:high",
+		Action: &entity.BulkOperation{Op: entity.BulkComplete},
+	}, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{release.ID, other.ID}, []uint{results[0].ID, results[1].ID})
+	for _, task := range []*entity.Task{release, notes, docs, draft, other} {
+		stored, err := repo.GetTaskById(int(task.ID))
+		require.NoError(t, err)
+		assert.Equal(t, entity.StatusDone, stored.Status, task.Name)
+	}
+
+	results, err = taskService.BulkTasks(entity.BulkRequest{
+		Filter: "priority:high",
+		Action: &entity.BulkOperation{Op: entity.BulkDelete},
+	}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+	for _, task := range []*entity.Task{release, notes, docs, draft, other} {
+		_, err := repo.GetTaskById(int(task.ID))
+		assert.Equal(t, gorm.ErrRecordNotFound, err, task.Name)
+	}
+}
+
+func TestTaskService_BulkTasksValidation(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	shift := &entity.BulkOperation{Op: entity.BulkShiftDeadline, Days: 3}
+
+	for _, request := range []entity.BulkRequest{
+		{},
+		{Operations: make([]entity.BulkOperation, MaxBulkOperations+1)},
+		{Operations: []entity.BulkOperation{{Op: "archive", ID: 1}}},
+		{Operations: []entity.BulkOperation{{Op: entity.BulkCreate}}},
+		{Operations: []entity.BulkOperation{{Op: entity.BulkUpdate, ID: 1}}},
+		{Operations: []entity.BulkOperation{{Op: entity.BulkDelete}}},
+		{Operations: []entity.BulkOperation{{Op: entity.BulkShiftDeadline, ID: 1, Days: 3}}},
+		{Filter: "priority:medium"},
+		{Action: shift},
+		{Filter: "priority:medium", Action: &entity.BulkOperation{Op: entity.BulkShiftDeadline}},
+		{Filter: "priority:medium", Action: &entity.BulkOperation{Op: entity.BulkDelete, ID: 1}},
+		{Filter: "priority:medium", Action: &entity.BulkOperation{Op: entity.BulkCreate, Task: &entity.Task{Name: "Docs"}}},
+		{Filter: "priority:medium", Action: shift, Operations: []entity.BulkOperation{{Op: entity.BulkDelete, ID: 1}}},
+	} {
+		_, err := taskService.BulkTasks(request, time.UTC)
+		assert.ErrorIs(t, err, ErrInvalidBulk, fmt.Sprintf("%+v", request))
+	}
+
+	for i := 0; i <= MaxBulkOperations; i++ {
+		require.NoError(t, repo.CreateTask(&entity.Task{Name: "Task", Priority: "medium", Status: entity.StatusTodo}))
+	}
+	_, err := taskService.BulkTasks(entity.BulkRequest{Filter: "priority:medium", Action: &entity.BulkOperation{Op: entity.BulkDelete}}, time.UTC)
+	assert.ErrorIs(t, err, ErrInvalidBulk)
+}
+
+func TestTaskService_ExportTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	monday := time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)
+	tasks := []*entity.Task{
+		{Name: "Write report", Deadline: due(monday), Priority: "high"},
+		{Name: "Review report", Deadline: due(monday.AddDate(0, 0, 7)), Priority: "medium"},
+		{Name: "Write tests", Priority: "less", Status: entity.StatusDone},
+	}
+	for _, task := range tasks {
+		require.NoError(t, taskService.CreateTask(task))
+	}
+	work := entity.Label{Name: "work", Color: entity.DefaultLabelColor}
+	require.NoError(t, repo.CreateLabel(&work))
+	require.NoError(t, repo.AttachLabel(tasks[0].ID, work.ID))
+	require.NoError(t, repo.AttachLabel(tasks[2].ID, work.ID))
+
+	ids := func(filter entity.TaskFilter) []uint {
+		exported, err := taskService.ExportTasks(filter, time.UTC)
+		require.NoError(t, err)
+		var ids []uint
+		for _, task := range exported {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	start := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 11, 3, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID, tasks[2].ID}, ids(entity.TaskFilter{}))
+	assert.Equal(t, []uint{tasks[0].ID, tasks[2].ID}, ids(entity.TaskFilter{Labels: []string{"work"}}))
+	assert.Equal(t, []uint{tasks[0].ID, tasks[2].ID}, ids(entity.TaskFilter{Keyword: "WRITE"}))
+	assert.Equal(t, []uint{tasks[0].ID}, ids(entity.TaskFilter{Start: &start, End: &end}))
+	// The end date includes its whole day
+	assert.Equal(t, []uint{tasks[0].ID}, ids(entity.TaskFilter{Start: &start, End: &monday}))
+	lastDay := monday.AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	assert.Equal(t, []uint{tasks[0].ID, tasks[1].ID}, ids(entity.TaskFilter{Start: &start, End: &lastDay}))
+	assert.Equal(t, []uint{tasks[2].ID}, ids(entity.TaskFilter{Labels: []string{"work"}, Status: entity.StatusDone}))
+	assert.Equal(t, []uint{tasks[1].ID}, ids(entity.TaskFilter{Query: "NOT label:work", Keyword: "report"}))
+
+	_, err := taskService.ExportTasks(entity.TaskFilter{Status: "finished"}, time.UTC)
+	assert.Equal(t, ErrInvalidStatus, err)
+	_, err = taskService.ExportTasks(entity.TaskFilter{Query: "priority:urgent"}, time.UTC)
+	var queryErr *query.Error
+	assert.ErrorAs(t, err, &queryErr)
+}
+
+func TestWriteTasksCSV(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	tasks := []entity.Task{
+		{ID: 1, Name: "Write report, draft", Deadline: due(time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)), Priority: "high", Status: entity.StatusTodo, ListID: 2, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Name: `Say "hi"`, Priority: "less", Status: entity.StatusDone, ParentID: 1, CompletedAt: &created, CreatedAt: created, UpdatedAt: created},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteTasksCSV(&out, tasks, berlin))
+	assert.Equal(t, "id,name,deadline,priority,status,list_id,parent_id,recurrence,completed_at,created_at,updated_at\n"+
+		`1,"Write report, draft",2026-11-02T10:00:00+01:00,high,todo,2,,,,2026-11-01T09:00:00+01:00,2026-11-01T09:00:00+01:00`+"\n"+
+		`2,"Say ""hi""",,less,done,,1,,2026-11-01T09:00:00+01:00,2026-11-01T09:00:00+01:00,2026-11-01T09:00:00+01:00`+"\n",
+		out.String())
+}
+
+func TestTaskService_CSVFormulas(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	names := []string{"=HYPERLINK(\"http://example.com\")", "+1", "-2", "@SUM(A1)", "\tTab", "'=quoted", "'plain", "a=b"}
+	for _, name := range names {
+		require.NoError(t, taskService.CreateTask(&entity.Task{Name: name, Priority: "medium"}))
+	}
+
+	// Cells a spreadsheet would run as formulas are escaped with an apostrophe
+	exported, err := taskService.ExportTasks(entity.TaskFilter{}, time.UTC)
+	require.NoError(t, err)
+	var out strings.Builder
+	require.NoError(t, WriteTasksCSV(&out, exported, time.UTC))
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	var cells []string
+	for _, record := range records[1:] {
+		cells = append(cells, record[1])
+	}
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://example.com\")", "'+1", "'-2", "'@SUM(A1)", "'\tTab", "''=quoted", "'plain", "a=b"}, cells)
+
+	// and import back unchanged
+	tasks, rowErrors, err := taskService.ImportTasksCSV(strings.NewReader(out.String()), entity.CSVImport{DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	var imported []string
+	for _, task := range tasks {
+		imported = append(imported, task.Name)
+	}
+	assert.Equal(t, names, imported)
+}
+
+func TestTaskService_ImportTasksCSV(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	list := entity.List{Name: "Work"}
+	require.NoError(t, repo.CreateList(&list))
+	stored := func() []entity.Task {
+		result, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+		require.NoError(t, err)
+		return result.Tasks
+	}
+
+	// Columns are mapped by name or explicitly, and the others are ignored
+	file := "\ufeffTitle,Due,Priority,Notes,list_id\n" +
+		"Write report,2026-11-02 09:00,HIGH,first draft," + fmt.Sprint(list.ID) + "\n" +
+		"\"Review\nreport\",2026-11-03T17:00:00Z,,,\n"
+	options := entity.CSVImport{Columns: map[string]string{"name": "title", "deadline": "Due"}, Location: berlin}
+
+	// A dry run checks the rows without storing them
+	options.DryRun = true
+	tasks, rowErrors, err := taskService.ImportTasksCSV(strings.NewReader(file), options)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, tasks, 2)
+	assert.Zero(t, tasks[0].ID)
+	assert.Equal(t, "high", tasks[0].Priority)
+	assert.Empty(t, stored())
+
+	options.DryRun = false
+	tasks, rowErrors, err = taskService.ImportTasksCSV(strings.NewReader(file), options)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, tasks, stored())
+	assert.Equal(t, "Write report", tasks[0].Name)
+	assert.True(t, time.Date(2026, 11, 2, 9, 0, 0, 0, berlin).Equal(*tasks[0].Deadline))
+	assert.Equal(t, list.ID, tasks[0].ListID)
+	assert.Equal(t, entity.StatusTodo, tasks[0].Status)
+	assert.Equal(t, "Review\nreport", tasks[1].Name)
+	assert.Equal(t, entity.PriorityMedium, tasks[1].Priority)
+
+	// Every rejected row is reported with its line, and nothing is stored
+	file = "name,deadline,priority,status,list_id,recurrence\n" +
+		"Valid,2026-11-02,less,,,\n" +
+		",2026-11-02,less,,,\n" +
+		"Bad date,next week,less,,,\n" +
+		"Bad priority,,urgent,,,\n" +
+		"Bad status,,,paused,,\n" +
+		"Bad list,,,,99,\n" +
+		"Bad rule,2026-11-02,,,,FREQ=HOURLY\n"
+	tasks, rowErrors, err = taskService.ImportTasksCSV(strings.NewReader(file), entity.CSVImport{})
+	require.NoError(t, err)
+	assert.Nil(t, tasks)
+	require.Len(t, rowErrors, 6)
+	for i, expected := range []struct {
+		line  int
+		field string
+		err   error
+	}{
+		{3, "name", ErrInvalidTask},
+		{4, "deadline", ErrInvalidTask},
+		{5, "priority", ErrInvalidPriority},
+		{6, "status", ErrInvalidStatus},
+		{7, "list_id", ErrListNotFound},
+		{8, "recurrence", ErrInvalidRecurrence},
+	} {
+		assert.Equal(t, expected.line, rowErrors[i].Line)
+		assert.Equal(t, expected.field, rowErrors[i].Field)
+		assert.ErrorIs(t, rowErrors[i].Err, expected.err)
+	}
+	assert.Len(t, stored(), 2)
+
+	// A malformed row ends the import at its line
+	_, rowErrors, err = taskService.ImportTasksCSV(strings.NewReader("name\nValid\nBad \"quote\n"), entity.CSVImport{})
+	require.NoError(t, err)
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 3, rowErrors[0].Line)
+	assert.ErrorIs(t, rowErrors[0].Err, ErrInvalidImport)
+}
+
+func TestTaskService_ImportTasksCSVInvalidFile(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+
+	for _, test := range []struct {
+		file    string
+		columns map[string]string
+	}{
+		{file: ""},
+		{file: "name\n"},
+		{file: "title,deadline\nReport,\n"},
+		{file: "name\nReport\n", columns: map[string]string{"owner": "name"}},
+		{file: "name\nReport\n", columns: map[string]string{"deadline": "Due"}},
+		{file: "name\n" + strings.Repeat("Report\n", MaxImportRows+1)},
+	} {
+		_, _, err := taskService.ImportTasksCSV(strings.NewReader(test.file), entity.CSVImport{Columns: test.columns})
+		assert.ErrorIs(t, err, ErrInvalidImport, test.file)
+	}
+}
+
+func TestTaskService_GetFeedTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	listService := ListService{Repo: repo}
+	backend := entity.List{Name: "Backend"}
+	require.NoError(t, repo.CreateList(&backend))
+	frontend := entity.List{Name: "Frontend"}
+	require.NoError(t, repo.CreateList(&frontend))
+	tasks := []*entity.Task{
+		{ListID: backend.ID, Name: "Write report", Priority: "high"},
+		{ListID: frontend.ID, Name: "Review report", Priority: "high"},
+		{ListID: backend.ID, Name: "Write tests", Priority: "less"},
+	}
+	for _, task := range tasks {
+		require.NoError(t, taskService.CreateTask(task))
+	}
+	require.NoError(t, taskService.DeleteTask(int(tasks[2].ID), 0))
+
+	token, err := listService.CreateListFeed(int(backend.ID))
+	require.NoError(t, err)
+	list, feedTasks, err := taskService.GetFeedTasks(token)
+	require.NoError(t, err)
+	assert.Equal(t, backend, list)
+	require.Len(t, feedTasks, 1)
+	assert.Equal(t, tasks[0].ID, feedTasks[0].ID)
+
+	// A new token replaces the old one, and a revoked feed is gone
+	renewed, err := listService.CreateListFeed(int(backend.ID))
+	require.NoError(t, err)
+	_, _, err = taskService.GetFeedTasks(token)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	require.NoError(t, listService.DeleteListFeed(int(backend.ID)))
+	_, _, err = taskService.GetFeedTasks(renewed)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestWriteTasksICal(t *testing.T) {
+	created := time.Date(2026, 11, 1, 8, 0, 0, 0, time.UTC)
+	updated := created.Add(90 * time.Minute)
+	deadline := time.Date(2026, 11, 2, 17, 0, 0, 0, time.UTC)
+	tasks := []entity.Task{
+		{ID: 1, UID: "task-1@todo-lists", Name: "Write report; draft, v2", Deadline: &deadline, Priority: "high", Status: entity.StatusInProgress, Version: 3, CreatedAt: created, UpdatedAt: updated},
+		{ID: 2, UID: "task-2@todo-lists", Name: "Proofread", Priority: "less", Status: entity.StatusDone, ParentID: 1, CompletedAt: &updated, Version: 1, CreatedAt: created, UpdatedAt: updated},
+		{ID: 3, UID: "task-3@todo-lists", Name: "Ship", Deadline: &deadline, Priority: "medium", Status: entity.StatusCancelled, Version: 2, CreatedAt: created, UpdatedAt: updated, ParentID: 9},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteTasksICal(&out, tasks, entity.ICalExport{Name: "Backend", Refresh: time.Hour}))
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//todo-lists//Tasks//EN",
+		"CALSCALE:GREGORIAN",
+		"NAME:Backend",
+		"X-WR-CALNAME:Backend",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+		"BEGIN:VTODO",
+		"UID:task-1@todo-lists",
+		"DTSTAMP:20261101T093000Z",
+		"CREATED:20261101T080000Z",
+		"LAST-MODIFIED:20261101T093000Z",
+		"SEQUENCE:2",
+		`SUMMARY:Write report\; draft\, v2`,
+		"PRIORITY:1",
+		"DUE:20261102T170000Z",
+		"STATUS:IN-PROCESS",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-2@todo-lists",
+		"DTSTAMP:20261101T093000Z",
+		"CREATED:20261101T080000Z",
+		"LAST-MODIFIED:20261101T093000Z",
+		"SUMMARY:Proofread",
+		"PRIORITY:9",
+		"RELATED-TO;RELTYPE=PARENT:task-1@todo-lists",
+		"STATUS:COMPLETED",
+		"COMPLETED:20261101T093000Z",
+		"PERCENT-COMPLETE:100",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:task-3@todo-lists",
+		"DTSTAMP:20261101T093000Z",
+		"CREATED:20261101T080000Z",
+		"LAST-MODIFIED:20261101T093000Z",
+		"SEQUENCE:1",
+		"SUMMARY:Ship",
+		"PRIORITY:5",
+		"DUE:20261102T170000Z",
+		"STATUS:CANCELLED",
+		"END:VTODO",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), out.String())
+
+	// The parent of Ship is not exported, so it has no RELATED-TO
+	assert.Equal(t, 1, strings.Count(out.String(), "RELATED-TO"))
+
+	// Events leave out the tasks without a deadline
+	out.Reset()
+	require.NoError(t, WriteTasksICal(&out, tasks, entity.ICalExport{Events: true}))
+	calendar := out.String()
+	assert.NotContains(t, calendar, "X-WR-CALNAME")
+	assert.NotContains(t, calendar, "REFRESH-INTERVAL")
+	assert.Equal(t, 2, strings.Count(calendar, "BEGIN:VEVENT"))
+	assert.NotContains(t, calendar, "task-2@todo-lists")
+	assert.Contains(t, calendar, "BEGIN:VEVENT\r\nUID:task-1@todo-lists\r\n")
+	assert.Contains(t, calendar, "DTSTART:20261102T170000Z\r\nTRANSP:TRANSPARENT\r\nSTATUS:CONFIRMED\r\nEND:VEVENT")
+	assert.Contains(t, calendar, "TRANSP:TRANSPARENT\r\nSTATUS:CANCELLED\r\nEND:VEVENT")
+}
+
+// icalFile wraps VTODO lines in a calendar
+func icalFile(lines ...string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Example//Tasks//EN\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n"
+}
+
+func TestTaskService_ImportTasksICal(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	list := entity.List{Name: "Backend"}
+	require.NoError(t, repo.CreateList(&list))
+	options := entity.ICalImport{ListID: list.ID, Location: berlin}
+
+	file := icalFile(
+		"BEGIN:VTODO",
+		"UID:report@example.com",
+		`SUMMARY:Write report\, draft`,
+		"DUE;TZID=America/New_York:20261102T090000",
+		"PRIORITY:2",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:review@example.com",
+		"SUMMARY:Review",
+		"DUE:20261103T170000",
+		"PRIORITY:7",
+		"STATUS:IN-PROCESS",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:ship@example.com",
+		"SUMMARY:Ship",
+		"DUE;VALUE=DATE:20261104",
+		"END:VTODO",
+		"BEGIN:VEVENT",
+		"UID:meeting@example.com",
+		"SUMMARY:Not a task",
+		"END:VEVENT",
+	)
+
+	// A dry run stores nothing
+	result, rowErrors, err := taskService.ImportTasksICal(strings.NewReader(file), entity.ICalImport{ListID: list.ID, Location: berlin, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 3, result.Created)
+	assert.Zero(t, result.Tasks[0].ID)
+	page, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+
+	result, rowErrors, err = taskService.ImportTasksICal(strings.NewReader(file), options)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 3, result.Created)
+	assert.Zero(t, result.Updated)
+	require.Len(t, result.Tasks, 3)
+	report, review, ship := result.Tasks[0], result.Tasks[1], result.Tasks[2]
+	assert.Equal(t, "Write report, draft", report.Name)
+	assert.Equal(t, "report@example.com", report.UID)
+	assert.Equal(t, list.ID, report.ListID)
+	assert.True(t, time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC).Equal(*report.Deadline))
+	assert.Equal(t, entity.PriorityHigh, report.Priority)
+	assert.Equal(t, entity.StatusTodo, report.Status)
+	// Floating times and dates are read in the location of the import
+	assert.True(t, time.Date(2026, 11, 3, 16, 0, 0, 0, time.UTC).Equal(*review.Deadline))
+	assert.Equal(t, entity.PriorityLess, review.Priority)
+	assert.Equal(t, entity.StatusInProgress, review.Status)
+	assert.True(t, time.Date(2026, 11, 3, 23, 0, 0, 0, time.UTC).Equal(*ship.Deadline))
+	assert.Equal(t, entity.PriorityMedium, ship.Priority)
+
+	// Importing again updates the tasks with the same UID instead of adding more
+	updatedFile := icalFile(
+		"BEGIN:VTODO",
+		"UID:report@example.com",
+		"SUMMARY:Write final report",
+		"DUE:20261105T120000Z",
+		"PRIORITY:5",
+		"STATUS:COMPLETED",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:review@example.com",
+		"SUMMARY:Review",
+		"DUE:20261103T170000",
+		"PRIORITY:7",
+		"STATUS:IN-PROCESS",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:new@example.com",
+		"SUMMARY:Celebrate",
+		"END:VTODO",
+	)
+	result, rowErrors, err = taskService.ImportTasksICal(strings.NewReader(updatedFile), entity.ICalImport{Location: berlin})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 2, result.Updated)
+	assert.Equal(t, report.ID, result.Tasks[0].ID)
+	assert.Equal(t, "Write final report", result.Tasks[0].Name)
+	assert.Equal(t, entity.PriorityMedium, result.Tasks[0].Priority)
+	assert.Equal(t, entity.StatusDone, result.Tasks[0].Status)
+	assert.Equal(t, list.ID, result.Tasks[0].ListID)
+	// An unchanged task stays at its version
+	assert.Equal(t, review, result.Tasks[1])
+	assert.Nil(t, result.Tasks[2].Deadline)
+	page, err = repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 4)
+}
+
+func TestTaskService_ImportTasksICalExported(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	tasks := []*entity.Task{
+		{Name: "Write report", Deadline: due(time.Date(2026, 11, 2, 9, 0, 0, 0, time.UTC)), Priority: "high"},
+		{Name: "Review report", Priority: "less", Status: entity.StatusBlocked},
+	}
+	for _, task := range tasks {
+		require.NoError(t, taskService.CreateTask(task))
+	}
+
+	// An exported calendar imports back onto the tasks it came from without changing them
+	exported, err := taskService.ExportTasks(entity.TaskFilter{}, time.UTC)
+	require.NoError(t, err)
+	var file strings.Builder
+	require.NoError(t, WriteTasksICal(&file, exported, entity.ICalExport{}))
+	result, rowErrors, err := taskService.ImportTasksICal(strings.NewReader(file.String()), entity.ICalImport{})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 2, result.Updated)
+	assert.Equal(t, exported, result.Tasks)
+
+	// A task in the trash is not updated, the VTODO creates a new task
+	require.NoError(t, taskService.DeleteTask(int(tasks[1].ID), 0))
+	result, _, err = taskService.ImportTasksICal(strings.NewReader(file.String()), entity.ICalImport{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, tasks[1].UID, result.Tasks[1].UID)
+}
+
+func TestTaskService_ImportTasksICalStatus(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	create := func(name, status string) entity.Task {
+		task := entity.Task{Name: name, Priority: "medium", Status: status}
+		require.NoError(t, taskService.CreateTask(&task))
+		return task
+	}
+	tasks := []entity.Task{
+		create("Cancelled", entity.StatusCancelled),
+		create("Done", entity.StatusDone),
+		create("Blocked", entity.StatusBlocked),
+	}
+
+	// Status changes the lifecycle has no direct transition for go through the state in between
+	var lines []string
+	for i, status := range []string{"COMPLETED", "CANCELLED", "COMPLETED"} {
+		lines = append(lines, "BEGIN:VTODO", "UID:"+tasks[i].UID, "SUMMARY:"+tasks[i].Name, "STATUS:"+status, "END:VTODO")
+	}
+	result, rowErrors, err := taskService.ImportTasksICal(strings.NewReader(icalFile(lines...)), entity.ICalImport{})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 3, result.Updated)
+	for i, status := range []string{entity.StatusDone, entity.StatusCancelled, entity.StatusDone} {
+		stored, err := repo.GetTaskById(int(tasks[i].ID))
+		require.NoError(t, err)
+		assert.Equal(t, status, stored.Status, stored.Name)
+	}
+}
+
+func TestTaskService_ImportTasksICalOtherServer(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	report := entity.Task{Name: "Write report", Priority: "high"}
+	require.NoError(t, taskService.CreateTask(&report))
+	review := entity.Task{Name: "Review report", Priority: "less"}
+	require.NoError(t, taskService.CreateTask(&review))
+
+	// Every task has a random UID of its own
+	assert.Regexp(t, `^[0-9a-f]{32}@todo-lists$`, report.UID)
+	assert.NotEqual(t, report.UID, review.UID)
+
+	// A calendar of another server whose UIDs name the same IDs creates tasks instead of changing these
+	file := icalFile(
+		"BEGIN:VTODO",
+		fmt.Sprintf("UID:task-%d@todo-lists", report.ID),
+		"SUMMARY:Someone else's task",
+		"END:VTODO",
+	)
+	result, rowErrors, err := taskService.ImportTasksICal(strings.NewReader(file), entity.ICalImport{})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 1, result.Created)
+	assert.Zero(t, result.Updated)
+	stored, err := repo.GetTaskById(int(report.ID))
+	require.NoError(t, err)
+	assert.Equal(t, report, stored)
+}
+
+func TestTaskService_ImportTasksICalRejected(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo}
+	blocker := entity.Task{Name: "Blocker", Priority: "less"}
+	require.NoError(t, taskService.CreateTask(&blocker))
+	waiting := entity.Task{Name: "Waiting", Priority: "less", UID: "waiting@example.com"}
+	require.NoError(t, taskService.CreateTask(&waiting))
+	require.NoError(t, taskService.AddBlocker(int(waiting.ID), int(blocker.ID)))
+
+	file := icalFile(
+		"BEGIN:VTODO",
+		"UID:valid@example.com",
+		"SUMMARY:Valid",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:nameless@example.com",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:Bad due",
+		"DUE;TZID=Mars/Olympus_Mons:20261102T090000",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:Bad priority",
+		"PRIORITY:high",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:Bad status",
+		"STATUS:DRAFT",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"UID:waiting@example.com",
+		"SUMMARY:Waiting",
+		"STATUS:COMPLETED",
+		"END:VTODO",
+	)
+	result, rowErrors, err := taskService.ImportTasksICal(strings.NewReader(file), entity.ICalImport{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Tasks)
+	require.Len(t, rowErrors, 5)
+	for i, expected := range []struct {
+		line  int
+		field string
+		err   error
+	}{
+		{8, "name", ErrInvalidTask},
+		{11, "deadline", ErrInvalidTask},
+		{15, "priority", ErrInvalidPriority},
+		{19, "status", ErrInvalidStatus},
+		{23, "status", ErrBlocked},
+	} {
+		assert.Equal(t, expected.line, rowErrors[i].Line)
+		assert.Equal(t, expected.field, rowErrors[i].Field)
+		assert.ErrorIs(t, rowErrors[i].Err, expected.err)
+	}
+	_, err = repo.GetTaskByUID("valid@example.com")
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	for _, file := range []string{
+		"",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\nSUMMARY:Not in a calendar\r\nEND:VTODO\r\n",
+		icalFile("BEGIN:VEVENT", "SUMMARY:Meeting", "END:VEVENT"),
+	} {
+		_, _, err := taskService.ImportTasksICal(strings.NewReader(file), entity.ICalImport{})
+		assert.ErrorIs(t, err, ErrInvalidImport, file)
+	}
+	_, _, err = taskService.ImportTasksICal(strings.NewReader(file), entity.ICalImport{ListID: 9})
+	assert.ErrorIs(t, err, ErrListNotFound)
+}
+
+func TestTaskService_ExportLabeledTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo, Labels: repo}
+	list := entity.List{Name: "Home Office"}
+	require.NoError(t, repo.CreateList(&list))
+	work := entity.Label{Name: "work", Color: entity.DefaultLabelColor}
+	require.NoError(t, repo.CreateLabel(&work))
+	deep := entity.Label{Name: "Deep Work", Color: entity.DefaultLabelColor}
+	require.NoError(t, repo.CreateLabel(&deep))
+
+	report := entity.Task{Name: "Write report", ListID: list.ID}
+	require.NoError(t, taskService.CreateTask(&report))
+	review := entity.Task{Name: "Review"}
+	require.NoError(t, taskService.CreateTask(&review))
+	require.NoError(t, repo.AttachLabel(report.ID, work.ID))
+	require.NoError(t, repo.AttachLabel(report.ID, deep.ID))
+
+	tasks, err := taskService.ExportLabeledTasks(entity.TaskFilter{}, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, report.ID, tasks[0].ID)
+	assert.Equal(t, "Home Office", tasks[0].List)
+	assert.Equal(t, []string{"Deep Work", "work"}, tasks[0].Labels)
+	assert.Equal(t, review.ID, tasks[1].ID)
+	assert.Empty(t, tasks[1].List)
+	assert.Empty(t, tasks[1].Labels)
+}
+
+func TestWriteTasksTodoTxt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	created := time.Date(2026, 10, 1, 23, 30, 0, 0, time.UTC)
+	completed := time.Date(2026, 11, 3, 10, 0, 0, 0, time.UTC)
+	tasks := []entity.LabeledTask{
+		{Task: entity.Task{Name: "Write  report", Deadline: due(time.Date(2026, 11, 2, 23, 0, 0, 0, time.UTC)), Priority: entity.PriorityHigh, Status: entity.StatusTodo, CreatedAt: created}, List: "Home Office", Labels: []string{"Deep Work", "work"}},
+		{Task: entity.Task{Name: "Review", Priority: entity.PriorityLess, Status: entity.StatusDone, CompletedAt: &completed, CreatedAt: created}},
+		{Task: entity.Task{Name: "Ship", Priority: entity.PriorityMedium, Status: entity.StatusBlocked, CreatedAt: created}},
+		{Task: entity.Task{Name: "Old idea", Priority: entity.PriorityMedium, Status: entity.StatusCancelled, CreatedAt: created}},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteTasksTodoTxt(&out, tasks, berlin))
+	assert.Equal(t, "(A) 2026-10-02 Write report +Home_Office @Deep_Work @work due:2026-11-03\n"+
+		"x 2026-11-03 2026-10-02 Review pri:C\n"+
+		"(B) 2026-10-02 Ship status:blocked\n"+
+		"x Old idea status:cancelled pri:B\n", out.String())
+}
+
+func TestTaskService_ImportTasksTodoTxt(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo, Labels: repo}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	inbox := entity.List{Name: "Inbox"}
+	require.NoError(t, repo.CreateList(&inbox))
+	office := entity.List{Name: "Home Office"}
+	require.NoError(t, repo.CreateList(&office))
+	work := entity.Label{Name: "Work", Color: "#ff0000"}
+	require.NoError(t, repo.CreateLabel(&work))
+	options := entity.TodoTxtImport{ListID: inbox.ID, Location: berlin}
+
+	file := "\ufeff(A) 2026-10-01 Write report +home_office @work @Deep_Work due:2026-11-02\n" +
+		"\n" +
+		"x 2026-11-03 Review pri:C\n" +
+		"(D) Ship +Release status:in_progress\n" +
+		"Call Mom @phone\n"
+
+	// A dry run stores nothing
+	tasks, rowErrors, err := taskService.ImportTasksTodoTxt(strings.NewReader(file), entity.TodoTxtImport{ListID: inbox.ID, Location: berlin, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, tasks, 4)
+	assert.Zero(t, tasks[0].ID)
+	page, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+	lists, err := repo.GetAllLists()
+	require.NoError(t, err)
+	assert.Len(t, lists, 2)
+
+	tasks, rowErrors, err = taskService.ImportTasksTodoTxt(strings.NewReader(file), options)
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, tasks, 4)
+	report, review, ship, call := tasks[0], tasks[1], tasks[2], tasks[3]
+
+	assert.Equal(t, "Write report", report.Name)
+	assert.Equal(t, entity.PriorityHigh, report.Priority)
+	assert.Equal(t, entity.StatusTodo, report.Status)
+	assert.Equal(t, office.ID, report.ListID)
+	assert.True(t, time.Date(2026, 11, 1, 23, 0, 0, 0, time.UTC).Equal(*report.Deadline))
+	labels, err := repo.GetTaskLabels(report.ID)
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	assert.Equal(t, "Deep Work", labels[0].Name)
+	assert.Equal(t, entity.DefaultLabelColor, labels[0].Color)
+	assert.Equal(t, work, labels[1])
+
+	assert.Equal(t, entity.PriorityLess, review.Priority)
+	assert.Equal(t, entity.StatusDone, review.Status)
+	assert.NotNil(t, review.CompletedAt)
+	assert.Equal(t, inbox.ID, review.ListID)
+	assert.Nil(t, review.Deadline)
+
+	// Priorities below (C) are less, and missing lists are created
+	assert.Equal(t, entity.PriorityLess, ship.Priority)
+	assert.Equal(t, entity.StatusInProgress, ship.Status)
+	release, err := repo.GetListById(int(ship.ListID))
+	require.NoError(t, err)
+	assert.Equal(t, "Release", release.Name)
+
+	assert.Equal(t, entity.PriorityMedium, call.Priority)
+	labels, err = repo.GetTaskLabels(call.ID)
+	require.NoError(t, err)
+	require.Len(t, labels, 1)
+	assert.Equal(t, "phone", labels[0].Name)
+
+	// What is exported is imported as the same tasks
+	exported, err := taskService.ExportLabeledTasks(entity.TaskFilter{}, berlin)
+	require.NoError(t, err)
+	var out strings.Builder
+	require.NoError(t, WriteTasksTodoTxt(&out, exported, berlin))
+	again, rowErrors, err := taskService.ImportTasksTodoTxt(strings.NewReader(out.String()), entity.TodoTxtImport{Location: berlin, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	for i, task := range again {
+		assert.Equal(t, tasks[i].Name, task.Name)
+		assert.Equal(t, tasks[i].Priority, task.Priority)
+		assert.Equal(t, tasks[i].Status, task.Status)
+		assert.Equal(t, tasks[i].ListID, task.ListID)
+		assert.Zero(t, entity.CompareDeadlines(tasks[i].Deadline, task.Deadline))
+	}
+}
+
+func TestTaskService_ImportTasksTodoTxtRejected(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo, Labels: repo}
+
+	file := "Write report +backend\n" +
+		"(A) +backend @work\n" +
+		"Review due:tomorrow\n" +
+		"Ship status:paused\n" +
+		"Deploy +backend +ops\n" +
+		"Plan @a,b\n"
+	tasks, rowErrors, err := taskService.ImportTasksTodoTxt(strings.NewReader(file), entity.TodoTxtImport{})
+	require.NoError(t, err)
+	assert.Nil(t, tasks)
+	require.Len(t, rowErrors, 5)
+	for i, expected := range []struct {
+		line  int
+		field string
+		err   error
+	}{
+		{2, "name", ErrInvalidTask},
+		{3, "deadline", ErrInvalidTask},
+		{4, "status", ErrInvalidStatus},
+		{5, "project", ErrInvalidTask},
+		{6, "context", ErrInvalidTask},
+	} {
+		assert.Equal(t, expected.line, rowErrors[i].Line)
+		assert.Equal(t, expected.field, rowErrors[i].Field)
+		assert.ErrorIs(t, rowErrors[i].Err, expected.err)
+	}
+
+	// Nothing was stored, not even the list of the valid lines
+	page, err := repo.GetAllTasks("", entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Tasks)
+	lists, err := repo.GetAllLists()
+	require.NoError(t, err)
+	assert.Empty(t, lists)
+
+	_, _, err = taskService.ImportTasksTodoTxt(strings.NewReader("\n  \n"), entity.TodoTxtImport{})
+	assert.ErrorIs(t, err, ErrInvalidImport)
+	_, _, err = taskService.ImportTasksTodoTxt(strings.NewReader("Write report"), entity.TodoTxtImport{ListID: 9})
+	assert.ErrorIs(t, err, ErrListNotFound)
+}
+
+func TestWriteTasksMarkdown(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	tasks := []entity.LabeledTask{
+		{Task: entity.Task{ID: 1, Name: "Write  report", Deadline: due(time.Date(2026, 11, 1, 23, 0, 0, 0, time.UTC)), Status: entity.StatusTodo}, Labels: []string{"work", "writing"}},
+		{Task: entity.Task{ID: 2, Name: "Review", Deadline: due(time.Date(2026, 11, 3, 16, 0, 0, 0, time.UTC)), Status: entity.StatusDone}, Labels: []string{"work"}},
+		{Task: entity.Task{ID: 3, Name: "Old idea", Status: entity.StatusCancelled}},
+		{Task: entity.Task{ID: 4, Name: "Buy milk", Status: entity.StatusBlocked}, Labels: []string{"home"}},
+	}
+
+	var out strings.Builder
+	require.NoError(t, WriteTasksMarkdown(&out, "Backend", tasks, berlin))
+	assert.Equal(t, "# Backend\n"+
+		"\n"+
+		"## home\n"+
+		"\n"+
+		"- [ ] Buy milk <!-- task:4 -->\n"+
+		"\n"+
+		"## work\n"+
+		"\n"+
+		"- [ ] Write report (due 2026-11-02) <!-- task:1 -->\n"+
+		"- [x] Review (due 2026-11-03 17:00) <!-- task:2 -->\n"+
+		"\n"+
+		"## Untagged\n"+
+		"\n"+
+		"- [x] ~~Old idea~~ <!-- task:3 -->\n", out.String())
+
+	out.Reset()
+	require.NoError(t, WriteTasksMarkdown(&out, "Empty", nil, berlin))
+	assert.Equal(t, "# Empty\n", out.String())
+}
+
+func TestTaskService_ImportTasksMarkdown(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo, Labels: repo}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	list := entity.List{Name: "Backend"}
+	require.NoError(t, repo.CreateList(&list))
+	work := entity.Label{Name: "work", Color: "#ff0000"}
+	require.NoError(t, repo.CreateLabel(&work))
+
+	report := entity.Task{Name: "Write report", ListID: list.ID, Deadline: due(time.Date(2026, 11, 1, 23, 0, 0, 0, time.UTC))}
+	require.NoError(t, taskService.CreateTask(&report))
+	review := entity.Task{Name: "Review", ListID: list.ID}
+	require.NoError(t, taskService.CreateTask(&review))
+	idea := entity.Task{Name: "Old idea", ListID: list.ID, Status: entity.StatusCancelled}
+	require.NoError(t, taskService.CreateTask(&idea))
+	ship := entity.Task{Name: "Ship", ListID: list.ID, Status: entity.StatusInProgress}
+	require.NoError(t, taskService.CreateTask(&ship))
+	require.NoError(t, repo.AttachLabel(report.ID, work.ID))
+
+	// The export imports back without changes or duplicates
+	_, exported, err := taskService.ExportListTasks(int(list.ID), entity.TaskFilter{}, berlin)
+	require.NoError(t, err)
+	var out strings.Builder
+	require.NoError(t, WriteTasksMarkdown(&out, list.Name, exported, berlin))
+	result, rowErrors, err := taskService.ImportTasksMarkdown(strings.NewReader(out.String()), entity.MarkdownImport{ListID: list.ID, Location: berlin})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Zero(t, result.Created)
+	assert.Equal(t, 4, result.Updated)
+	for _, task := range []entity.Task{report, review, idea, ship} {
+		stored, err := repo.GetTaskById(int(task.ID))
+		require.NoError(t, err)
+		assert.Equal(t, task.Version, stored.Version, task.Name)
+	}
+
+	// Edits made to the document are applied to the tasks
+	document := fmt.Sprintf("# Backend\n"+
+		"\n"+
+		"## work\n"+
+		"\n"+
+		"- [x] Write final report (due 2026-11-05 12:00) <!-- task:%d -->\n"+
+		"- [ ] Plan the launch (due 2026-11-06)\n"+
+		"\n"+
+		"## Deep Work\n"+
+		"\n"+
+		"- [ ] Refactor\n"+
+		"\n"+
+		"## Untagged\n"+
+		"\n"+
+		"- [ ] Review <!-- task:%d -->\n"+
+		"- [ ] Old idea <!-- task:%d -->\n"+
+		"- [ ] Ship <!-- task:%d -->\n", report.ID, review.ID, idea.ID, ship.ID)
+
+	// A dry run stores nothing
+	result, rowErrors, err = taskService.ImportTasksMarkdown(strings.NewReader(document), entity.MarkdownImport{ListID: list.ID, Location: berlin, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 2, result.Created)
+	stored, err := repo.GetTaskById(int(report.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Write report", stored.Name)
+
+	result, rowErrors, err = taskService.ImportTasksMarkdown(strings.NewReader(document), entity.MarkdownImport{ListID: list.ID, Location: berlin})
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 4, result.Updated)
+	require.Len(t, result.Tasks, 6)
+
+	stored, err = repo.GetTaskById(int(report.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Write final report", stored.Name)
+	assert.True(t, time.Date(2026, 11, 5, 11, 0, 0, 0, time.UTC).Equal(*stored.Deadline))
+	assert.Equal(t, entity.StatusDone, stored.Status)
+
+	plan := result.Tasks[1]
+	assert.Equal(t, "Plan the launch", plan.Name)
+	assert.Equal(t, list.ID, plan.ListID)
+	assert.True(t, time.Date(2026, 11, 5, 23, 0, 0, 0, time.UTC).Equal(*plan.Deadline))
+	labels, err := repo.GetTaskLabels(plan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.Label{work}, labels)
+	labels, err = repo.GetTaskLabels(result.Tasks[2].ID)
+	require.NoError(t, err)
+	require.Len(t, labels, 1)
+	assert.Equal(t, "Deep Work", labels[0].Name)
+
+	// Unchecking reopens a closed task and leaves an open one as it is
+	stored, err = repo.GetTaskById(int(idea.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusTodo, stored.Status)
+	stored, err = repo.GetTaskById(int(ship.ID))
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusInProgress, stored.Status)
+	page, err := repo.GetTasksByListId(int(list.ID), entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 6)
+}
+
+func TestTaskService_ImportTasksMarkdownRejected(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	taskService := TaskService{Repo: repo, Lists: repo, Labels: repo}
+	list := entity.List{Name: "Backend"}
+	require.NoError(t, repo.CreateList(&list))
+	report := entity.Task{Name: "Write report", ListID: list.ID, Status: entity.StatusCancelled}
+	require.NoError(t, taskService.CreateTask(&report))
+	other := entity.List{Name: "Frontend"}
+	require.NoError(t, repo.CreateList(&other))
+	styles := entity.Task{Name: "Styles", ListID: other.ID}
+	require.NoError(t, taskService.CreateTask(&styles))
+
+	document := fmt.Sprintf("- [ ] Plan\n"+
+		"- [ ] <!-- task:%d -->\n"+
+		"- [ ] Review (due tomorrow)\n"+
+		"- [x] Write report <!-- task:%d -->\n"+
+		"- [ ] Again <!-- task:%d -->\n"+
+		"- [ ] Gone <!-- task:99 -->\n"+
+		"- [x] Styles of another list <!-- task:%d -->\n"+
+		"## a, b\n"+
+		"- [ ] Ship\n", report.ID, report.ID, report.ID, styles.ID)
+	result, rowErrors, err := taskService.ImportTasksMarkdown(strings.NewReader(document), entity.MarkdownImport{ListID: list.ID})
+	require.NoError(t, err)
+	assert.Empty(t, result.Tasks)
+	require.Len(t, rowErrors, 7)
+	for i, expected := range []struct {
+		line  int
+		field string
+		err   error
+	}{
+		{2, "name", ErrInvalidTask},
+		{3, "deadline", ErrInvalidTask},
+		{4, "status", ErrIllegalTransition},
+		{5, "id", ErrInvalidTask},
+		{6, "id", ErrInvalidTask},
+		{7, "id", ErrInvalidTask},
+		{9, "section", ErrInvalidTask},
+	} {
+		assert.Equal(t, expected.line, rowErrors[i].Line)
+		assert.Equal(t, expected.field, rowErrors[i].Field)
+		assert.ErrorIs(t, rowErrors[i].Err, expected.err)
+	}
+
+	// Nothing was stored, not even the valid items, and the task of the other list is left alone
+	page, err := repo.GetTasksByListId(int(list.ID), entity.PageRequest{Limit: 10, Sort: "id"})
+	require.NoError(t, err)
+	assert.Len(t, page.Tasks, 1)
+	stored, err := repo.GetTaskById(int(styles.ID))
+	require.NoError(t, err)
+	assert.Equal(t, styles, stored)
+
+	_, _, err = taskService.ImportTasksMarkdown(strings.NewReader("# Backend\n\n- not a task\n"), entity.MarkdownImport{ListID: list.ID})
+	assert.ErrorIs(t, err, ErrInvalidImport)
+	_, _, err = taskService.ImportTasksMarkdown(strings.NewReader("- [ ] Plan\n"), entity.MarkdownImport{ListID: 9})
+	assert.ErrorIs(t, err, ErrListNotFound)
+	_, _, err = taskService.ExportListTasks(9, entity.TaskFilter{}, time.UTC)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
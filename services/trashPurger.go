@@ -0,0 +1,43 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// StartTrashPurge empties the trash of tasks older than retention once right away and then every interval,
+// until the returned stop function is called.
+func StartTrashPurge(service IService, retention, interval time.Duration) (stop func()) {
+	return runEvery(interval, func() {
+		purged, err := service.PurgeTrash(retention)
+		if err != nil {
+			log.Println("Error purging trash:", err)
+			return
+		}
+		if purged > 0 {
+			log.Printf("Purged %d tasks from the trash", purged)
+		}
+	})
+}
+
+// runEvery calls job once right away and then every interval in the background, until the returned stop
+// function is called
+func runEvery(interval time.Duration, job func()) (stop func()) {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		job()
+		for {
+			select {
+			case <-ticker.C:
+				job()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
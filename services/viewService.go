@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/query"
+	"todo-lists/repositories"
+
+	"gorm.io/gorm"
+)
+
+// builtInViews are the views every client has. Their relative dates are days in the caller's time zone.
+var builtInViews = []entity.View{
+	{ID: "today", Name: "Today", Query: "deadline:today", BuiltIn: true},
+	{ID: "upcoming", Name: "Upcoming", Query: "deadline>=tomorrow AND deadline<today+8d", BuiltIn: true},
+	{ID: "overdue", Name: "Overdue", Query: "deadline<today AND NOT status:done AND NOT status:cancelled", BuiltIn: true},
+	{ID: "no_deadline", Name: "No deadline", Query: "deadline:none", BuiltIn: true},
+}
+
+type ViewService struct {
+	Repo  repositories.IViewRepo
+	Tasks repositories.IRepo
+}
+
+// CreateView method saves a new view after checking that its query parses
+func (s *ViewService) CreateView(view *entity.View) error {
+	if err := s.validateView(view); err != nil {
+		return err
+	}
+	return s.Repo.CreateView(view)
+}
+
+// GetAllViews method retrieves the built-in views followed by the saved ones
+func (s *ViewService) GetAllViews() ([]entity.View, error) {
+	saved, err := s.Repo.GetAllViews()
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]entity.View{}, builtInViews...), saved...), nil
+}
+
+// GetView method retrieves a built-in view by its key or a saved view by its ID
+func (s *ViewService) GetView(id string) (entity.View, error) {
+	if view, ok := builtInView(id); ok {
+		return view, nil
+	}
+	savedId, err := strconv.Atoi(id)
+	if err != nil || savedId < 1 {
+		return entity.View{}, gorm.ErrRecordNotFound
+	}
+	return s.Repo.GetViewById(savedId)
+}
+
+// UpdateView method renames a saved view or replaces its query
+func (s *ViewService) UpdateView(view *entity.View) error {
+	if _, ok := builtInView(view.ID); ok {
+		return ErrBuiltInView
+	}
+	if _, err := s.GetView(view.ID); err != nil {
+		return err
+	}
+	if err := s.validateView(view); err != nil {
+		return err
+	}
+	return s.Repo.UpdateView(view)
+}
+
+// DeleteView method deletes a saved view by its ID
+func (s *ViewService) DeleteView(id string) error {
+	if _, ok := builtInView(id); ok {
+		return ErrBuiltInView
+	}
+	savedId, err := strconv.Atoi(id)
+	if err != nil || savedId < 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return s.Repo.DeleteView(savedId)
+}
+
+// GetViewTasks method evaluates a view and returns a page of the tasks it matches with counts over all of
+// them. Dates in the query are days in the given location.
+func (s *ViewService) GetViewTasks(id string, loc *time.Location, page entity.PageRequest) (entity.ViewTasks, error) {
+	view, err := s.GetView(id)
+	if err != nil {
+		return entity.ViewTasks{}, err
+	}
+	now := time.Now().In(loc)
+	filter, err := query.ParseAt(view.Query, now)
+	if err != nil {
+		return entity.ViewTasks{}, err
+	}
+
+	tasks, err := s.Tasks.QueryTasks(filter, page)
+	if err != nil {
+		return entity.ViewTasks{}, err
+	}
+	counts, err := s.countTasks(filter, now)
+	if err != nil {
+		return entity.ViewTasks{}, err
+	}
+	return entity.ViewTasks{View: view, TaskPage: tasks, Counts: counts}, nil
+}
+
+// countTasks counts all tasks a filter matches, the open ones and the open ones due before the day of now
+func (s *ViewService) countTasks(filter query.Expr, now time.Time) (entity.ViewCounts, error) {
+	var counts entity.ViewCounts
+	byStatus, err := s.Tasks.CountTasksByStatus(filter)
+	if err != nil {
+		return entity.ViewCounts{}, err
+	}
+	for status, n := range byStatus {
+		counts.Total += n
+		counts.Open += openCount(status, n)
+	}
+
+	overdue, err := query.ParseAt("deadline<today", now)
+	if err != nil {
+		return entity.ViewCounts{}, err
+	}
+	byStatus, err = s.Tasks.CountTasksByStatus(query.And{Left: filter, Right: overdue})
+	if err != nil {
+		return entity.ViewCounts{}, err
+	}
+	for status, n := range byStatus {
+		counts.Overdue += openCount(status, n)
+	}
+	return counts, nil
+}
+
+// validateView trims and checks the name and query of a view and makes sure no other view has its name
+func (s *ViewService) validateView(view *entity.View) error {
+	view.Name = strings.TrimSpace(view.Name)
+	view.Query = strings.TrimSpace(view.Query)
+	view.BuiltIn = false
+	if view.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidView)
+	}
+	if len(view.Name) > entity.MaxViewNameLength {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidView, entity.MaxViewNameLength)
+	}
+	if _, err := query.Parse(view.Query); err != nil {
+		return err
+	}
+
+	for _, builtIn := range builtInViews {
+		if strings.EqualFold(builtIn.Name, view.Name) {
+			return ErrViewExists
+		}
+	}
+	existing, err := s.Repo.GetViewByName(view.Name)
+	if err == nil && existing.ID != view.ID {
+		return ErrViewExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// builtInView finds a built-in view by its key
+func builtInView(id string) (entity.View, bool) {
+	for _, view := range builtInViews {
+		if view.ID == id {
+			return view, true
+		}
+	}
+	return entity.View{}, false
+}
+
+// openCount returns n for a status a task is still open in, zero otherwise
+func openCount(status string, n int64) int64 {
+	if isOpen(entity.Task{Status: status}) {
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"todo-lists/entity"
+	"todo-lists/mocks"
+	"todo-lists/query"
+	"todo-lists/repositories"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestViewService_CreateView(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	viewService := ViewService{Repo: repo, Tasks: repo}
+
+	// Names and queries are trimmed
+	view := &entity.View{Name: " Overdue high priority ", Query: " deadline<today priority:high ", BuiltIn: true}
+	require.NoError(t, viewService.CreateView(view))
+	assert.Equal(t, "Overdue high priority", view.Name)
+	assert.Equal(t, "deadline<today priority:high", view.Query)
+	assert.False(t, view.BuiltIn)
+
+	assert.Equal(t, ErrViewExists, viewService.CreateView(&entity.View{Name: "Overdue high priority", Query: "status:done"}))
+	assert.Equal(t, ErrViewExists, viewService.CreateView(&entity.View{Name: "today", Query: "status:done"}))
+	for _, invalid := range []entity.View{
+		{Name: " ", Query: "status:done"},
+		{Name: string(make([]byte, entity.MaxViewNameLength+1)), Query: "status:done"},
+	} {
+		assert.ErrorIs(t, viewService.CreateView(&invalid), ErrInvalidView, invalid.Name)
+	}
+
+	// Queries are checked before they are saved
+	err := viewService.CreateView(&entity.View{Name: "Broken", Query: "deadline<someday"})
+	var queryErr *query.Error
+	if assert.ErrorAs(t, err, &queryErr) {
+		assert.Equal(t, 10, queryErr.Pos)
+	}
+	assert.ErrorIs(t, viewService.CreateView(&entity.View{Name: "Empty"}), ErrInvalidQuery)
+
+	// Built-in views come first
+	views, err := viewService.GetAllViews()
+	require.NoError(t, err)
+	var ids []string
+	for _, view := range views {
+		ids = append(ids, view.ID)
+	}
+	assert.Equal(t, []string{"today", "upcoming", "overdue", "no_deadline", view.ID}, ids)
+}
+
+func TestViewService_ChangeViews(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	viewService := ViewService{Repo: repo, Tasks: repo}
+	week := &entity.View{Name: "Due this week", Query: "deadline>=today deadline<today+7d"}
+	require.NoError(t, viewService.CreateView(week))
+	require.NoError(t, viewService.CreateView(&entity.View{Name: "Done", Query: "status:done"}))
+
+	// Keeping the own name is not a conflict
+	require.NoError(t, viewService.UpdateView(&entity.View{ID: week.ID, Name: "Due this week", Query: "deadline<today+7d"}))
+	found, err := viewService.GetView(week.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "deadline<today+7d", found.Query)
+
+	assert.Equal(t, ErrViewExists, viewService.UpdateView(&entity.View{ID: week.ID, Name: "Done", Query: "status:done"}))
+	assert.Equal(t, gorm.ErrRecordNotFound, viewService.UpdateView(&entity.View{ID: "99", Name: "Other", Query: "status:done"}))
+	assert.Equal(t, ErrBuiltInView, viewService.UpdateView(&entity.View{ID: "today", Name: "Mine", Query: "status:done"}))
+	assert.Equal(t, ErrBuiltInView, viewService.DeleteView("overdue"))
+
+	require.NoError(t, viewService.DeleteView(week.ID))
+	_, err = viewService.GetView(week.ID)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	for _, missing := range []string{week.ID, "0", "someday"} {
+		assert.Equal(t, gorm.ErrRecordNotFound, viewService.DeleteView(missing), missing)
+		_, err = viewService.GetView(missing)
+		assert.Equal(t, gorm.ErrRecordNotFound, err, missing)
+	}
+}
+
+func TestViewService_GetViewTasks(t *testing.T) {
+	repo := repositories.NewMemoryRepository()
+	viewService := ViewService{Repo: repo, Tasks: repo}
+	page := entity.PageRequest{Limit: 2, Sort: "deadline"}
+
+	// The tasks are placed relative to the current day in Tokyo, which is what the built-in views see
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	now := time.Now().In(tokyo)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, tokyo)
+	at := func(days int, hour int) *time.Time {
+		return due(today.AddDate(0, 0, days).Add(time.Duration(hour) * time.Hour))
+	}
+	tasks := []entity.Task{
+		{Name: "late", Deadline: at(-2, 9), Priority: entity.PriorityHigh},
+		{Name: "late but done", Deadline: at(-1, 9), Priority: entity.PriorityHigh, Status: entity.StatusDone},
+		{Name: "morning", Deadline: at(0, 0), Priority: entity.PriorityHigh},
+		{Name: "evening", Deadline: at(0, 23), Priority: entity.PriorityLess, Status: entity.StatusDone},
+		{Name: "tomorrow", Deadline: at(1, 9), Priority: entity.PriorityHigh},
+		{Name: "next week", Deadline: at(7, 9), Priority: entity.PriorityMedium},
+		{Name: "in eight days", Deadline: at(8, 0), Priority: entity.PriorityMedium},
+		{Name: "someday", Priority: entity.PriorityLess},
+	}
+	for i := range tasks {
+		if tasks[i].Status == "" {
+			tasks[i].Status = entity.StatusTodo
+		}
+		require.NoError(t, repo.CreateTask(&tasks[i]))
+	}
+	names := func(result entity.ViewTasks) []string {
+		var names []string
+		for _, task := range result.Tasks {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+
+	result, err := viewService.GetViewTasks("today", tokyo, page)
+	require.NoError(t, err)
+	assert.Equal(t, "Today", result.View.Name)
+	assert.Equal(t, []string{"morning", "evening"}, names(result))
+	assert.Equal(t, entity.ViewCounts{Total: 2, Open: 1}, result.Counts)
+
+	result, err = viewService.GetViewTasks("upcoming", tokyo, page)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tomorrow", "next week"}, names(result))
+	assert.Empty(t, result.Next)
+
+	result, err = viewService.GetViewTasks("overdue", tokyo, page)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"late"}, names(result))
+	assert.Equal(t, entity.ViewCounts{Total: 1, Open: 1, Overdue: 1}, result.Counts)
+
+	result, err = viewService.GetViewTasks("no_deadline", tokyo, page)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"someday"}, names(result))
+
+	// Saved views are evaluated live and count every match, not only the page
+	saved := &entity.View{Name: "High priority", Query: "priority:high"}
+	require.NoError(t, viewService.CreateView(saved))
+	result, err = viewService.GetViewTasks(saved.ID, tokyo, page)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"late", "late but done"}, names(result))
+	assert.NotEmpty(t, result.Next)
+	assert.Equal(t, entity.ViewCounts{Total: 4, Open: 3, Overdue: 1}, result.Counts)
+
+	_, err = viewService.GetViewTasks("someday", tokyo, page)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestViewService_GetViewTasksErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockViews := mocks.NewMockIViewRepo(ctrl)
+	mockTasks := mocks.NewMockIRepo(ctrl)
+	viewService := ViewService{Repo: mockViews, Tasks: mockTasks}
+	page := entity.PageRequest{Limit: entity.DefaultPageLimit, Sort: "id"}
+
+	// A stored query that no longer parses is reported like any invalid query
+	mockViews.EXPECT().GetViewById(3).Return(entity.View{ID: "3", Name: "Old", Query: "color:red"}, nil)
+	_, err := viewService.GetViewTasks("3", time.UTC, page)
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+
+	mockTasks.EXPECT().QueryTasks(gomock.Any(), page).Return(entity.TaskPage{Tasks: []entity.Task{}}, nil)
+	mockTasks.EXPECT().CountTasksByStatus(gomock.Any()).Return(nil, errors.New("count error"))
+	_, err = viewService.GetViewTasks("today", time.UTC, page)
+	assert.EqualError(t, err, "count error")
+}
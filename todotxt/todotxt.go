@@ -0,0 +1,157 @@
+// Package todotxt reads and writes the todo.txt format, one task per line: an x for a completed task, a
+// priority such as (A), completion and creation dates, and a description holding +project and @context words
+// and key:value tags.
+package todotxt
+
+import (
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of the dates of a line
+const dateLayout = "2006-01-02"
+
+// Task is a line of a todo.txt file. Text is its description without the projects, contexts and tags, which
+// are kept apart in the order they were written. Priority is a letter from A, the highest, to Z, or empty.
+// A completed task has no priority of its own in the format, so it is written as a pri tag.
+type Task struct {
+	Done      bool
+	Priority  string
+	Completed time.Time
+	Created   time.Time
+	Text      string
+	Projects  []string
+	Contexts  []string
+	Tags      []Tag
+}
+
+// Tag is a key:value word of a description, such as due:2026-11-02
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Parse reads a line. Every line is a task: what is not a completion mark, a priority or a date where the
+// format expects one is part of the description. Dates are days in UTC.
+func Parse(line string) Task {
+	var task Task
+	words := strings.Fields(line)
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Done = true
+		words = words[1:]
+		if date, ok := parseDate(words); ok {
+			task.Completed = date
+			words = words[1:]
+		}
+	} else if len(words) > 0 && isPriority(words[0]) {
+		task.Priority = words[0][1:2]
+		words = words[1:]
+	}
+	if date, ok := parseDate(words); ok {
+		task.Created = date
+		words = words[1:]
+	}
+
+	var text []string
+	for _, word := range words {
+		if project, ok := strings.CutPrefix(word, "+"); ok && project != "" {
+			task.Projects = append(task.Projects, project)
+		} else if context, ok := strings.CutPrefix(word, "@"); ok && context != "" {
+			task.Contexts = append(task.Contexts, context)
+		} else if tag, ok := parseTag(word); ok {
+			if task.Done && tag.Key == "pri" && task.Priority == "" && isLetter(tag.Value) {
+				task.Priority = tag.Value
+				continue
+			}
+			task.Tags = append(task.Tags, tag)
+		} else {
+			text = append(text, word)
+		}
+	}
+	task.Text = strings.Join(text, " ")
+	return task
+}
+
+// String formats the task as a line, with the projects, contexts and tags after the text
+func (t Task) String() string {
+	var words []string
+	if t.Done {
+		words = append(words, "x")
+		if !t.Completed.IsZero() {
+			words = append(words, t.Completed.Format(dateLayout))
+			// A creation date alone would be read as the completion date
+			if !t.Created.IsZero() {
+				words = append(words, t.Created.Format(dateLayout))
+			}
+		}
+	} else {
+		if t.Priority != "" {
+			words = append(words, "("+t.Priority+")")
+		}
+		if !t.Created.IsZero() {
+			words = append(words, t.Created.Format(dateLayout))
+		}
+	}
+
+	if t.Text != "" {
+		words = append(words, t.Text)
+	}
+	for _, project := range t.Projects {
+		words = append(words, "+"+project)
+	}
+	for _, context := range t.Contexts {
+		words = append(words, "@"+context)
+	}
+	for _, tag := range t.Tags {
+		words = append(words, tag.Key+":"+tag.Value)
+	}
+	if t.Done && t.Priority != "" {
+		words = append(words, "pri:"+t.Priority)
+	}
+	return strings.Join(words, " ")
+}
+
+// Tag returns the value of the first tag with the given key
+func (t Task) Tag(key string) (string, bool) {
+	for _, tag := range t.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// Word joins the words of a name into one, so it can be written as a project or a context
+func Word(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// parseDate reads the first word as a date
+func parseDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.Parse(dateLayout, words[0])
+	return date, err == nil
+}
+
+// parseTag reads a key:value word. Neither part may be empty or hold another colon, and a value starting
+// with // is the rest of a URL rather than a tag.
+func parseTag(word string) (Tag, bool) {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.Contains(value, ":") || strings.HasPrefix(value, "//") {
+		return Tag{}, false
+	}
+	return Tag{Key: key, Value: value}, true
+}
+
+// isPriority reports whether a word is a priority such as (A)
+func isPriority(word string) bool {
+	return len(word) == 3 && word[0] == '(' && word[2] == ')' && isLetter(word[1:2])
+}
+
+// isLetter reports whether s is one upper case letter
+func isLetter(s string) bool {
+	return len(s) == 1 && s[0] >= 'A' && s[0] <= 'Z'
+}
@@ -0,0 +1,95 @@
+package todotxt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	for _, test := range []struct {
+		line string
+		task Task
+	}{
+		{
+			"(A) 2026-10-01 Call Mom +Family @phone due:2026-11-02",
+			Task{Priority: "A", Created: day(2026, 10, 1), Text: "Call Mom", Projects: []string{"Family"}, Contexts: []string{"phone"}, Tags: []Tag{{"due", "2026-11-02"}}},
+		},
+		{
+			"x 2026-11-03 2026-10-01 Write report +work pri:B",
+			Task{Done: true, Priority: "B", Completed: day(2026, 11, 3), Created: day(2026, 10, 1), Text: "Write report", Projects: []string{"work"}},
+		},
+		{
+			"x Write report",
+			Task{Done: true, Text: "Write report"},
+		},
+		// Only a priority at the start counts, and only upper case letters
+		{
+			"Write (A) report",
+			Task{Text: "Write (A) report"},
+		},
+		{
+			"(a) Write report",
+			Task{Text: "(a) Write report"},
+		},
+		{
+			"xylophone lessons",
+			Task{Text: "xylophone lessons"},
+		},
+		// Lone marks, URLs and times are text
+		{
+			"Read https://example.com/notes at 10:30:00 + more @ home mail:me@example.com",
+			Task{Text: "Read https://example.com/notes at 10:30:00 + more @ home", Tags: []Tag{{"mail", "me@example.com"}}},
+		},
+		{
+			"  ",
+			Task{},
+		},
+	} {
+		assert.Equal(t, test.task, Parse(test.line), test.line)
+	}
+}
+
+func TestString(t *testing.T) {
+	for _, test := range []struct {
+		task Task
+		line string
+	}{
+		{
+			Task{Priority: "A", Created: day(2026, 10, 1), Text: "Call Mom", Projects: []string{"Family"}, Contexts: []string{"phone"}, Tags: []Tag{{"due", "2026-11-02"}}},
+			"(A) 2026-10-01 Call Mom +Family @phone due:2026-11-02",
+		},
+		{
+			Task{Done: true, Priority: "C", Completed: day(2026, 11, 3), Created: day(2026, 10, 1), Text: "Write report"},
+			"x 2026-11-03 2026-10-01 Write report pri:C",
+		},
+		// Without a completion date the creation date is left out, as it would be read as one
+		{
+			Task{Done: true, Created: day(2026, 10, 1), Text: "Write report"},
+			"x Write report",
+		},
+	} {
+		line := test.task.String()
+		assert.Equal(t, test.line, line)
+		assert.Equal(t, line, Parse(line).String())
+	}
+}
+
+func TestTag(t *testing.T) {
+	task := Parse("Write report due:2026-11-02 status:blocked due:2026-11-03")
+	due, ok := task.Tag("due")
+	assert.True(t, ok)
+	assert.Equal(t, "2026-11-02", due)
+	_, ok = task.Tag("rec")
+	assert.False(t, ok)
+}
+
+func TestWord(t *testing.T) {
+	assert.Equal(t, "Home_Office", Word(" Home  Office "))
+	assert.Equal(t, "work", Word("work"))
+}